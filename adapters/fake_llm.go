@@ -0,0 +1,99 @@
+package adapters
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// FakeLLM answers without touching the network. By default it echoes the last
+// user message, so the same request always gets the same response. Err and
+// FinishReason script failures and cut off replies, e.g. in tests.
+type FakeLLM struct {
+	Reply        func(req LLMRequest) string
+	Err          func(req LLMRequest) error
+	FinishReason func(req LLMRequest) LLMFinishReason
+}
+
+func NewFakeLLM(reply func(req LLMRequest) string) *FakeLLM {
+	return &FakeLLM{Reply: reply}
+}
+
+func (f *FakeLLM) Provider() string {
+	return LLMProviderFake
+}
+
+func (f *FakeLLM) Complete(ctx context.Context, req LLMRequest) (res LLMResponse, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if err = validateLLMRequest(req); err != nil {
+		return
+	}
+	if f.Err != nil {
+		if err = f.Err(req); err != nil {
+			return
+		}
+	}
+
+	var content string
+	if f.Reply != nil {
+		content = f.Reply(req)
+	} else {
+		for _, m := range req.Messages {
+			if m.Role == LLMRoleUser {
+				content = m.Content
+			}
+		}
+	}
+
+	var prompt strings.Builder
+	promptTokens := 0
+	for _, m := range req.Messages {
+		prompt.WriteString(string(m.Role))
+		prompt.WriteString(m.Content)
+		promptTokens += len(strings.Fields(m.Content))
+	}
+	sum := sha256.Sum256([]byte(req.Model + prompt.String()))
+	completionTokens := len(strings.Fields(content))
+
+	finishReason := LLMFinishReasonStop
+	if f.FinishReason != nil {
+		finishReason = f.FinishReason(req)
+	}
+
+	return LLMResponse{
+		Id:           "fake-" + hex.EncodeToString(sum[:8]),
+		Model:        req.Model,
+		Content:      content,
+		FinishReason: finishReason,
+		Usage: LLMUsage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+	}, nil
+}
+
+// CompleteStream sends the reply of Complete one word at a time
+func (f *FakeLLM) CompleteStream(ctx context.Context, req LLMRequest, onDelta func(delta string) error) (res LLMResponse, err error) {
+	res, err = f.Complete(ctx, req)
+	if err != nil {
+		return
+	}
+
+	for i, word := range strings.SplitAfter(res.Content, " ") {
+		if err = ctx.Err(); err != nil {
+			return res, err
+		}
+		if word == "" && i > 0 {
+			continue
+		}
+		if err = onDelta(word); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,202 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+const (
+	LLMProviderOpenAI     = "openai"
+	LLMProviderAzure      = "azure"
+	LLMProviderCompatible = "compatible"
+	LLMProviderFake       = "fake"
+
+	DefaultLLMModel = openai.GPT3Dot5Turbo16K
+)
+
+type LLMRole string
+
+const (
+	LLMRoleSystem    LLMRole = "system"
+	LLMRoleUser      LLMRole = "user"
+	LLMRoleAssistant LLMRole = "assistant"
+)
+
+type LLMFinishReason string
+
+const (
+	LLMFinishReasonStop          LLMFinishReason = "stop"
+	LLMFinishReasonLength        LLMFinishReason = "length"
+	LLMFinishReasonContentFilter LLMFinishReason = "content_filter"
+)
+
+var (
+	ErrUnknownLLMProvider   = errors.New("Unknown LLM provider")
+	ErrAzureEndpointEmpty   = errors.New("Azure OpenAI endpoint can't be empty")
+	ErrAzureAPIKeyEmpty     = errors.New("Azure OpenAI API key can't be empty")
+	ErrLLMBaseUrlEmpty      = errors.New("LLM base URL can't be empty")
+	ErrLLMEmptyResponse     = errors.New("LLM returned an empty response")
+	ErrLLMMessagesEmpty     = errors.New("LLM request must have at least one message")
+	ErrLLMRequestModelEmpty = errors.New("LLM request model can't be empty")
+)
+
+type LLMMessage struct {
+	Role    LLMRole `json:"role"`
+	Content string  `json:"content"`
+}
+
+type LLMRequest struct {
+	Model       string       `json:"model"`
+	Messages    []LLMMessage `json:"messages"`
+	MaxTokens   int          `json:"max_tokens"`
+	Temperature float32      `json:"temperature"`
+	TopP        float32      `json:"top_p"`
+
+	// Timeout bounds every attempt of the call when set, on top of the
+	// caller's context.
+	Timeout time.Duration `json:"-"`
+}
+
+type LLMUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type LLMResponse struct {
+	Id           string          `json:"id"`
+	Model        string          `json:"model"`
+	Content      string          `json:"content"`
+	FinishReason LLMFinishReason `json:"finish_reason"`
+	Usage        LLMUsage        `json:"usage"`
+}
+
+// LLM is a chat completion provider. Providers translate their own failures
+// into an *LLMStatusError whenever the upstream answered with an HTTP status,
+// the resilient adapter they're wrapped in turns them into an *LLMError.
+type LLM interface {
+	Provider() string
+	Complete(ctx context.Context, req LLMRequest) (LLMResponse, error)
+}
+
+// LLMStreamer is implemented by providers that can send a completion while
+// it's generated. onDelta gets every new piece of content, an error returned
+// from it stops the stream.
+type LLMStreamer interface {
+	CompleteStream(ctx context.Context, req LLMRequest, onDelta func(delta string) error) (LLMResponse, error)
+}
+
+// CompleteStream streams when the provider can, otherwise the whole completion
+// is handed to onDelta at once.
+func CompleteStream(ctx context.Context, llm LLM, req LLMRequest, onDelta func(delta string) error) (LLMResponse, error) {
+	if streamer, ok := llm.(LLMStreamer); ok {
+		return streamer.CompleteStream(ctx, req, onDelta)
+	}
+
+	res, err := llm.Complete(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	return res, onDelta(res.Content)
+}
+
+// EstimateTokens guesses the token count without a tokenizer at about 3.5
+// characters per token, which errs on the side of more tokens for the long
+// affixed words Indonesian is made of.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text)*2 + 6) / 7
+}
+
+// estimateLLMUsage stands in for providers that don't report usage, like
+// OpenAI when streaming.
+func estimateLLMUsage(req LLMRequest, content string) LLMUsage {
+	usage := LLMUsage{CompletionTokens: EstimateTokens(content)}
+	for _, m := range req.Messages {
+		usage.PromptTokens += EstimateTokens(m.Content)
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+
+	return usage
+}
+
+// LLMStatusError is returned when the provider answered with a non-2xx status.
+type LLMStatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *LLMStatusError) Error() string {
+	return fmt.Sprintf("LLM provider responded with status %d: %v", e.StatusCode, e.Err)
+}
+
+func (e *LLMStatusError) Unwrap() error {
+	return e.Err
+}
+
+type LLMConfig struct {
+	Provider string
+
+	OpenAIOrganizationId string
+	OpenAIAPIKey         string
+
+	AzureOpenAIEndpoint   string
+	AzureOpenAIAPIKey     string
+	AzureOpenAIAPIVersion string
+	AzureOpenAIDeployment string
+
+	BaseUrl string
+	APIKey  string
+
+	Resilience ResilienceConfig
+}
+
+// ConfigureLLMAdapter returns the configured provider wrapped in the
+// resilience layer, see NewResilientLLM.
+func ConfigureLLMAdapter(config LLMConfig) LLM {
+	return NewResilientLLM(configureLLMProvider(config), config.Resilience)
+}
+
+func configureLLMProvider(config LLMConfig) LLM {
+	provider := strings.ToLower(strings.TrimSpace(config.Provider))
+
+	switch provider {
+	case "", LLMProviderOpenAI:
+		return ConfigureOpenAIAdapter(config.OpenAIOrganizationId, config.OpenAIAPIKey)
+	case LLMProviderAzure:
+		return ConfigureAzureOpenAIAdapter(
+			config.AzureOpenAIEndpoint,
+			config.AzureOpenAIAPIKey,
+			config.AzureOpenAIAPIVersion,
+			config.AzureOpenAIDeployment,
+		)
+	case LLMProviderCompatible:
+		return ConfigureOpenAICompatibleAdapter(config.BaseUrl, config.APIKey)
+	case LLMProviderFake:
+		log.Warn().Msg("Using the fake LLM adapter, generated texts are not real")
+		return NewFakeLLM(nil)
+	default:
+		log.Fatal().Err(ErrUnknownLLMProvider).Str("provider", config.Provider).Msg("Failed to configure LLM adapter")
+	}
+
+	return nil
+}
+
+func validateLLMRequest(req LLMRequest) error {
+	if strings.TrimSpace(req.Model) == "" {
+		return ErrLLMRequestModelEmpty
+	}
+	if len(req.Messages) == 0 {
+		return ErrLLMMessagesEmpty
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The error taxonomy of LLM calls. Every failed call through the resilient
+// adapter matches exactly one of these with errors.Is, whatever the provider.
+var (
+	ErrLLMUnauthorized = errors.New("Invalid LLM provider API key")
+	ErrLLMRateLimited  = errors.New("The LLM provider has rate limited us due to too many requests. Please try again later")
+	ErrLLMUnavailable  = errors.New("The LLM provider is currently unavailable. Please try again later")
+	ErrLLMTimeout      = errors.New("The LLM provider took too long to respond. Please try again later")
+	ErrLLMRejected     = errors.New("The LLM provider rejected the request")
+
+	ErrLLMCircuitOpen = errors.New("LLM circuit breaker is open")
+)
+
+// LLMError puts a provider failure into one of the taxonomy kinds. Its message
+// is the kind's, so it's safe to show to clients, while the cause stays
+// reachable for logs and errors.As.
+type LLMError struct {
+	Kind       error
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *LLMError) Error() string {
+	return e.Kind.Error()
+}
+
+// Detail adds the cause to the message, for logs and records rather than
+// clients.
+func (e *LLMError) Detail() string {
+	if e.Err == nil {
+		return e.Kind.Error()
+	}
+
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *LLMError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+// IsRetryable is true for failures that may go away on their own
+func (e *LLMError) IsRetryable() bool {
+	return e.Kind == ErrLLMRateLimited || e.Kind == ErrLLMUnavailable || e.Kind == ErrLLMTimeout
+}
+
+// LLMErrorStatus is the status clients are answered with when an LLM call
+// failed with err, false when err isn't a failed LLM call.
+func LLMErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, ErrLLMUnauthorized):
+		return http.StatusUnauthorized, true
+	case errors.Is(err, ErrLLMRateLimited):
+		return http.StatusTooManyRequests, true
+	case errors.Is(err, ErrLLMUnavailable):
+		return http.StatusServiceUnavailable, true
+	case errors.Is(err, ErrLLMTimeout):
+		return http.StatusGatewayTimeout, true
+	case errors.Is(err, ErrLLMRejected):
+		return http.StatusBadGateway, true
+	}
+
+	return 0, false
+}
+
+// classifyLLMError returns nil for errors that aren't the provider's fault,
+// which is only the caller giving up on the call or failing to take a stream.
+func classifyLLMError(err error) *LLMError {
+	var llmErr *LLMError
+	if errors.As(err, &llmErr) {
+		return llmErr
+	}
+	var callerErr *callerError
+	if errors.Is(err, context.Canceled) || errors.As(err, &callerErr) {
+		return nil
+	}
+
+	var statusErr *LLMStatusError
+	if errors.As(err, &statusErr) {
+		e := &LLMError{StatusCode: statusErr.StatusCode, RetryAfter: statusErr.RetryAfter, Err: err}
+
+		switch code := statusErr.StatusCode; {
+		case code == http.StatusUnauthorized, code == http.StatusForbidden:
+			e.Kind = ErrLLMUnauthorized
+		case code == http.StatusTooManyRequests:
+			e.Kind = ErrLLMRateLimited
+		case code == http.StatusRequestTimeout, code == http.StatusGatewayTimeout:
+			e.Kind = ErrLLMTimeout
+		case code >= http.StatusInternalServerError:
+			e.Kind = ErrLLMUnavailable
+		default:
+			e.Kind = ErrLLMRejected
+		}
+
+		return e
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &LLMError{Kind: ErrLLMTimeout, Err: err}
+	case errors.Is(err, ErrLLMMessagesEmpty), errors.Is(err, ErrLLMRequestModelEmpty):
+		return &LLMError{Kind: ErrLLMRejected, Err: err}
+	}
+
+	// Whatever is left never got an answer from the provider, like refused
+	// connections or empty responses
+	return &LLMError{Kind: ErrLLMUnavailable, Err: err}
+}
+
+// parseRetryAfter reads the delay in seconds or as an HTTP date, and Azure's
+// retry-after-ms which is more precise when both are sent.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if ms, err := strconv.ParseInt(strings.TrimSpace(header.Get("Retry-After-Ms")), 10, 64); err == nil && ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,197 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+type LLMTask string
+
+const (
+	LLMTaskArticleText    LLMTask = "article_text"
+	LLMTaskArticleSummary LLMTask = "article_summary"
+	LLMTaskSimplify       LLMTask = "simplify"
+	LLMTaskExplain        LLMTask = "explain"
+	LLMTaskChat           LLMTask = "chat"
+)
+
+const (
+	maxLLMTaskMaxTokens = 128000
+	maxLLMTaskTimeout   = 10 * time.Minute
+)
+
+var ErrInvalidLLMTaskSettings = errors.New("Invalid LLM task settings")
+
+type LLMTaskSettings struct {
+	Model          string  `json:"model"`
+	MaxTokens      int     `json:"max_tokens"`
+	Temperature    float32 `json:"temperature"`
+	TopP           float32 `json:"top_p"`
+	TimeoutSeconds int     `json:"timeout_seconds"`
+}
+
+// LLMTaskSettingsOverride lets superadmins try other settings for a single
+// request, unset fields keep the configured value.
+type LLMTaskSettingsOverride struct {
+	Model          null.String `json:"model"`
+	MaxTokens      null.Int    `json:"max_tokens"`
+	Temperature    null.Float  `json:"temperature"`
+	TopP           null.Float  `json:"top_p"`
+	TimeoutSeconds null.Int    `json:"timeout_seconds"`
+}
+
+var (
+	defaultLLMTaskSettings = map[LLMTask]LLMTaskSettings{
+		LLMTaskArticleText:    {Model: DefaultLLMModel, MaxTokens: 8000, Temperature: 0.8, TimeoutSeconds: 180},
+		LLMTaskArticleSummary: {Model: DefaultLLMModel, MaxTokens: 400, Temperature: 0.3, TimeoutSeconds: 60},
+		LLMTaskSimplify:       {Model: DefaultLLMModel, MaxTokens: 5000, Temperature: 0.8, TimeoutSeconds: 60},
+		LLMTaskExplain:        {Model: DefaultLLMModel, MaxTokens: 2000, Temperature: 0.8, TimeoutSeconds: 60},
+		LLMTaskChat:           {Model: DefaultLLMModel, MaxTokens: 1000, Temperature: 0.7, TimeoutSeconds: 90},
+	}
+
+	llmTaskSettings = defaultLLMTaskSettings
+)
+
+// ConfigureLLMTaskSettings applies the configured spec of every task on top of
+// its defaults. A spec is a comma separated list of key=value pairs, e.g.
+// "model=gpt-4o-mini,max_tokens=4000,temperature=0.5,top_p=1,timeout=90s".
+// A non-empty defaultModel replaces the built-in model of every task that
+// doesn't name its own.
+func ConfigureLLMTaskSettings(defaultModel string, specs map[LLMTask]string) {
+	for task := range specs {
+		if _, ok := defaultLLMTaskSettings[task]; !ok {
+			log.Fatal().Err(ErrInvalidLLMTaskSettings).Str("task", string(task)).Msg("Failed to configure LLM task settings, unknown task")
+		}
+	}
+
+	defaultModel = strings.TrimSpace(defaultModel)
+	settings := make(map[LLMTask]LLMTaskSettings, len(defaultLLMTaskSettings))
+
+	for task, defaults := range defaultLLMTaskSettings {
+		if defaultModel != "" {
+			defaults.Model = defaultModel
+		}
+
+		s, err := parseLLMTaskSettings(defaults, specs[task])
+		if err == nil {
+			err = s.Validate()
+		}
+		if err != nil {
+			log.Fatal().Err(err).Str("task", string(task)).Msg("Failed to configure LLM task settings")
+		}
+
+		settings[task] = s
+	}
+
+	llmTaskSettings = settings
+}
+
+func LLMTaskSettingsFor(task LLMTask) LLMTaskSettings {
+	return llmTaskSettings[task]
+}
+
+func parseLLMTaskSettings(s LLMTaskSettings, spec string) (LLMTaskSettings, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return s, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return s, fmt.Errorf("%w: %q is not a key=value pair", ErrInvalidLLMTaskSettings, pair)
+		}
+
+		var err error
+		switch key {
+		case "model":
+			s.Model = value
+		case "max_tokens":
+			s.MaxTokens, err = strconv.Atoi(value)
+		case "temperature":
+			var f float64
+			f, err = strconv.ParseFloat(value, 32)
+			s.Temperature = float32(f)
+		case "top_p":
+			var f float64
+			f, err = strconv.ParseFloat(value, 32)
+			s.TopP = float32(f)
+		case "timeout":
+			var d time.Duration
+			d, err = time.ParseDuration(value)
+			s.TimeoutSeconds = int(d / time.Second)
+		default:
+			return s, fmt.Errorf("%w: unknown key %q", ErrInvalidLLMTaskSettings, key)
+		}
+		if err != nil {
+			return s, fmt.Errorf("%w: invalid %s %q", ErrInvalidLLMTaskSettings, key, value)
+		}
+	}
+
+	return s, nil
+}
+
+// Validate keeps settings within what every provider accepts. A zero top_p is
+// left out of the request so the provider default applies.
+func (s LLMTaskSettings) Validate() error {
+	switch {
+	case strings.TrimSpace(s.Model) == "":
+		return fmt.Errorf("%w: model can't be empty", ErrInvalidLLMTaskSettings)
+	case s.MaxTokens <= 0 || s.MaxTokens > maxLLMTaskMaxTokens:
+		return fmt.Errorf("%w: max_tokens must be between 1 and %d", ErrInvalidLLMTaskSettings, maxLLMTaskMaxTokens)
+	case s.Temperature < 0 || s.Temperature > 2:
+		return fmt.Errorf("%w: temperature must be between 0 and 2", ErrInvalidLLMTaskSettings)
+	case s.TopP < 0 || s.TopP > 1:
+		return fmt.Errorf("%w: top_p must be between 0 and 1", ErrInvalidLLMTaskSettings)
+	case s.TimeoutSeconds <= 0 || s.Timeout() > maxLLMTaskTimeout:
+		return fmt.Errorf("%w: timeout must be between 1s and %s", ErrInvalidLLMTaskSettings, maxLLMTaskTimeout)
+	}
+
+	return nil
+}
+
+func (s LLMTaskSettings) Timeout() time.Duration {
+	return time.Duration(s.TimeoutSeconds) * time.Second
+}
+
+func (s LLMTaskSettings) Override(o *LLMTaskSettingsOverride) (LLMTaskSettings, error) {
+	if o == nil {
+		return s, nil
+	}
+
+	if o.Model.Valid {
+		s.Model = strings.TrimSpace(o.Model.String)
+	}
+	if o.MaxTokens.Valid {
+		s.MaxTokens = int(o.MaxTokens.Int64)
+	}
+	if o.Temperature.Valid {
+		s.Temperature = float32(o.Temperature.Float64)
+	}
+	if o.TopP.Valid {
+		s.TopP = float32(o.TopP.Float64)
+	}
+	if o.TimeoutSeconds.Valid {
+		s.TimeoutSeconds = int(o.TimeoutSeconds.Int64)
+	}
+
+	return s, s.Validate()
+}
+
+func (s LLMTaskSettings) Request(messages ...LLMMessage) LLMRequest {
+	return LLMRequest{
+		Model:       s.Model,
+		Messages:    messages,
+		MaxTokens:   s.MaxTokens,
+		Temperature: s.Temperature,
+		TopP:        s.TopP,
+		Timeout:     s.Timeout(),
+	}
+}
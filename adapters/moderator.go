@@ -0,0 +1,88 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ModeratorProviderNone   = "none"
+	ModeratorProviderOpenAI = "openai"
+	ModeratorProviderFake   = "fake"
+
+	// Moderation sits in front of every reply, it can't hold one up for long
+	defaultModerationTimeout = 15 * time.Second
+)
+
+var ErrUnknownModeratorProvider = errors.New("Unknown moderation provider")
+
+// ModerationResult lists the provider's categories the text was flagged for
+type ModerationResult struct {
+	Flagged    bool               `json:"flagged"`
+	Categories []string           `json:"categories"`
+	Scores     map[string]float64 `json:"scores"`
+}
+
+type Moderator interface {
+	Provider() string
+	Moderate(ctx context.Context, text string) (ModerationResult, error)
+}
+
+type ModeratorConfig struct {
+	Provider string
+	Model    string
+
+	OpenAIOrganizationId string
+	OpenAIAPIKey         string
+}
+
+// ConfigureModerator returns nil when no provider is configured, moderation
+// then only uses its local rules.
+func ConfigureModerator(config ModeratorConfig) Moderator {
+	provider := strings.ToLower(strings.TrimSpace(config.Provider))
+
+	switch provider {
+	case "", ModeratorProviderNone:
+		log.Warn().Msg("No moderation provider configured, only local moderation rules are used")
+		return nil
+	case ModeratorProviderOpenAI:
+		return ConfigureOpenAIModerator(config.OpenAIOrganizationId, config.OpenAIAPIKey, config.Model)
+	case ModeratorProviderFake:
+		log.Warn().Msg("Using the fake moderation provider, nothing is flagged by it")
+		return NewFakeModerator(nil)
+	default:
+		log.Fatal().Err(ErrUnknownModeratorProvider).Str("provider", config.Provider).Msg("Failed to configure moderation provider")
+	}
+
+	return nil
+}
+
+// FakeModerator flags nothing unless Flag says otherwise
+type FakeModerator struct {
+	Flag func(text string) []string
+}
+
+func NewFakeModerator(flag func(text string) []string) *FakeModerator {
+	return &FakeModerator{Flag: flag}
+}
+
+func (f *FakeModerator) Provider() string {
+	return ModeratorProviderFake
+}
+
+func (f *FakeModerator) Moderate(ctx context.Context, text string) (res ModerationResult, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	if f.Flag != nil {
+		res.Categories = f.Flag(text)
+	}
+	res.Flagged = len(res.Categories) != 0
+
+	return res, nil
+}
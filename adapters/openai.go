@@ -1,7 +1,12 @@
 package adapters
 
 import (
+	"context"
 	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	openai "github.com/sashabaranov/go-openai"
@@ -12,7 +17,41 @@ var (
 	ErrOpenAIAPIKeyEmpty         = errors.New("OpenAI API key can't be empty")
 )
 
-func ConfigureOpenAIAdapter(organizationId, apiKey string) *openai.Client {
+type retryAfterCtxKey struct{}
+
+// retryAfterTransport keeps the Retry-After of a response, which the OpenAI
+// client drops, in a holder the request context carries.
+type retryAfterTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	if retryAfter, ok := req.Context().Value(retryAfterCtxKey{}).(*time.Duration); ok {
+		*retryAfter = parseRetryAfter(res.Header, time.Now())
+	}
+
+	return res, nil
+}
+
+func newOpenAIClient(config openai.ClientConfig) *openai.Client {
+	config.HTTPClient = &http.Client{Transport: &retryAfterTransport{base: http.DefaultTransport}}
+
+	return openai.NewClientWithConfig(config)
+}
+
+// openAILLM serves OpenAI, Azure OpenAI and any server that speaks the OpenAI
+// chat completion API, they only differ in the client configuration.
+type openAILLM struct {
+	provider string
+	client   *openai.Client
+}
+
+func ConfigureOpenAIAdapter(organizationId, apiKey string) LLM {
 	if organizationId == "" {
 		log.Fatal().Err(ErrOpenAIOrganizationIdEmpty).Msg("Failed to configure OpenAI adapter")
 	}
@@ -23,5 +62,163 @@ func ConfigureOpenAIAdapter(organizationId, apiKey string) *openai.Client {
 	config := openai.DefaultConfig(apiKey)
 	config.OrgID = organizationId
 
-	return openai.NewClientWithConfig(config)
+	return &openAILLM{provider: LLMProviderOpenAI, client: newOpenAIClient(config)}
+}
+
+// ConfigureAzureOpenAIAdapter sends every model to the given deployment when
+// one is set, otherwise the deployment name is derived from the model name.
+func ConfigureAzureOpenAIAdapter(endpoint, apiKey, apiVersion, deployment string) LLM {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		log.Fatal().Err(ErrAzureEndpointEmpty).Msg("Failed to configure Azure OpenAI adapter")
+	}
+	if apiKey == "" {
+		log.Fatal().Err(ErrAzureAPIKeyEmpty).Msg("Failed to configure Azure OpenAI adapter")
+	}
+
+	config := openai.DefaultAzureConfig(apiKey, endpoint)
+	if apiVersion = strings.TrimSpace(apiVersion); apiVersion != "" {
+		config.APIVersion = apiVersion
+	}
+	if deployment = strings.TrimSpace(deployment); deployment != "" {
+		config.AzureModelMapperFunc = func(string) string {
+			return deployment
+		}
+	}
+
+	return &openAILLM{provider: LLMProviderAzure, client: newOpenAIClient(config)}
+}
+
+// ConfigureOpenAICompatibleAdapter targets self-hosted servers, which often
+// don't need an API key at all.
+func ConfigureOpenAICompatibleAdapter(baseUrl, apiKey string) LLM {
+	baseUrl = strings.TrimRight(strings.TrimSpace(baseUrl), "/")
+	if baseUrl == "" {
+		log.Fatal().Err(ErrLLMBaseUrlEmpty).Msg("Failed to configure OpenAI compatible adapter")
+	}
+
+	config := openai.DefaultConfig(apiKey)
+	config.BaseURL = baseUrl
+
+	return &openAILLM{provider: LLMProviderCompatible, client: newOpenAIClient(config)}
+}
+
+func (l *openAILLM) Provider() string {
+	return l.provider
+}
+
+func (l *openAILLM) Complete(ctx context.Context, req LLMRequest) (res LLMResponse, err error) {
+	if err = validateLLMRequest(req); err != nil {
+		return
+	}
+
+	var retryAfter time.Duration
+	ctx = context.WithValue(ctx, retryAfterCtxKey{}, &retryAfter)
+
+	completion, err := l.client.CreateChatCompletion(ctx, toOpenAIChatCompletionRequest(req))
+	if err != nil {
+		return res, toLLMError(err, retryAfter)
+	}
+	if len(completion.Choices) == 0 {
+		return res, ErrLLMEmptyResponse
+	}
+
+	return LLMResponse{
+		Id:           completion.ID,
+		Model:        completion.Model,
+		Content:      completion.Choices[0].Message.Content,
+		FinishReason: LLMFinishReason(completion.Choices[0].FinishReason),
+		Usage: LLMUsage{
+			PromptTokens:     completion.Usage.PromptTokens,
+			CompletionTokens: completion.Usage.CompletionTokens,
+			TotalTokens:      completion.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// CompleteStream estimates the usage since OpenAI doesn't report it for
+// streamed completions.
+func (l *openAILLM) CompleteStream(ctx context.Context, req LLMRequest, onDelta func(delta string) error) (res LLMResponse, err error) {
+	if err = validateLLMRequest(req); err != nil {
+		return
+	}
+
+	var retryAfter time.Duration
+	ctx = context.WithValue(ctx, retryAfterCtxKey{}, &retryAfter)
+
+	completionReq := toOpenAIChatCompletionRequest(req)
+	completionReq.Stream = true
+
+	stream, err := l.client.CreateChatCompletionStream(ctx, completionReq)
+	if err != nil {
+		return res, toLLMError(err, retryAfter)
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return res, toLLMError(err, retryAfter)
+		}
+
+		res.Id, res.Model = chunk.ID, chunk.Model
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		if reason := chunk.Choices[0].FinishReason; reason != "" {
+			res.FinishReason = LLMFinishReason(reason)
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		if delta == "" {
+			continue
+		}
+
+		content.WriteString(delta)
+		if err = onDelta(delta); err != nil {
+			return res, err
+		}
+	}
+
+	if content.Len() == 0 {
+		return res, ErrLLMEmptyResponse
+	}
+
+	res.Content = content.String()
+	res.Usage = estimateLLMUsage(req, res.Content)
+
+	return res, nil
+}
+
+func toOpenAIChatCompletionRequest(req LLMRequest) openai.ChatCompletionRequest {
+	messages := make([]openai.ChatCompletionMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = openai.ChatCompletionMessage{Role: string(m.Role), Content: m.Content}
+	}
+
+	return openai.ChatCompletionRequest{
+		Model:       req.Model,
+		Messages:    messages,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+}
+
+func toLLMError(err error, retryAfter time.Duration) error {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return &LLMStatusError{StatusCode: apiErr.HTTPStatusCode, RetryAfter: retryAfter, Err: err}
+	}
+
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) && reqErr.HTTPStatusCode != 0 {
+		return &LLMStatusError{StatusCode: reqErr.HTTPStatusCode, RetryAfter: retryAfter, Err: err}
+	}
+
+	return err
 }
@@ -0,0 +1,79 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+var ErrModerationEmptyResponse = errors.New("Moderation provider returned no result")
+
+type openAIModerator struct {
+	client *openai.Client
+	model  string
+}
+
+func ConfigureOpenAIModerator(organizationId, apiKey, model string) Moderator {
+	if organizationId == "" {
+		log.Fatal().Err(ErrOpenAIOrganizationIdEmpty).Msg("Failed to configure OpenAI moderator")
+	}
+	if apiKey == "" {
+		log.Fatal().Err(ErrOpenAIAPIKeyEmpty).Msg("Failed to configure OpenAI moderator")
+	}
+
+	config := openai.DefaultConfig(apiKey)
+	config.OrgID = organizationId
+
+	return &openAIModerator{client: newOpenAIClient(config), model: model}
+}
+
+func (m *openAIModerator) Provider() string {
+	return ModeratorProviderOpenAI
+}
+
+func (m *openAIModerator) Moderate(ctx context.Context, text string) (res ModerationResult, err error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultModerationTimeout)
+	defer cancel()
+
+	var retryAfter time.Duration
+	ctx = context.WithValue(ctx, retryAfterCtxKey{}, &retryAfter)
+
+	moderation, err := m.client.Moderations(ctx, openai.ModerationRequest{Input: text, Model: m.model})
+	if err != nil {
+		if llmErr := classifyLLMError(toLLMError(err, retryAfter)); llmErr != nil {
+			return res, llmErr
+		}
+		return res, err
+	}
+	if len(moderation.Results) == 0 {
+		return res, ErrModerationEmptyResponse
+	}
+
+	result := moderation.Results[0]
+	categories := []struct {
+		name    string
+		flagged bool
+		score   float32
+	}{
+		{"hate", result.Categories.Hate, result.CategoryScores.Hate},
+		{"hate/threatening", result.Categories.HateThreatening, result.CategoryScores.HateThreatening},
+		{"self-harm", result.Categories.SelfHarm, result.CategoryScores.SelfHarm},
+		{"sexual", result.Categories.Sexual, result.CategoryScores.Sexual},
+		{"sexual/minors", result.Categories.SexualMinors, result.CategoryScores.SexualMinors},
+		{"violence", result.Categories.Violence, result.CategoryScores.Violence},
+		{"violence/graphic", result.Categories.ViolenceGraphic, result.CategoryScores.ViolenceGraphic},
+	}
+
+	res = ModerationResult{Flagged: result.Flagged, Categories: []string{}, Scores: make(map[string]float64, len(categories))}
+	for _, c := range categories {
+		if c.flagged {
+			res.Categories = append(res.Categories, c.name)
+		}
+		res.Scores[c.name] = float64(c.score)
+	}
+
+	return res, nil
+}
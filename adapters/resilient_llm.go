@@ -0,0 +1,277 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type ResilienceConfig struct {
+	// MaxAttempts counts the first try, 1 disables retries
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+
+	// The breaker opens after this many consecutive unavailable or timed out
+	// attempts and lets a single probe through once the cooldown passed
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+var defaultResilienceConfig = ResilienceConfig{
+	MaxAttempts:      3,
+	BaseDelay:        500 * time.Millisecond,
+	MaxDelay:         10 * time.Second,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+}
+
+// resilientLLM wraps a provider with per attempt deadlines, retries with
+// exponential backoff and jitter, and a circuit breaker. Failures come out as
+// an *LLMError so callers only deal with one error taxonomy.
+type resilientLLM struct {
+	llm     LLM
+	config  ResilienceConfig
+	breaker *circuitBreaker
+}
+
+// NewResilientLLM uses the defaults for every unset field of config
+func NewResilientLLM(llm LLM, config ResilienceConfig) LLM {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultResilienceConfig.MaxAttempts
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = defaultResilienceConfig.BaseDelay
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = defaultResilienceConfig.MaxDelay
+	}
+	if config.BreakerThreshold <= 0 {
+		config.BreakerThreshold = defaultResilienceConfig.BreakerThreshold
+	}
+	if config.BreakerCooldown <= 0 {
+		config.BreakerCooldown = defaultResilienceConfig.BreakerCooldown
+	}
+
+	return &resilientLLM{
+		llm:     llm,
+		config:  config,
+		breaker: &circuitBreaker{provider: llm.Provider(), threshold: config.BreakerThreshold, cooldown: config.BreakerCooldown},
+	}
+}
+
+func (l *resilientLLM) Provider() string {
+	return l.llm.Provider()
+}
+
+func (l *resilientLLM) Complete(ctx context.Context, req LLMRequest) (LLMResponse, error) {
+	return l.complete(ctx, req, func(ctx context.Context) (LLMResponse, error) {
+		return l.llm.Complete(ctx, req)
+	}, nil)
+}
+
+// CompleteStream only retries while nothing was streamed yet, since a retry
+// after that would send the beginning of the answer twice.
+func (l *resilientLLM) CompleteStream(ctx context.Context, req LLMRequest, onDelta func(delta string) error) (LLMResponse, error) {
+	streamed := false
+
+	res, err := l.complete(ctx, req, func(ctx context.Context) (LLMResponse, error) {
+		return CompleteStream(ctx, l.llm, req, func(delta string) error {
+			streamed = true
+			if err := onDelta(delta); err != nil {
+				return &callerError{err: err}
+			}
+
+			return nil
+		})
+	}, func() bool { return !streamed })
+
+	var callerErr *callerError
+	if errors.As(err, &callerErr) {
+		return res, callerErr.err
+	}
+
+	return res, err
+}
+
+// callerError marks a failure on the caller's side of a stream, like the
+// client hanging up, which says nothing about the provider.
+type callerError struct {
+	err error
+}
+
+func (e *callerError) Error() string {
+	return e.err.Error()
+}
+
+func (e *callerError) Unwrap() error {
+	return e.err
+}
+
+// complete runs call until it succeeds or the failure isn't worth another
+// attempt, canRetry can veto retries when it's set.
+func (l *resilientLLM) complete(ctx context.Context, req LLMRequest, call func(ctx context.Context) (LLMResponse, error), canRetry func() bool) (res LLMResponse, err error) {
+	attempt := 1
+	defer func() {
+		if llmErr, ok := err.(*LLMError); ok {
+			log.Warn().Fields(map[string]any{
+				"provider": l.llm.Provider(),
+				"model":    req.Model,
+				"attempts": attempt,
+				"error":    llmErr.Detail(),
+			}).Msg("LLM request failed")
+		}
+	}()
+
+	for ; ; attempt++ {
+		if !l.breaker.allow(time.Now()) {
+			return res, &LLMError{Kind: ErrLLMUnavailable, Err: ErrLLMCircuitOpen}
+		}
+
+		res, err = l.attempt(ctx, req, call)
+		if err == nil {
+			l.breaker.report(nil, time.Now())
+			return res, nil
+		}
+
+		llmErr := classifyLLMError(err)
+		if llmErr == nil {
+			l.breaker.release()
+			return res, err
+		}
+
+		l.breaker.report(llmErr, time.Now())
+		if !llmErr.IsRetryable() || attempt >= l.config.MaxAttempts || ctx.Err() != nil || (canRetry != nil && !canRetry()) {
+			return res, llmErr
+		}
+
+		// A provider asking for a longer pause than we'd ever back off is
+		// better answered right away than by holding the request
+		if llmErr.RetryAfter > l.config.MaxDelay {
+			return res, llmErr
+		}
+
+		delay := l.backoff(attempt, llmErr.RetryAfter)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return res, llmErr
+		}
+
+		log.Warn().Err(llmErr.Err).Fields(map[string]any{
+			"provider": l.llm.Provider(),
+			"model":    req.Model,
+			"attempt":  attempt,
+			"delay":    delay.String(),
+		}).Msg("Retrying LLM request")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return res, llmErr
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt bounds a single try by the request timeout. Running out of it while
+// the caller is still waiting is a provider timeout, not a cancellation.
+func (l *resilientLLM) attempt(ctx context.Context, req LLMRequest, call func(ctx context.Context) (LLMResponse, error)) (res LLMResponse, err error) {
+	attemptCtx := ctx
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+
+	res, err = call(attemptCtx)
+	if err != nil && attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return res, fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
+	}
+
+	return res, err
+}
+
+// backoff doubles the delay on every attempt up to the maximum and picks a
+// random point in its upper half, so callers that failed together don't all
+// come back together. Retry-After wins when the provider sent it.
+func (l *resilientLLM) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	delay := l.config.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > l.config.MaxDelay {
+		delay = l.config.MaxDelay
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+type circuitBreaker struct {
+	mu        sync.Mutex
+	provider  string
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+// allow lets everything through while closed, nothing during the cooldown and
+// a single probe once it passed.
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if now.Sub(b.openedAt) < b.cooldown || b.probing {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+// report only counts the provider being down or too slow. Any answer, even a
+// rate limit or a rejection, proves it's up again.
+func (b *circuitBreaker) report(err *LLMError, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil || (err.Kind != ErrLLMUnavailable && err.Kind != ErrLLMTimeout) {
+		if !b.openedAt.IsZero() {
+			log.Info().Str("provider", b.provider).Msg("LLM circuit breaker closed")
+		}
+
+		b.failures, b.openedAt, b.probing = 0, time.Time{}, false
+		return
+	}
+
+	b.failures++
+	if b.probing || (b.openedAt.IsZero() && b.failures >= b.threshold) {
+		log.Warn().Fields(map[string]any{
+			"provider": b.provider,
+			"failures": b.failures,
+			"cooldown": b.cooldown.String(),
+		}).Msg("LLM circuit breaker opened")
+
+		b.openedAt, b.probing = now, false
+	}
+}
+
+// release gives the probe back when the caller gave up before the provider
+// answered, which says nothing about the provider.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
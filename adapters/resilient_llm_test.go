@@ -0,0 +1,192 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var testLLMRequest = LLMRequest{
+	Model:    DefaultLLMModel,
+	Messages: []LLMMessage{{Role: LLMRoleUser, Content: "Halo"}},
+}
+
+// scriptedLLM fails the calls of the fake with errs in order and answers
+// every call after them.
+func scriptedLLM(errs ...error) (llm *FakeLLM, calls *int) {
+	calls = new(int)
+	llm = NewFakeLLM(nil)
+	llm.Err = func(req LLMRequest) error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+
+		return nil
+	}
+
+	return llm, calls
+}
+
+func statusError(code int, retryAfter time.Duration) error {
+	return &LLMStatusError{StatusCode: code, RetryAfter: retryAfter, Err: errors.New(http.StatusText(code))}
+}
+
+func TestResilientLLMRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantKind  error
+	}{
+		{
+			name:      "answers right away",
+			wantCalls: 1,
+		},
+		{
+			name:      "retries until the provider is back",
+			errs:      []error{statusError(http.StatusServiceUnavailable, 0), statusError(http.StatusBadGateway, 0)},
+			wantCalls: 3,
+		},
+		{
+			name:      "retries timeouts",
+			errs:      []error{statusError(http.StatusGatewayTimeout, 0)},
+			wantCalls: 2,
+		},
+		{
+			name:      "gives up after the last attempt",
+			errs:      []error{statusError(http.StatusInternalServerError, 0), statusError(http.StatusInternalServerError, 0), statusError(http.StatusInternalServerError, 0)},
+			wantCalls: 3,
+			wantKind:  ErrLLMUnavailable,
+		},
+		{
+			name:      "doesn't retry rejected requests",
+			errs:      []error{statusError(http.StatusBadRequest, 0)},
+			wantCalls: 1,
+			wantKind:  ErrLLMRejected,
+		},
+		{
+			name:      "doesn't retry unauthorized requests",
+			errs:      []error{statusError(http.StatusUnauthorized, 0)},
+			wantCalls: 1,
+			wantKind:  ErrLLMUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, calls := scriptedLLM(tt.errs...)
+			llm := NewResilientLLM(fake, ResilienceConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+
+			_, err := llm.Complete(context.Background(), testLLMRequest)
+			if *calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", *calls, tt.wantCalls)
+			}
+			if tt.wantKind == nil {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+
+			var llmErr *LLMError
+			if !errors.As(err, &llmErr) || !errors.Is(err, tt.wantKind) {
+				t.Fatalf("err = %v, want an *LLMError of kind %v", err, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestResilientLLMRetryAfter(t *testing.T) {
+	tests := []struct {
+		name        string
+		retryAfter  time.Duration
+		wantCalls   int
+		wantWait    time.Duration
+		wantLimited bool
+	}{
+		{
+			name:       "waits as long as the provider asked",
+			retryAfter: 30 * time.Millisecond,
+			wantCalls:  2,
+			wantWait:   30 * time.Millisecond,
+		},
+		{
+			name:        "gives up when asked to wait longer than the maximum delay",
+			retryAfter:  time.Minute,
+			wantCalls:   1,
+			wantLimited: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, calls := scriptedLLM(statusError(http.StatusTooManyRequests, tt.retryAfter))
+			llm := NewResilientLLM(fake, ResilienceConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+
+			startedAt := time.Now()
+			_, err := llm.Complete(context.Background(), testLLMRequest)
+			waited := time.Since(startedAt)
+
+			if *calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", *calls, tt.wantCalls)
+			}
+			if waited < tt.wantWait {
+				t.Errorf("waited %s, want at least %s", waited, tt.wantWait)
+			}
+			if tt.wantLimited != errors.Is(err, ErrLLMRateLimited) {
+				t.Errorf("err = %v, want rate limited %t", err, tt.wantLimited)
+			}
+		})
+	}
+}
+
+func TestResilientLLMBreaker(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+
+	unavailable := statusError(http.StatusServiceUnavailable, 0)
+	fake, calls := scriptedLLM(unavailable, unavailable, unavailable)
+	llm := NewResilientLLM(fake, ResilienceConfig{MaxAttempts: 1, BreakerThreshold: 2, BreakerCooldown: cooldown})
+
+	complete := func() error {
+		_, err := llm.Complete(context.Background(), testLLMRequest)
+		return err
+	}
+
+	// Closed, every failure reaches the provider until the threshold
+	for i := 0; i < 2; i++ {
+		if err := complete(); !errors.Is(err, ErrLLMUnavailable) || errors.Is(err, ErrLLMCircuitOpen) {
+			t.Fatalf("failure %d: err = %v, want the provider being unavailable", i+1, err)
+		}
+	}
+
+	// Open, nothing reaches the provider during the cooldown
+	if err := complete(); !errors.Is(err, ErrLLMCircuitOpen) {
+		t.Fatalf("err = %v, want the circuit being open", err)
+	}
+	if *calls != 2 {
+		t.Fatalf("calls = %d while open, want 2", *calls)
+	}
+
+	// Half open, a failed probe opens it again right away
+	time.Sleep(cooldown)
+	if err := complete(); errors.Is(err, ErrLLMCircuitOpen) {
+		t.Fatalf("err = %v, want the probe to reach the provider", err)
+	}
+	if err := complete(); !errors.Is(err, ErrLLMCircuitOpen) {
+		t.Fatalf("err = %v after a failed probe, want the circuit being open", err)
+	}
+
+	// Half open, a successful probe closes it
+	time.Sleep(cooldown)
+	for i := 0; i < 2; i++ {
+		if err := complete(); err != nil {
+			t.Fatalf("call %d after the cooldown: err = %v, want nil", i+1, err)
+		}
+	}
+	if *calls != 5 {
+		t.Fatalf("calls = %d, want 5", *calls)
+	}
+}
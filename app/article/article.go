@@ -13,9 +13,13 @@ type Article struct {
 	Title        string      `json:"title"`
 	ThumbnailUrl null.String `json:"thumbnail_url"`
 	OriginalUrl  string      `json:"original_url"`
+	SourceId     ulid.ULID   `json:"source_id"`
 	Source       string      `json:"source"`
+	AuthorId     *ulid.ULID  `json:"author_id"`
 	Author       null.String `json:"author"`
 	IsPublished  bool        `json:"is_published"`
+	PublishedAt  null.Time   `json:"published_at"`
+	Version      uint        `json:"version"`
 	CreatedAt    time.Time   `json:"created_at"`
 	UpdatedAt    null.Time   `json:"updated_at"`
 	DeletedAt    null.Time   `json:"deleted_at"`
@@ -58,7 +62,7 @@ func NewArticle(
 
 	id := ulid.Make()
 
-	return Article{
+	article := Article{
 		Id:           id,
 		CategoryId:   categoryId,
 		Title:        title,
@@ -67,8 +71,14 @@ func NewArticle(
 		Source:       source,
 		Author:       author,
 		IsPublished:  isPublished.Valid && isPublished.Bool,
+		Version:      1,
 		CreatedAt:    time.Now(),
-	}, nil
+	}
+	if article.IsPublished {
+		article.PublishedAt = null.TimeFrom(article.CreatedAt)
+	}
+
+	return article, nil
 }
 
 func (a *Article) Update(
@@ -127,6 +137,12 @@ func (a *Article) Update(
 
 	if isPublished.Valid {
 		a.IsPublished = isPublished.Bool
+
+		// Keep the first publication date so unpublishing for a fix doesn't
+		// bump the article back to the top of the listing
+		if a.IsPublished && !a.PublishedAt.Valid {
+			a.PublishedAt = null.TimeFrom(time.Now())
+		}
 	}
 
 	if len(errs) != 0 {
@@ -143,3 +159,17 @@ func (a *Article) Delete() {
 		a.DeletedAt = null.NewTime(time.Now(), true)
 	}
 }
+
+func (a *Article) Restore() {
+	if a.DeletedAt.Valid {
+		a.DeletedAt = null.NewTime(time.Time{}, false)
+		a.UpdatedAt = null.NewTime(time.Now(), true)
+	}
+}
+
+func (a Article) LastModified() time.Time {
+	if a.UpdatedAt.Valid {
+		return a.UpdatedAt.Time
+	}
+	return a.CreatedAt
+}
@@ -10,6 +10,7 @@ import (
 type ArticleCategory struct {
 	Id        ulid.ULID `json:"id"`
 	Name      string    `json:"name"`
+	Version   uint      `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt null.Time `json:"updated_at"`
 	DeletedAt null.Time `json:"deleted_at"`
@@ -23,8 +24,23 @@ func NewArticleCategory(name string) (category ArticleCategory, err error) {
 	category = ArticleCategory{
 		Id:        ulid.Make(),
 		Name:      name,
+		Version:   1,
 		CreatedAt: time.Now(),
 	}
 
 	return category, nil
 }
+
+func (c *ArticleCategory) Restore() {
+	if c.DeletedAt.Valid {
+		c.DeletedAt = null.NewTime(time.Time{}, false)
+		c.UpdatedAt = null.TimeFrom(time.Now())
+	}
+}
+
+func (c ArticleCategory) LastModified() time.Time {
+	if c.UpdatedAt.Valid {
+		return c.UpdatedAt.Time
+	}
+	return c.CreatedAt
+}
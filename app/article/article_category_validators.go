@@ -11,6 +11,7 @@ var (
 	ErrArticleCategoryNameTooLong = validation.NewError("article:category_name_too_long", "Category name can't be longer than 100 characters")
 	ErrArticleCategoryNameEmpty   = validation.NewError("article:category_name_empty", "Article category can't be empty")
 	ErrInvalidArticleCategoryId   = validation.NewError("article:invalid_category_id", "Invalid article category id")
+	ErrArticleCategoryMoveToSelf  = validation.NewError("article:category_move_to_self", "Articles can't be moved into the category they're already in")
 )
 
 func validateArticleCategoryId(idStr string) (id ulid.ULID, err error) {
@@ -30,3 +31,26 @@ func validateArticleCategoryName(name string) error {
 		validation.Length(1, 100).ErrorObject(ErrArticleCategoryNameTooLong),
 	)
 }
+
+// validateArticleCategoryMove parses the category articles move into and the
+// optional subset of articles to move. No article ids means all of them.
+func validateArticleCategoryMove(from ulid.ULID, toStr string, articleIdStrs []string) (to ulid.ULID, articleIds []ulid.ULID, err error) {
+	to, err = validateArticleCategoryId(toStr)
+	if err != nil {
+		return
+	}
+	if to.Compare(from) == 0 {
+		return to, nil, ErrArticleCategoryMoveToSelf
+	}
+
+	for _, idStr := range articleIdStrs {
+		id, err := validateArticleId(idStr)
+		if err != nil {
+			return to, nil, err
+		}
+
+		articleIds = append(articleIds, id)
+	}
+
+	return to, articleIds, nil
+}
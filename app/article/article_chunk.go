@@ -0,0 +1,96 @@
+package article
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/lexica-app/lexicapi/adapters"
+)
+
+// splitArticleChunks groups the paragraphs of a text into chunks of at most
+// maxTokens. A paragraph is only broken up when it's too big on its own, first
+// between sentences and as a last resort between words. Text that fits is
+// returned untouched.
+func splitArticleChunks(text string, maxTokens int) []string {
+	if adapters.EstimateTokens(text) <= maxTokens {
+		return []string{text}
+	}
+
+	units := []string{}
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if adapters.EstimateTokens(paragraph) <= maxTokens {
+			units = append(units, paragraph)
+			continue
+		}
+
+		for _, sentences := range packChunkPieces(splitSentences(paragraph), " ", maxTokens) {
+			if adapters.EstimateTokens(sentences) <= maxTokens {
+				units = append(units, sentences)
+				continue
+			}
+
+			units = append(units, packChunkPieces(strings.Fields(sentences), " ", maxTokens)...)
+		}
+	}
+
+	return packChunkPieces(units, "\n\n", maxTokens)
+}
+
+// packChunkPieces greedily joins consecutive pieces while they fit, a piece
+// that's too big on its own ends up alone.
+func packChunkPieces(pieces []string, sep string, maxTokens int) []string {
+	chunks := []string{}
+
+	var b strings.Builder
+	for _, piece := range pieces {
+		if b.Len() > 0 && adapters.EstimateTokens(b.String()+sep+piece) > maxTokens {
+			chunks = append(chunks, b.String())
+			b.Reset()
+		}
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(piece)
+	}
+	if b.Len() > 0 {
+		chunks = append(chunks, b.String())
+	}
+
+	return chunks
+}
+
+// splitSentences ends a sentence after ., ! or ? and any closing quotes or
+// brackets when whitespace follows.
+func splitSentences(paragraph string) []string {
+	sentences := []string{}
+	runes := []rune(paragraph)
+
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if !strings.ContainsRune(".!?", runes[i]) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && strings.ContainsRune("\"'”’)]", runes[end]) {
+			end++
+		}
+		if end < len(runes) && !unicode.IsSpace(runes[end]) {
+			continue
+		}
+
+		if sentence := strings.TrimSpace(string(runes[start:end])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start, i = end, end-1
+	}
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+
+	return sentences
+}
@@ -0,0 +1,112 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/lexica-app/lexicapi/adapters"
+)
+
+var chunkMarkerPattern = regexp.MustCompile(`Bagian\d+`)
+
+// chunkMarkersLLM answers with the markers of the paragraphs it was sent and
+// cuts the reply off whenever it was sent more than maxParagraphs of them.
+func chunkMarkersLLM(maxParagraphs int) (llm *adapters.FakeLLM, calls *int) {
+	calls = new(int)
+	markers := func(req adapters.LLMRequest) []string {
+		return chunkMarkerPattern.FindAllString(req.Messages[len(req.Messages)-1].Content, -1)
+	}
+
+	llm = adapters.NewFakeLLM(func(req adapters.LLMRequest) string {
+		*calls++
+		return strings.Join(markers(req), " ")
+	})
+	llm.FinishReason = func(req adapters.LLMRequest) adapters.LLMFinishReason {
+		if len(markers(req)) > maxParagraphs {
+			return adapters.LLMFinishReasonLength
+		}
+
+		return adapters.LLMFinishReasonStop
+	}
+
+	return llm, calls
+}
+
+func chunkParagraphs(markers ...string) string {
+	paragraphs := make([]string, len(markers))
+	for i, marker := range markers {
+		paragraphs[i] = marker + " " + strings.Repeat("kalimat yang cukup panjang untuk paragraf ini ", 5)
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+func TestAdaptArticleChunkSplitsTruncatedChunks(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		maxParagraphs int
+		want          string
+		wantCalls     int
+		wantErr       error
+	}{
+		{
+			name:          "keeps a chunk that fits",
+			text:          chunkParagraphs("Bagian1", "Bagian2"),
+			maxParagraphs: 2,
+			want:          "Bagian1 Bagian2",
+			wantCalls:     1,
+		},
+		{
+			name:          "splits a truncated chunk in halves",
+			text:          chunkParagraphs("Bagian1", "Bagian2", "Bagian3", "Bagian4"),
+			maxParagraphs: 2,
+			want:          "Bagian1 Bagian2\n\nBagian3 Bagian4",
+			wantCalls:     3,
+		},
+		{
+			name:          "splits the halves again while they're truncated",
+			text:          chunkParagraphs("Bagian1", "Bagian2", "Bagian3", "Bagian4"),
+			maxParagraphs: 1,
+			want:          "Bagian1\n\nBagian2\n\nBagian3\n\nBagian4",
+			wantCalls:     7,
+		},
+		{
+			name:          "gives up after the maximum number of splits",
+			text:          chunkParagraphs("Bagian1", "Bagian2", "Bagian3", "Bagian4", "Bagian5", "Bagian6", "Bagian7", "Bagian8"),
+			maxParagraphs: 1,
+			wantErr:       ErrArticleTextTruncated,
+		},
+	}
+
+	settings := adapters.LLMTaskSettingsFor(adapters.LLMTaskArticleText)
+	defer func(previous adapters.LLM) { llmAdapter = previous }(llmAdapter)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm, calls := chunkMarkersLLM(tt.maxParagraphs)
+			SetLLMAdapter(llm)
+
+			got, _, err := adaptArticleChunk(context.Background(), settings, string(ADVANCED), string(BEGINNER), tt.text, "", "", 0)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+			if *calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package article
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// articleCursor points at an article by its position under a sort. It is
+// handed to clients base64 encoded so its shape can change without breaking them.
+type articleCursor struct {
+	Sort  ArticleSort     `json:"s"`
+	Value json.RawMessage `json:"v"`
+	Id    ulid.ULID       `json:"id"`
+}
+
+func newArticleCursor(sort ArticleSort, article *ArticleWithRowNumber) (string, error) {
+	value, err := json.Marshal(article.SortValue)
+	if err != nil {
+		return "", err
+	}
+
+	cursor, err := json.Marshal(articleCursor{Sort: sort, Value: value, Id: article.Id})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(cursor), nil
+}
+
+// decodeArticleCursor returns the id and sort value the cursor points at. Cursors
+// made for a different sort are rejected since their values aren't comparable.
+func decodeArticleCursor(cursorStr string, sort ArticleSort) (id ulid.ULID, value any, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err != nil {
+		return id, nil, ErrInvalidArticleCursor
+	}
+
+	var cursor articleCursor
+	if err = json.Unmarshal(raw, &cursor); err != nil || cursor.Sort != sort {
+		return id, nil, ErrInvalidArticleCursor
+	}
+
+	switch sort {
+	case NEWEST, OLDEST:
+		var publishedAt time.Time
+		err = json.Unmarshal(cursor.Value, &publishedAt)
+		value = publishedAt
+	case TITLE:
+		var title string
+		err = json.Unmarshal(cursor.Value, &title)
+		value = title
+	case POPULARITY, SERIES:
+		var popularity int64
+		err = json.Unmarshal(cursor.Value, &popularity)
+		value = popularity
+	default:
+		return id, nil, ErrInvalidArticleCursor
+	}
+	if err != nil {
+		return id, nil, ErrInvalidArticleCursor
+	}
+
+	return cursor.Id, value, nil
+}
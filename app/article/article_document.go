@@ -0,0 +1,244 @@
+package article
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+type ArticleBlockType string
+
+const (
+	HEADING   ArticleBlockType = "heading"
+	PARAGRAPH ArticleBlockType = "paragraph"
+	QUOTE     ArticleBlockType = "quote"
+	IMAGE     ArticleBlockType = "image"
+)
+
+type ArticleDocumentFormat string
+
+const (
+	HTML     ArticleDocumentFormat = "html"
+	TEXT     ArticleDocumentFormat = "text"
+	MARKDOWN ArticleDocumentFormat = "markdown"
+)
+
+// ArticleBlock is a single structural element of an article. Text and captions
+// are always plain text, markup is only ever produced when rendering.
+type ArticleBlock struct {
+	Type    ArticleBlockType `json:"type"`
+	Level   int              `json:"level,omitempty"`
+	Text    string           `json:"text,omitempty"`
+	Url     string           `json:"url,omitempty"`
+	Caption string           `json:"caption,omitempty"`
+}
+
+type ArticleDocument struct {
+	Blocks []ArticleBlock `json:"blocks"`
+}
+
+var (
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownImagePattern   = regexp.MustCompile(`^!\[([^\]]*)\]\(\s*(\S+?)(?:\s+"([^"]*)")?\s*\)$`)
+	htmlTagPattern         = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+)
+
+func NewArticleDocumentFromMarkdown(markdown string) ArticleDocument {
+	document := ArticleDocument{Blocks: []ArticleBlock{}}
+
+	var paragraph, quote []string
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			document.Blocks = append(document.Blocks, ArticleBlock{Type: PARAGRAPH, Text: strings.Join(paragraph, " ")})
+			paragraph = nil
+		}
+	}
+	flushQuote := func() {
+		if len(quote) == 0 {
+			return
+		}
+
+		block := ArticleBlock{Type: QUOTE}
+		last := quote[len(quote)-1]
+		if len(quote) > 1 && (strings.HasPrefix(last, "— ") || strings.HasPrefix(last, "-- ")) {
+			block.Caption = strings.TrimSpace(strings.TrimLeft(last, "—- "))
+			quote = quote[:len(quote)-1]
+		}
+		block.Text = strings.Join(quote, " ")
+
+		document.Blocks = append(document.Blocks, block)
+		quote = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			flushParagraph()
+			flushQuote()
+			continue
+		}
+
+		if match := markdownHeadingPattern.FindStringSubmatch(line); match != nil {
+			flushParagraph()
+			flushQuote()
+
+			level := len(match[1])
+			if level > 3 {
+				level = 3
+			}
+			document.Blocks = append(document.Blocks, ArticleBlock{Type: HEADING, Level: level, Text: match[2]})
+			continue
+		}
+
+		if match := markdownImagePattern.FindStringSubmatch(line); match != nil {
+			flushParagraph()
+			flushQuote()
+
+			caption := match[3]
+			if caption == "" {
+				caption = match[1]
+			}
+			document.Blocks = append(document.Blocks, ArticleBlock{Type: IMAGE, Url: match[2], Caption: caption})
+			continue
+		}
+
+		if strings.HasPrefix(line, ">") {
+			flushParagraph()
+			quote = append(quote, strings.TrimSpace(line[1:]))
+			continue
+		}
+
+		flushQuote()
+		if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+		paragraph = append(paragraph, line)
+	}
+
+	flushParagraph()
+	flushQuote()
+
+	return document
+}
+
+// NewArticleDocumentFromPlainText is used for texts that were stored before
+// documents existed, treating every non-empty line as a paragraph.
+func NewArticleDocumentFromPlainText(content string) ArticleDocument {
+	document := ArticleDocument{Blocks: []ArticleBlock{}}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		document.Blocks = append(document.Blocks, ArticleBlock{Type: PARAGRAPH, Text: line})
+	}
+
+	return document
+}
+
+func (d *ArticleDocument) Sanitize() {
+	for i := range d.Blocks {
+		d.Blocks[i].Type = ArticleBlockType(strings.ToLower(strings.TrimSpace(string(d.Blocks[i].Type))))
+		d.Blocks[i].Text = sanitizeArticleBlockText(d.Blocks[i].Text)
+		d.Blocks[i].Caption = sanitizeArticleBlockText(d.Blocks[i].Caption)
+		d.Blocks[i].Url = strings.TrimSpace(d.Blocks[i].Url)
+	}
+}
+
+func sanitizeArticleBlockText(text string) string {
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func (d ArticleDocument) Render(format ArticleDocumentFormat) string {
+	switch format {
+	case HTML:
+		return d.HTML()
+	case MARKDOWN:
+		return d.Markdown()
+	default:
+		return d.PlainText()
+	}
+}
+
+func (d ArticleDocument) HTML() string {
+	var b strings.Builder
+
+	for _, block := range d.Blocks {
+		switch block.Type {
+		case HEADING:
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", block.Level, html.EscapeString(block.Text), block.Level)
+		case PARAGRAPH:
+			fmt.Fprintf(&b, "<p>%s</p>\n", html.EscapeString(block.Text))
+		case QUOTE:
+			b.WriteString("<blockquote>")
+			fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(block.Text))
+			if block.Caption != "" {
+				fmt.Fprintf(&b, "<footer>%s</footer>", html.EscapeString(block.Caption))
+			}
+			b.WriteString("</blockquote>\n")
+		case IMAGE:
+			fmt.Fprintf(&b, `<figure><img src="%s" alt="%s">`, html.EscapeString(block.Url), html.EscapeString(block.Caption))
+			if block.Caption != "" {
+				fmt.Fprintf(&b, "<figcaption>%s</figcaption>", html.EscapeString(block.Caption))
+			}
+			b.WriteString("</figure>\n")
+		}
+	}
+
+	return b.String()
+}
+
+func (d ArticleDocument) Markdown() string {
+	parts := make([]string, 0, len(d.Blocks))
+
+	for _, block := range d.Blocks {
+		switch block.Type {
+		case HEADING:
+			parts = append(parts, strings.Repeat("#", block.Level)+" "+block.Text)
+		case PARAGRAPH:
+			text := block.Text
+			if strings.HasPrefix(text, "#") || strings.HasPrefix(text, ">") || strings.HasPrefix(text, "!") || strings.HasPrefix(text, `\`) {
+				text = `\` + text
+			}
+			parts = append(parts, text)
+		case QUOTE:
+			quote := "> " + block.Text
+			if block.Caption != "" {
+				quote += "\n> — " + block.Caption
+			}
+			parts = append(parts, quote)
+		case IMAGE:
+			parts = append(parts, fmt.Sprintf("![%s](%s)", strings.ReplaceAll(block.Caption, "]", ""), block.Url))
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+func (d ArticleDocument) PlainText() string {
+	parts := make([]string, 0, len(d.Blocks))
+
+	for _, block := range d.Blocks {
+		switch block.Type {
+		case HEADING, PARAGRAPH:
+			parts = append(parts, block.Text)
+		case QUOTE:
+			if block.Caption != "" {
+				parts = append(parts, fmt.Sprintf("\"%s\" — %s", block.Text, block.Caption))
+			} else {
+				parts = append(parts, fmt.Sprintf("\"%s\"", block.Text))
+			}
+		case IMAGE:
+			if block.Caption != "" {
+				parts = append(parts, block.Caption)
+			}
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,135 @@
+package article
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jellydator/validation"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrArticleDocumentEmpty          = validation.NewError("article:document_empty", "Document must have at least one block")
+	ErrArticleDocumentTooLong        = validation.NewError("article:document_too_long", "Document can't have more than 500 blocks")
+	ErrInvalidArticleBlockType       = validation.NewError("article:invalid_block_type", "Invalid block type")
+	ErrInvalidArticleHeadingLevel    = validation.NewError("article:invalid_heading_level", "Heading level must be between 1 and 3")
+	ErrArticleBlockTextEmpty         = validation.NewError("article:block_text_empty", "Block text can't be empty")
+	ErrArticleBlockTextTooLong       = validation.NewError("article:block_text_too_long", "Block text can't be longer than 10000 characters")
+	ErrArticleHeadingTooLong         = validation.NewError("article:heading_too_long", "Heading can't be longer than 255 characters")
+	ErrArticleBlockCaptionTooLong    = validation.NewError("article:block_caption_too_long", "Caption can't be longer than 255 characters")
+	ErrInvalidArticleImageUrl        = validation.NewError("article:invalid_image_url", "Image url must be an absolute http or https url")
+	ErrUnexpectedArticleBlockField   = validation.NewError("article:unexpected_block_field", "Block has a field that isn't allowed for its type")
+	ErrArticleDocumentSourceConflict = validation.NewError("article:document_source_conflict", "Only one of document or markdown can be given")
+	ErrInvalidArticleDocumentFormat  = validation.NewError("article:invalid_document_format", "Invalid document format")
+)
+
+// parseArticleDocument builds a sanitized and validated document out of either
+// a JSON document or markdown. It returns nil when neither is given.
+func parseArticleDocument(document *ArticleDocument, markdown null.String) (*ArticleDocument, error) {
+	if document != nil && markdown.Valid {
+		return nil, ErrArticleDocumentSourceConflict
+	}
+
+	var parsed ArticleDocument
+	switch {
+	case document != nil:
+		parsed = ArticleDocument{Blocks: append([]ArticleBlock{}, document.Blocks...)}
+	case markdown.Valid:
+		parsed = NewArticleDocumentFromMarkdown(markdown.String)
+	default:
+		return nil, nil
+	}
+
+	parsed.Sanitize()
+	if err := validateArticleDocument(parsed); err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
+
+func validateArticleDocument(document ArticleDocument) error {
+	if len(document.Blocks) == 0 {
+		return ErrArticleDocumentEmpty
+	}
+	if len(document.Blocks) > 500 {
+		return ErrArticleDocumentTooLong
+	}
+
+	for i, block := range document.Blocks {
+		if err := validateArticleBlock(block); err != nil {
+			return fmt.Errorf("block %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func validateArticleBlock(block ArticleBlock) error {
+	switch block.Type {
+	case HEADING:
+		if block.Url != "" || block.Caption != "" {
+			return ErrUnexpectedArticleBlockField
+		}
+		if block.Level < 1 || block.Level > 3 {
+			return ErrInvalidArticleHeadingLevel
+		}
+		return validation.Validate(
+			&block.Text,
+			validation.Required.ErrorObject(ErrArticleBlockTextEmpty),
+			validation.RuneLength(1, 255).ErrorObject(ErrArticleHeadingTooLong),
+		)
+	case PARAGRAPH, QUOTE:
+		if block.Url != "" || block.Level != 0 || (block.Type == PARAGRAPH && block.Caption != "") {
+			return ErrUnexpectedArticleBlockField
+		}
+		if err := validateArticleBlockCaption(block.Caption); err != nil {
+			return err
+		}
+		return validation.Validate(
+			&block.Text,
+			validation.Required.ErrorObject(ErrArticleBlockTextEmpty),
+			validation.RuneLength(1, 10000).ErrorObject(ErrArticleBlockTextTooLong),
+		)
+	case IMAGE:
+		if block.Text != "" || block.Level != 0 {
+			return ErrUnexpectedArticleBlockField
+		}
+		if err := validateArticleImageUrl(block.Url); err != nil {
+			return err
+		}
+		return validateArticleBlockCaption(block.Caption)
+	default:
+		return ErrInvalidArticleBlockType
+	}
+}
+
+func validateArticleBlockCaption(caption string) error {
+	return validation.Validate(
+		&caption,
+		validation.RuneLength(0, 255).ErrorObject(ErrArticleBlockCaptionTooLong),
+	)
+}
+
+func validateArticleImageUrl(imageUrl string) error {
+	u, err := url.Parse(strings.TrimSpace(imageUrl))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrInvalidArticleImageUrl
+	}
+
+	return nil
+}
+
+func validateArticleDocumentFormat(formatStr string) (format ArticleDocumentFormat, err error) {
+	switch formatStr {
+	case string(HTML), "":
+		return HTML, nil
+	case string(TEXT):
+		return TEXT, nil
+	case string(MARKDOWN):
+		return MARKDOWN, nil
+	default:
+		return format, ErrInvalidArticleDocumentFormat
+	}
+}
@@ -0,0 +1,54 @@
+package article
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	defaultPreviewTokenTtlHours = 72
+	maxPreviewTokenTtlHours     = 30 * 24
+)
+
+// ArticlePreviewToken records an issued preview link. The signed token itself
+// is never stored, only its id so the link can be listed and revoked.
+type ArticlePreviewToken struct {
+	Id        ulid.ULID `json:"id"`
+	ArticleId ulid.ULID `json:"article_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	RevokedAt null.Time `json:"revoked_at"`
+}
+
+func NewArticlePreviewToken(articleId ulid.ULID, ttlHours null.Int) (ArticlePreviewToken, map[string]error) {
+	errs := make(map[string]error)
+
+	if !ttlHours.Valid {
+		ttlHours = null.IntFrom(defaultPreviewTokenTtlHours)
+	}
+	if err := validateArticlePreviewTokenTtl(ttlHours.Int64); err != nil {
+		errs["ttl_hours"] = err
+	}
+
+	if len(errs) != 0 {
+		return ArticlePreviewToken{}, errs
+	}
+
+	now := time.Now()
+	return ArticlePreviewToken{
+		Id:        ulid.Make(),
+		ArticleId: articleId,
+		ExpiresAt: now.Add(time.Duration(ttlHours.Int64) * time.Hour),
+		CreatedAt: now,
+	}, nil
+}
+
+func (t *ArticlePreviewToken) Revoke() {
+	t.RevokedAt = null.TimeFrom(time.Now())
+}
+
+func (t ArticlePreviewToken) IsActive() bool {
+	return !t.RevokedAt.Valid && time.Now().Before(t.ExpiresAt)
+}
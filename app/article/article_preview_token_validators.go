@@ -0,0 +1,28 @@
+package article
+
+import (
+	"github.com/jellydator/validation"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrInvalidArticlePreviewTokenId  = validation.NewError("article:invalid_preview_token_id", "Invalid preview token id")
+	ErrInvalidArticlePreviewTokenTtl = validation.NewError("article:invalid_preview_token_ttl", "Preview token lifetime must be between 1 and 720 hours")
+)
+
+func validateArticlePreviewTokenId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidArticlePreviewTokenId
+	}
+
+	return id, nil
+}
+
+func validateArticlePreviewTokenTtl(hours int64) error {
+	return validation.Validate(
+		hours,
+		validation.Min(int64(1)).ErrorObject(ErrInvalidArticlePreviewTokenTtl),
+		validation.Max(int64(maxPreviewTokenTtlHours)).ErrorObject(ErrInvalidArticlePreviewTokenTtl),
+	)
+}
@@ -0,0 +1,208 @@
+package article
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// availableDifficultiesColumn lists the difficulties an article can be read in,
+// starting from the original text.
+const availableDifficultiesColumn = `ARRAY(
+	    SELECT dt.difficulty FROM article_texts dt
+	    WHERE dt.article_id = a.id AND dt.moderation_status = 'APPROVED' AND dt.deleted_at IS NULL
+	    ORDER BY (CASE dt.difficulty WHEN 'ADVANCED' THEN 0 WHEN 'INTERMEDIATE' THEN 1 WHEN 'BEGINNER' THEN 2 ELSE 3 END), dt.difficulty
+	  ) available_difficulties`
+
+// articleViewModelColumns select everything an ArticleViewModel is scanned
+// from. They expect the category joined as ac next to a and at.
+var articleViewModelColumns = []string{
+	"a.*",
+	"ac.name category_name",
+	"(CASE WHEN LENGTH(at.content) >= 255 THEN SUBSTRING(at.content, 1, 255) || '...' ELSE at.content END) teaser",
+	"at.word_count",
+	"at.reading_time_minutes",
+	availableDifficultiesColumn,
+}
+
+type articleFilter struct {
+	Query              string
+	CategoryIds        []ulid.ULID
+	SourceIds          []ulid.ULID
+	AuthorIds          []ulid.ULID
+	SeriesIds          []ulid.ULID
+	Source             string
+	Author             string
+	PublishedFrom      null.Time
+	PublishedUntil     null.Time
+	Difficulties       []string
+	MinReadingTime     null.Int
+	MaxReadingTime     null.Int
+	IncludeUnpublished bool
+}
+
+// where expects articles aliased as a and their original text as at.
+func (f articleFilter) where() sq.And {
+	conditions := sq.And{
+		sq.Expr("a.deleted_at IS NULL"),
+		sq.Expr("a.title ILIKE '%' || ? || '%'", f.Query),
+	}
+
+	if !f.IncludeUnpublished {
+		conditions = append(conditions, sq.Expr("a.is_published IS TRUE"), sq.Expr("at.moderation_status = 'APPROVED'"))
+	}
+	if len(f.CategoryIds) > 0 {
+		conditions = append(conditions, sq.Expr("a.category_id = ANY(?)", ulidBytes(f.CategoryIds)))
+	}
+	if len(f.SourceIds) > 0 {
+		conditions = append(conditions, sq.Expr("a.source_id = ANY(?)", ulidBytes(f.SourceIds)))
+	}
+	if len(f.AuthorIds) > 0 {
+		conditions = append(conditions, sq.Expr("a.author_id = ANY(?)", ulidBytes(f.AuthorIds)))
+	}
+	if len(f.SeriesIds) > 0 {
+		series := "SELECT sa.article_id FROM series_articles sa INNER JOIN series s ON s.id = sa.series_id WHERE sa.series_id = ANY(?) AND s.deleted_at IS NULL"
+		if !f.IncludeUnpublished {
+			series += " AND s.is_published IS TRUE"
+		}
+		conditions = append(conditions, sq.Expr("a.id IN ("+series+")", ulidBytes(f.SeriesIds)))
+	}
+	if f.Source != "" {
+		conditions = append(conditions, sq.Expr(
+			"a.source_id IN (SELECT s.id FROM sources s WHERE LOWER(s.name) = LOWER(?) OR LOWER(?) = ANY(s.aliases) OR s.domain = LOWER(?))",
+			f.Source, f.Source, f.Source,
+		))
+	}
+	if f.Author != "" {
+		conditions = append(conditions, sq.Expr(
+			"a.author_id IN (SELECT au.id FROM authors au WHERE LOWER(au.name) = LOWER(?) OR LOWER(?) = ANY(au.aliases))",
+			f.Author, f.Author,
+		))
+	}
+	if f.PublishedFrom.Valid {
+		conditions = append(conditions, sq.GtOrEq{"a.published_at": f.PublishedFrom.Time})
+	}
+	if f.PublishedUntil.Valid {
+		conditions = append(conditions, sq.Lt{"a.published_at": f.PublishedUntil.Time})
+	}
+	for _, difficulty := range f.Difficulties {
+		conditions = append(conditions, sq.Expr(
+			"EXISTS (SELECT 1 FROM article_texts ft WHERE ft.article_id = a.id AND ft.difficulty = ? AND ft.moderation_status = 'APPROVED' AND ft.deleted_at IS NULL)",
+			difficulty,
+		))
+	}
+	if f.MinReadingTime.Valid {
+		conditions = append(conditions, sq.GtOrEq{"at.reading_time_minutes": f.MinReadingTime.Int64})
+	}
+	if f.MaxReadingTime.Valid {
+		conditions = append(conditions, sq.LtOrEq{"at.reading_time_minutes": f.MaxReadingTime.Int64})
+	}
+
+	return conditions
+}
+
+// articleSortKey returns the expression an article listing is ordered by and
+// whether it's descending. Ties are always broken by id in the same direction.
+func articleSortKey(sort ArticleSort) (key string, descending bool) {
+	switch sort {
+	case OLDEST:
+		return "COALESCE(a.published_at, a.created_at)", false
+	case TITLE:
+		return "a.title", false
+	case POPULARITY:
+		return `(
+	    SELECT COUNT(*) FROM collection_articles ca
+	    INNER JOIN collections c ON c.id = ca.collection_id
+	    WHERE ca.article_id = a.id AND ca.deleted_at IS NULL AND c.deleted_at IS NULL
+	  )`, true
+	case SERIES:
+		return "(SELECT sa.position FROM series_articles sa WHERE sa.article_id = a.id)", false
+	default:
+		return "COALESCE(a.published_at, a.created_at)", true
+	}
+}
+
+// articleQuery derives every listing statement from a single filter and sort
+// so the page, its row offsets and the total can't disagree with each other.
+type articleQuery struct {
+	filter articleFilter
+	sort   ArticleSort
+}
+
+// Listings only show articles through their original text.
+const originalTextJoin = "article_texts at ON a.id = at.article_id AND at.difficulty = 'ADVANCED' AND at.deleted_at IS NULL"
+
+func (q articleQuery) selectFrom(columns ...string) sq.SelectBuilder {
+	return sq.StatementBuilder.
+		PlaceholderFormat(sq.Dollar).
+		Select(columns...).
+		From("articles a").
+		InnerJoin(originalTextJoin).
+		Where(q.filter.where())
+}
+
+func (q articleQuery) count() sq.SelectBuilder {
+	return q.selectFrom("COUNT(*) total")
+}
+
+// countBefore counts the articles that come before the given position under the sort.
+func (q articleQuery) countBefore(id ulid.ULID, sortValue any) sq.SelectBuilder {
+	return q.count().Where(q.keyset(false, id, sortValue))
+}
+
+// page returns up to limit articles starting at the cursor when going forward,
+// or ending right before it when going back. Pages going back come out in
+// reverse order.
+func (q articleQuery) page(direction ArticlePaginationDirection, cursorId ulid.ULID, cursorValue any, limit uint) sq.SelectBuilder {
+	sortKey, descending := articleSortKey(q.sort)
+
+	builder := q.
+		selectFrom(append([]string{sortKey + " sort_value"}, articleViewModelColumns...)...).
+		InnerJoin("article_categories ac ON a.category_id = ac.id")
+
+	forward := direction != PREVIOUS
+	if cursorId != (ulid.ULID{}) {
+		builder = builder.Where(q.keyset(forward, cursorId, cursorValue))
+	}
+
+	order := "ASC"
+	if descending == forward {
+		order = "DESC"
+	}
+
+	return builder.
+		OrderBy("sort_value "+order, "a.id "+order).
+		Limit(uint64(limit))
+}
+
+// keyset matches articles at or after the position when atOrAfter is set and
+// strictly before it otherwise.
+func (q articleQuery) keyset(atOrAfter bool, id ulid.ULID, sortValue any) sq.Sqlizer {
+	sortKey, descending := articleSortKey(q.sort)
+
+	var operator string
+	switch {
+	case atOrAfter && descending:
+		operator = "<="
+	case atOrAfter:
+		operator = ">="
+	case descending:
+		operator = ">"
+	default:
+		operator = "<"
+	}
+
+	return sq.Expr(fmt.Sprintf("(%s, a.id) %s (?, ?)", sortKey, operator), sortValue, id)
+}
+
+// ulidBytes lets a list of ids be passed as a single bytea[] parameter.
+func ulidBytes(ids []ulid.ULID) [][]byte {
+	bytes := make([][]byte, 0, len(ids))
+	for _, id := range ids {
+		bytes = append(bytes, id.Bytes())
+	}
+
+	return bytes
+}
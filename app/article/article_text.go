@@ -1,6 +1,7 @@
 package article
 
 import (
+	"strings"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -15,15 +16,36 @@ const (
 	BEGINNER     ArticleTextDifficultyPreset = "BEGINNER"
 )
 
+// Generated texts moderation held for review stay hidden from readers until
+// the flag on them is approved.
+type ArticleTextModerationStatus string
+
+const (
+	TEXT_APPROVED ArticleTextModerationStatus = "APPROVED"
+	TEXT_PENDING  ArticleTextModerationStatus = "PENDING"
+)
+
+// Average silent reading speed used to estimate reading time.
+const wordsPerMinute = 200
+
 type ArticleText struct {
-	Id         ulid.ULID `json:"id"`
-	ArticleId  ulid.ULID `json:"article_id"`
-	Content    string    `json:"content"`
-	Difficulty string    `json:"difficulty"`
-	IsAdapted  bool      `json:"is_adapted"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  null.Time `json:"updated_at"`
-	DeletedAt  null.Time `json:"deleted_at"`
+	Id                 ulid.ULID        `json:"id"`
+	ArticleId          ulid.ULID        `json:"article_id"`
+	Content            string           `json:"content"`
+	Difficulty         string           `json:"difficulty"`
+	IsAdapted          bool             `json:"is_adapted"`
+	Version            uint             `json:"version"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          null.Time        `json:"updated_at"`
+	DeletedAt          null.Time        `json:"deleted_at"`
+	Document           *ArticleDocument `json:"document"`
+	WordCount          uint             `json:"word_count"`
+	ReadingTimeMinutes uint             `json:"reading_time_minutes"`
+	PromptVersion      null.String      `json:"prompt_version"`
+	// Quality is the report of the checks a generated text went through,
+	// editing the text drops it
+	Quality          *ArticleTextQuality         `json:"quality"`
+	ModerationStatus ArticleTextModerationStatus `json:"moderation_status"`
 }
 
 func NewArticleText(
@@ -50,14 +72,20 @@ func NewArticleText(
 
 	id := ulid.Make()
 
-	return ArticleText{
+	text := ArticleText{
 		Id:         id,
 		ArticleId:  articleId,
 		Content:    content,
 		Difficulty: difficulty,
 		IsAdapted:  isAdapted,
+		Version:    1,
 		CreatedAt:  time.Now(),
-	}, nil
+
+		ModerationStatus: TEXT_APPROVED,
+	}
+	text.countWords()
+
+	return text, nil
 }
 
 func (at *ArticleText) Update(content, difficulty string, isAdapted bool) map[string]error {
@@ -77,12 +105,53 @@ func (at *ArticleText) Update(content, difficulty string, isAdapted bool) map[st
 	at.Difficulty = difficulty
 	at.IsAdapted = isAdapted
 	at.UpdatedAt = null.TimeFrom(time.Now())
+	at.Quality = nil
+	at.countWords()
 
 	return nil
 }
 
+// SetDocument keeps content in sync with the document so search and teasers
+// keep working on the plain text. A nil document drops the structure.
+func (at *ArticleText) SetDocument(document *ArticleDocument) {
+	at.Document = document
+	if document != nil {
+		at.Content = document.PlainText()
+		at.countWords()
+	}
+}
+
+func (at *ArticleText) countWords() {
+	at.WordCount = uint(len(strings.Fields(at.Content)))
+	at.ReadingTimeMinutes = (at.WordCount + wordsPerMinute - 1) / wordsPerMinute
+}
+
+// RenderableDocument falls back to a paragraph per line for texts that were
+// saved without a document.
+func (at ArticleText) RenderableDocument() ArticleDocument {
+	if at.Document != nil {
+		return *at.Document
+	}
+
+	return NewArticleDocumentFromPlainText(at.Content)
+}
+
 func (at *ArticleText) Delete() {
 	if !at.DeletedAt.Valid {
 		at.DeletedAt = null.TimeFrom(time.Now())
 	}
 }
+
+func (at *ArticleText) Restore() {
+	if at.DeletedAt.Valid {
+		at.DeletedAt = null.NewTime(time.Time{}, false)
+		at.UpdatedAt = null.TimeFrom(time.Now())
+	}
+}
+
+func (at ArticleText) LastModified() time.Time {
+	if at.UpdatedAt.Valid {
+		return at.UpdatedAt.Time
+	}
+	return at.CreatedAt
+}
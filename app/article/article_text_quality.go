@@ -0,0 +1,383 @@
+package article
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+type QualityCheckName string
+
+const (
+	LANGUAGE_CHECK     QualityCheckName = "LANGUAGE"
+	LENGTH_RATIO_CHECK QualityCheckName = "LENGTH_RATIO"
+	PARAGRAPHS_CHECK   QualityCheckName = "PARAGRAPHS"
+	ARTIFACTS_CHECK    QualityCheckName = "ARTIFACTS"
+	READABILITY_CHECK  QualityCheckName = "READABILITY"
+)
+
+const (
+	// Texts shorter than this in words are too short for the language and
+	// length checks to mean anything
+	minQualityCheckWords = 20
+	// Share of the words that have to be common Indonesian words
+	minIndonesianRatio = 0.1
+	minLengthRatio     = 0.5
+	maxLengthRatio     = 1.8
+	// Grade levels a text adapted to an easier difficulty has to drop at least
+	minReadabilityDrop = 0.5
+)
+
+var ErrArticleTextQualityFailed = errors.New("The generated article text failed the quality checks on every attempt")
+
+var (
+	indonesianStopwords = stopwordSet("yang dan di ke dari ini itu dengan untuk tidak adalah pada dalam akan juga ada atau oleh karena sebagai mereka kita kami saya bisa dapat telah sudah lebih seperti banyak sangat jika agar para tersebut namun tetapi setelah saat bahwa")
+	englishStopwords    = stopwordSet("the and of to is in that it for was on are with as this be by at from have or an they which you were their has not but can will would there")
+
+	preamblePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^(tentu|baik|oke|ok|berikut|sure|here|certainly|of course|absolutely)\b.*:$`),
+		regexp.MustCompile(`(?i)^.*\b(teks|bacaan|versi)\b.*\b(disederhanakan|sederhana|level)\b.*:$`),
+	}
+	postamblePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^(semoga|jika (ada|kamu|anda) (butuh|ingin|perlu)|apakah (ada|kamu|anda)|let me know|i hope|hope this)\b`),
+	}
+	// Markdown only counts as an artifact when the original didn't have it
+	markdownPatterns = map[string]*regexp.Regexp{
+		"heading":    regexp.MustCompile(`(?m)^#{1,6}\s`),
+		"bold":       regexp.MustCompile(`\*\*[^*\n]+\*\*|__[^_\n]+__`),
+		"code fence": regexp.MustCompile("(?m)^```"),
+		"bullet":     regexp.MustCompile(`(?m)^\s*[-*+]\s+\S`),
+	}
+)
+
+// QualityCheck is the outcome of one check, Feedback is what the model is
+// told when the text is regenerated.
+type QualityCheck struct {
+	Name     QualityCheckName `json:"name"`
+	IsPassed bool             `json:"is_passed"`
+	Message  string           `json:"message"`
+	Feedback string           `json:"feedback,omitempty"`
+}
+
+type QualityReport struct {
+	Attempt  int            `json:"attempt"`
+	IsPassed bool           `json:"is_passed"`
+	Checks   []QualityCheck `json:"checks"`
+}
+
+// ArticleTextQuality keeps the report of every attempt it took to generate a
+// text, the last one is the text that was kept.
+type ArticleTextQuality struct {
+	IsPassed bool             `json:"is_passed"`
+	Attempts []*QualityReport `json:"attempts"`
+}
+
+// ArticleTextQualityError is errors.Is ErrArticleTextQualityFailed and holds
+// the reports for the editor.
+type ArticleTextQualityError struct {
+	Quality ArticleTextQuality
+}
+
+func (e *ArticleTextQualityError) Error() string {
+	return ErrArticleTextQualityFailed.Error()
+}
+
+func (e *ArticleTextQualityError) Is(target error) bool {
+	return target == ErrArticleTextQualityFailed
+}
+
+// checkArticleTextQuality compares a generated text with the text it was
+// adapted from.
+func checkArticleTextQuality(attempt int, originalDifficulty, targetDifficulty, original, generated string) *QualityReport {
+	report := &QualityReport{
+		Attempt:  attempt,
+		IsPassed: true,
+		Checks: []QualityCheck{
+			checkLanguage(original, generated),
+			checkLengthRatio(original, generated),
+			checkParagraphs(original, generated),
+			checkArtifacts(original, generated),
+			checkReadability(originalDifficulty, targetDifficulty, original, generated),
+		},
+	}
+
+	for _, c := range report.Checks {
+		report.IsPassed = report.IsPassed && c.IsPassed
+	}
+
+	return report
+}
+
+// feedback lists what went wrong for the next attempt's prompt
+func (r QualityReport) feedback() string {
+	var lines []string
+	for _, c := range r.Checks {
+		if !c.IsPassed && c.Feedback != "" {
+			lines = append(lines, "- "+c.Feedback)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (r QualityReport) failedChecks() []QualityCheckName {
+	var names []QualityCheckName
+	for _, c := range r.Checks {
+		if !c.IsPassed {
+			names = append(names, c.Name)
+		}
+	}
+
+	return names
+}
+
+func passedCheck(name QualityCheckName, message string) QualityCheck {
+	return QualityCheck{Name: name, IsPassed: true, Message: message}
+}
+
+func failedCheck(name QualityCheckName, message, feedback string) QualityCheck {
+	return QualityCheck{Name: name, Message: message, Feedback: feedback}
+}
+
+// checkLanguage expects Indonesian out when Indonesian went in, it only looks
+// at how often common Indonesian and English words show up.
+func checkLanguage(original, generated string) QualityCheck {
+	if len(strings.Fields(generated)) < minQualityCheckWords {
+		return passedCheck(LANGUAGE_CHECK, "Text is too short to tell its language")
+	}
+
+	originalId, originalEn := stopwordRatios(original)
+	if originalId < minIndonesianRatio || originalId <= originalEn {
+		return passedCheck(LANGUAGE_CHECK, "Original text isn't in Indonesian, language isn't checked")
+	}
+
+	generatedId, generatedEn := stopwordRatios(generated)
+	if generatedId < minIndonesianRatio || generatedId <= generatedEn {
+		return failedCheck(
+			LANGUAGE_CHECK,
+			fmt.Sprintf("Text doesn't look like Indonesian (%.0f%% common Indonesian words, %.0f%% common English words)", generatedId*100, generatedEn*100),
+			"Tulis seluruh teks dalam bahasa Indonesia, jangan gunakan bahasa Inggris.",
+		)
+	}
+
+	return passedCheck(LANGUAGE_CHECK, fmt.Sprintf("Text is in Indonesian (%.0f%% common Indonesian words)", generatedId*100))
+}
+
+func stopwordRatios(text string) (indonesian, english float64) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) == 0 {
+		return 0, 0
+	}
+
+	var id, en int
+	for _, w := range words {
+		if indonesianStopwords[w] {
+			id++
+		}
+		if englishStopwords[w] {
+			en++
+		}
+	}
+
+	return float64(id) / float64(len(words)), float64(en) / float64(len(words))
+}
+
+// checkLengthRatio catches texts the model cut short or padded out
+func checkLengthRatio(original, generated string) QualityCheck {
+	originalWords, generatedWords := len(strings.Fields(original)), len(strings.Fields(generated))
+	if originalWords < minQualityCheckWords {
+		return passedCheck(LENGTH_RATIO_CHECK, "Original text is too short to compare lengths")
+	}
+
+	ratio := float64(generatedWords) / float64(originalWords)
+	message := fmt.Sprintf("Text has %d words, %.0f%% of the original %d", generatedWords, ratio*100, originalWords)
+
+	switch {
+	case ratio < minLengthRatio:
+		return failedCheck(
+			LENGTH_RATIO_CHECK,
+			message,
+			fmt.Sprintf("Teks sebelumnya terlalu pendek (%d kata dari %d kata teks asli). Sederhanakan seluruh isi teks sampai selesai, jangan ada bagian yang dihilangkan atau terpotong.", generatedWords, originalWords),
+		)
+	case ratio > maxLengthRatio:
+		return failedCheck(
+			LENGTH_RATIO_CHECK,
+			message,
+			fmt.Sprintf("Teks sebelumnya terlalu panjang (%d kata dari %d kata teks asli). Jangan menambahkan isi yang tidak ada di teks asli.", generatedWords, originalWords),
+		)
+	}
+
+	return passedCheck(LENGTH_RATIO_CHECK, message)
+}
+
+// checkParagraphs lets a long text gain or lose a paragraph for every five
+func checkParagraphs(original, generated string) QualityCheck {
+	originalCount, generatedCount := len(textParagraphs(original)), len(textParagraphs(generated))
+
+	tolerance := originalCount / 5
+	if tolerance < 1 {
+		tolerance = 1
+	}
+
+	message := fmt.Sprintf("Text has %d paragraphs, the original has %d", generatedCount, originalCount)
+	if diff := generatedCount - originalCount; diff > tolerance || -diff > tolerance {
+		return failedCheck(
+			PARAGRAPHS_CHECK,
+			message,
+			fmt.Sprintf("Pertahankan jumlah paragraf seperti teks asli, yaitu %d paragraf. Teks sebelumnya memiliki %d paragraf.", originalCount, generatedCount),
+		)
+	}
+
+	return passedCheck(PARAGRAPHS_CHECK, message)
+}
+
+func textParagraphs(text string) []string {
+	var paragraphs []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paragraphs = append(paragraphs, line)
+		}
+	}
+
+	return paragraphs
+}
+
+// checkArtifacts looks for the model talking to the reader, like "Tentu,
+// berikut teksnya:", and markdown the original didn't have.
+func checkArtifacts(original, generated string) QualityCheck {
+	var found []string
+
+	paragraphs := textParagraphs(generated)
+	if len(paragraphs) != 0 {
+		for _, p := range preamblePatterns {
+			if p.MatchString(paragraphs[0]) {
+				found = append(found, fmt.Sprintf("preamble %q", paragraphs[0]))
+				break
+			}
+		}
+		for _, p := range postamblePatterns {
+			if last := paragraphs[len(paragraphs)-1]; p.MatchString(last) {
+				found = append(found, fmt.Sprintf("closing remark %q", last))
+				break
+			}
+		}
+	}
+
+	for _, name := range []string{"heading", "bold", "code fence", "bullet"} {
+		p := markdownPatterns[name]
+		if p.MatchString(generated) && !p.MatchString(original) {
+			found = append(found, "markdown "+name)
+		}
+	}
+
+	if len(found) != 0 {
+		return failedCheck(
+			ARTIFACTS_CHECK,
+			"Text has "+strings.Join(found, ", "),
+			"Tulis langsung teks hasil penyederhanaan tanpa kalimat pembuka atau penutup dan tanpa format markdown seperti #, ** atau daftar berpoin.",
+		)
+	}
+
+	return passedCheck(ARTIFACTS_CHECK, "Text has no preamble or markdown artifacts")
+}
+
+// checkReadability expects a text adapted to an easier difficulty to read
+// easier than the original by at least minReadabilityDrop grade levels.
+func checkReadability(originalDifficulty, targetDifficulty, original, generated string) QualityCheck {
+	originalRank, isOriginalPreset := difficultyRank(originalDifficulty)
+	targetRank, isTargetPreset := difficultyRank(targetDifficulty)
+	if !isOriginalPreset || !isTargetPreset || targetRank >= originalRank {
+		return passedCheck(READABILITY_CHECK, "Text isn't adapted to an easier difficulty, readability isn't checked")
+	}
+
+	originalGrade, generatedGrade := readabilityGrade(original), readabilityGrade(generated)
+	message := fmt.Sprintf("Text reads at grade %.1f, the original at %.1f", generatedGrade, originalGrade)
+	if generatedGrade > originalGrade-minReadabilityDrop {
+		return failedCheck(
+			READABILITY_CHECK,
+			message,
+			fmt.Sprintf("Teks sebelumnya tidak lebih mudah dibaca daripada teks asli. Gunakan kalimat yang lebih pendek dan kata-kata yang lebih sederhana sesuai level %s.", targetDifficulty),
+		)
+	}
+
+	return passedCheck(READABILITY_CHECK, message)
+}
+
+func difficultyRank(difficulty string) (int, bool) {
+	switch ArticleTextDifficultyPreset(difficulty) {
+	case BEGINNER:
+		return 0, true
+	case INTERMEDIATE:
+		return 1, true
+	case ADVANCED:
+		return 2, true
+	}
+
+	return 0, false
+}
+
+// readabilityGrade is the Flesch-Kincaid grade level with Indonesian
+// syllables. Indonesian words run longer so the grade itself is inflated, it's
+// only meant for comparing two texts.
+func readabilityGrade(text string) float64 {
+	var sentences, words, syllables int
+	for _, paragraph := range textParagraphs(text) {
+		for _, sentence := range splitSentences(paragraph) {
+			sentenceWords := strings.Fields(sentence)
+			if len(sentenceWords) == 0 {
+				continue
+			}
+
+			sentences++
+			words += len(sentenceWords)
+			for _, w := range sentenceWords {
+				syllables += countSyllables(w)
+			}
+		}
+	}
+	if words == 0 {
+		return 0
+	}
+
+	return 0.39*float64(words)/float64(sentences) + 11.8*float64(syllables)/float64(words) - 15.59
+}
+
+// countSyllables counts vowels, a final ai, au or oi (pantai, pulau) is one
+// syllable.
+func countSyllables(word string) int {
+	runes := []rune(strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})))
+
+	count := 0
+	for _, r := range runes {
+		if strings.ContainsRune("aeiou", r) {
+			count++
+		}
+	}
+
+	if n := len(runes); n >= 2 {
+		switch string(runes[n-2:]) {
+		case "ai", "au", "oi":
+			count--
+		}
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	return count
+}
+
+func stopwordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+
+	return set
+}
@@ -0,0 +1,214 @@
+package article
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckArtifacts(t *testing.T) {
+	const body = "Kucing itu tidur di atas kursi.\n\nIa bangun ketika hari sudah sore."
+
+	tests := []struct {
+		name      string
+		original  string
+		generated string
+		want      bool
+	}{
+		{
+			name:      "plain text",
+			original:  body,
+			generated: body,
+			want:      true,
+		},
+		{
+			name:      "preamble",
+			original:  body,
+			generated: "Tentu, berikut teksnya:\n\n" + body,
+			want:      false,
+		},
+		{
+			name:      "preamble naming the level",
+			original:  body,
+			generated: "Ini teks yang sudah disederhanakan untuk level pemula:\n\n" + body,
+			want:      false,
+		},
+		{
+			name:      "closing remark",
+			original:  body,
+			generated: body + "\n\nSemoga teks ini membantu!",
+			want:      false,
+		},
+		{
+			name:      "markdown heading",
+			original:  body,
+			generated: "# Kucing\n\n" + body,
+			want:      false,
+		},
+		{
+			name:      "markdown heading the original had",
+			original:  "# Kucing\n\n" + body,
+			generated: "# Kucing\n\n" + body,
+			want:      true,
+		},
+		{
+			name:      "bold",
+			original:  body,
+			generated: strings.Replace(body, "Kucing", "**Kucing**", 1),
+			want:      false,
+		},
+		{
+			name:      "bullets",
+			original:  body,
+			generated: "- Kucing itu tidur di atas kursi.\n- Ia bangun ketika hari sudah sore.",
+			want:      false,
+		},
+		{
+			name:      "colon inside a sentence",
+			original:  body,
+			generated: "Ia membawa tiga barang: tas, buku dan pensil.\n\n" + body,
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkArtifacts(tt.original, tt.generated)
+			if got.IsPassed != tt.want {
+				t.Errorf("IsPassed = %t, want %t: %s", got.IsPassed, tt.want, got.Message)
+			}
+		})
+	}
+}
+
+func TestCheckParagraphs(t *testing.T) {
+	paragraphs := func(n int) string {
+		return strings.TrimSuffix(strings.Repeat("Satu paragraf pendek.\n\n", n), "\n\n")
+	}
+
+	tests := []struct {
+		name      string
+		original  string
+		generated string
+		want      bool
+	}{
+		{
+			name:      "same count",
+			original:  paragraphs(3),
+			generated: paragraphs(3),
+			want:      true,
+		},
+		{
+			name:      "one paragraph off is tolerated",
+			original:  paragraphs(3),
+			generated: paragraphs(4),
+			want:      true,
+		},
+		{
+			name:      "two paragraphs off a short text",
+			original:  paragraphs(3),
+			generated: paragraphs(1),
+			want:      false,
+		},
+		{
+			name:      "a fifth off a long text is tolerated",
+			original:  paragraphs(10),
+			generated: paragraphs(12),
+			want:      true,
+		},
+		{
+			name:      "more than a fifth off a long text",
+			original:  paragraphs(10),
+			generated: paragraphs(13),
+			want:      false,
+		},
+		{
+			name:      "blank lines and CRLF don't count",
+			original:  paragraphs(3),
+			generated: "Satu paragraf pendek.\r\n\r\n\r\n   \r\nSatu paragraf pendek.\r\nSatu paragraf pendek.",
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkParagraphs(tt.original, tt.generated)
+			if got.IsPassed != tt.want {
+				t.Errorf("IsPassed = %t, want %t: %s", got.IsPassed, tt.want, got.Message)
+			}
+		})
+	}
+}
+
+func TestCountSyllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"buku", 2},
+		{"makan", 2},
+		{"strategi", 3},
+		{"pantai", 2},
+		{"pulau", 2},
+		{"sepoi", 2},
+		{"Rumah,", 2},
+		{"\"Indonesia\"", 5},
+		{"x", 1},
+		{"123", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := countSyllables(tt.word); got != tt.want {
+				t.Errorf("countSyllables(%q) = %d, want %d", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckReadability(t *testing.T) {
+	const (
+		hard = "Pemerintah daerah mengumumkan kebijakan pengelolaan sampah yang komprehensif, berkelanjutan dan melibatkan partisipasi masyarakat secara menyeluruh di seluruh kecamatan."
+		easy = "Kota punya aturan baru. Sampah harus dipilah. Warga ikut membantu."
+	)
+
+	tests := []struct {
+		name      string
+		target    ArticleTextDifficultyPreset
+		generated string
+		want      bool
+	}{
+		{
+			name:      "easier text",
+			target:    BEGINNER,
+			generated: easy,
+			want:      true,
+		},
+		{
+			name:      "unchanged text",
+			target:    BEGINNER,
+			generated: hard,
+			want:      false,
+		},
+		{
+			name:      "harder text",
+			target:    INTERMEDIATE,
+			generated: hard + " " + hard,
+			want:      false,
+		},
+		{
+			name:      "not adapted to an easier difficulty",
+			target:    ADVANCED,
+			generated: hard,
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkReadability(string(ADVANCED), string(tt.target), hard, tt.generated)
+			if got.IsPassed != tt.want {
+				t.Errorf("IsPassed = %t, want %t: %s", got.IsPassed, tt.want, got.Message)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ var (
 	ErrArticleSourceEmpty         = validation.NewError("article:source_empty", "Source can't be empty")
 	ErrArticleSourceToolong       = validation.NewError("article:source_too_long", "Source can't be longer than 255 characters")
 	ErrArticleAuthorTooLong       = validation.NewError("article:author_too_long", "Author can't be longer than 255 characters")
+	ErrInvalidArticleCursor       = validation.NewError("article:invalid_cursor", "Invalid article cursor")
 )
 
 func validateArticleId(idStr string) (id ulid.ULID, err error) {
@@ -1,6 +1,9 @@
 package article
 
 import (
+	"time"
+
+	"github.com/lexica-app/lexicapi/app/moderation"
 	"gopkg.in/guregu/null.v4"
 )
 
@@ -18,17 +21,47 @@ type Articles struct {
 
 type ArticleViewModel struct {
 	Article
-	Teaser       string `json:"teaser"`
-	CategoryName string `json:"category_name"`
+	Teaser                string   `json:"teaser"`
+	CategoryName          string   `json:"category_name"`
+	WordCount             uint     `json:"word_count"`
+	ReadingTimeMinutes    uint     `json:"reading_time_minutes"`
+	AvailableDifficulties []string `json:"available_difficulties"`
 }
 
 type ArticleDetail struct {
 	Article
-	CategoryName string                 `json:"category_name"`
-	Texts        map[string]ArticleText `json:"texts"`
+	CategoryName string                   `json:"category_name"`
+	Texts        map[string]ArticleText   `json:"texts"`
+	Series       *ArticleSeriesNavigation `json:"series"`
+
+	categoryUpdatedAt time.Time
+}
+
+// LastModified is the latest change to anything the detail shows, including
+// a rename of its category.
+func (d ArticleDetail) LastModified() time.Time {
+	lastModified := d.Article.LastModified()
+	if d.categoryUpdatedAt.After(lastModified) {
+		lastModified = d.categoryUpdatedAt
+	}
+	for _, text := range d.Texts {
+		if text.LastModified().After(lastModified) {
+			lastModified = text.LastModified()
+		}
+	}
+
+	return lastModified
 }
 
 type ArticleWithRowNumber struct {
 	ArticleViewModel
-	Row uint `json:"row"`
+	Row       uint `json:"row"`
+	SortValue any  `json:"-"`
+}
+
+// GeneratedArticleText is a generated text with the moderation verdict it was
+// saved with, a REVIEW decision means it waits on an admin.
+type GeneratedArticleText struct {
+	ArticleText
+	Moderation moderation.Verdict `json:"moderation"`
 }
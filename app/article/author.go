@@ -0,0 +1,70 @@
+package article
+
+import (
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Author is the canonical writer of articles. Aliases hold the lowercased
+// names of authors merged into it so they keep resolving here.
+type Author struct {
+	Id        ulid.ULID `json:"id"`
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt null.Time `json:"updated_at"`
+	DeletedAt null.Time `json:"deleted_at"`
+}
+
+func NewAuthor(name string) (author Author, err error) {
+	name = strings.TrimSpace(name)
+	if err = validateAuthorName(name); err != nil {
+		return author, err
+	}
+
+	return Author{
+		Id:        ulid.Make(),
+		Name:      name,
+		Aliases:   []string{},
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (a *Author) Update(name string) error {
+	if err := validateAuthorName(name); err != nil {
+		return err
+	}
+
+	a.Name = strings.TrimSpace(name)
+	a.UpdatedAt = null.TimeFrom(time.Now())
+
+	return nil
+}
+
+// Merge takes over the names and aliases of the other authors.
+func (a *Author) Merge(others []*Author) {
+	aliases := make(map[string]bool)
+	for _, alias := range a.Aliases {
+		aliases[alias] = true
+	}
+
+	for _, other := range others {
+		for _, alias := range append([]string{strings.ToLower(other.Name)}, other.Aliases...) {
+			if alias != strings.ToLower(a.Name) && !aliases[alias] {
+				aliases[alias] = true
+				a.Aliases = append(a.Aliases, alias)
+			}
+		}
+	}
+
+	a.UpdatedAt = null.TimeFrom(time.Now())
+}
+
+func (a *Author) Delete() {
+	if !a.DeletedAt.Valid {
+		a.DeletedAt = null.TimeFrom(time.Now())
+	}
+}
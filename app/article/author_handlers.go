@@ -0,0 +1,165 @@
+package article
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+)
+
+func getAuthorsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := r.URL.Query().Get("q")
+	limitStr := r.URL.Query().Get("limit")
+
+	// Don't throw error to client just because of misinputs
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+
+	authors, err := getAuthors(ctx, query, uint(limit))
+	if err != nil {
+		switch err {
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, authors)
+}
+
+func getAuthorDetailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+	author, err := getAuthorDetail(ctx, id, newGetArticlesReq(r))
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidAuthorId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrAuthorDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, author)
+}
+
+func createAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body createAuthorReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	author, err := createAuthor(ctx, body)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidAuthorId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrAuthorNameExists):
+			app.WriteHttpError(w, http.StatusConflict, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusCreated, author)
+}
+
+func updateAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+
+	var body updateAuthorReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	author, err := updateAuthor(ctx, id, body)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidAuthorId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrAuthorNameExists):
+			app.WriteHttpError(w, http.StatusConflict, err)
+		case errors.Is(err, ErrAuthorDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, author)
+}
+
+func removeAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+	if err := removeAuthor(ctx, id); err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidAuthorId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrAuthorInUse):
+			app.WriteHttpError(w, http.StatusConflict, err)
+		case errors.Is(err, ErrAuthorDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func mergeAuthorsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+
+	var body mergeAuthorsReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	author, err := mergeAuthors(ctx, id, body)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidAuthorId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrAuthorNameExists):
+			app.WriteHttpError(w, http.StatusConflict, err)
+		case errors.Is(err, ErrAuthorDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, author)
+}
@@ -0,0 +1,221 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrAuthorDoesNotExist = errors.New("Author does not exist")
+	ErrAuthorNameExists   = errors.New("Author with that name exists")
+	ErrAuthorInUse        = errors.New("Author still has articles, merge it into another author instead")
+)
+
+func findAuthors(ctx context.Context, tx pgx.Tx, search string, limit uint) (authors []*AuthorViewModel, err error) {
+	q := `
+	SELECT
+	  au.*,
+	  (SELECT COUNT(*) FROM articles a WHERE a.author_id = au.id AND a.is_published IS TRUE AND a.deleted_at IS NULL) article_count
+	FROM authors au
+	WHERE au.name ILIKE '%' || $1 || '%' AND au.deleted_at IS NULL
+	ORDER BY au.name
+	LIMIT $2
+	`
+
+	authors = []*AuthorViewModel{}
+	if err = pgxscan.Select(ctx, tx, &authors, q, search, limit); err != nil {
+		log.Err(err).Msg("Failed to find authors")
+		return
+	}
+
+	return authors, nil
+}
+
+func findAuthorById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (author Author, err error) {
+	q := "SELECT * FROM authors WHERE id = $1 AND deleted_at IS NULL"
+
+	if err = pgxscan.Get(ctx, tx, &author, q, id); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return author, ErrAuthorDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find author by id")
+		return author, err
+	}
+
+	return author, nil
+}
+
+func findAuthorsByIds(ctx context.Context, tx pgx.Tx, ids []ulid.ULID) (authors []*Author, err error) {
+	q := "SELECT * FROM authors WHERE id = ANY($1) AND deleted_at IS NULL"
+
+	authors = []*Author{}
+	if err = pgxscan.Select(ctx, tx, &authors, q, ulidBytes(ids)); err != nil {
+		log.Err(err).Msg("Failed to find authors by ids")
+		return
+	}
+
+	if len(authors) != len(ids) {
+		return authors, ErrAuthorDoesNotExist
+	}
+
+	return authors, nil
+}
+
+// findAuthorByName matches either the canonical name or a merged alias.
+func findAuthorByName(ctx context.Context, tx pgx.Tx, name string) (author Author, err error) {
+	q := `
+	SELECT * FROM authors
+	WHERE deleted_at IS NULL AND (LOWER(name) = $1 OR $1 = ANY(aliases))
+	ORDER BY (CASE WHEN LOWER(name) = $1 THEN 0 ELSE 1 END)
+	LIMIT 1
+	`
+
+	if err = pgxscan.Get(ctx, tx, &author, q, strings.ToLower(strings.TrimSpace(name))); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return author, ErrAuthorDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find author by name")
+		return author, err
+	}
+
+	return author, nil
+}
+
+func saveAuthor(ctx context.Context, tx pgx.Tx, author Author) (Author, error) {
+	q := `
+	INSERT INTO authors(id, name, aliases, created_at) VALUES
+	($1, $2, $3, $4)
+	RETURNING *
+	`
+
+	var newAuthor Author
+	if err := pgxscan.Get(ctx, tx, &newAuthor, q, author.Id, author.Name, author.Aliases, author.CreatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				return author, ErrAuthorNameExists
+			}
+		}
+
+		log.Err(err).Msg("Failed to save author")
+		return author, err
+	}
+
+	return newAuthor, nil
+}
+
+// updateAuthorById also renames the author on their articles.
+func updateAuthorById(ctx context.Context, tx pgx.Tx, author Author) (updatedAuthor Author, err error) {
+	q := `
+	UPDATE authors
+	SET name = $1, aliases = $2, updated_at = $3
+	WHERE id = $4 AND deleted_at IS NULL
+	RETURNING *
+	`
+
+	if err = pgxscan.Get(ctx, tx, &updatedAuthor, q, author.Name, author.Aliases, author.UpdatedAt, author.Id); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				return author, ErrAuthorNameExists
+			}
+		}
+
+		if err.Error() == "scanning one: no rows in result set" {
+			return author, ErrAuthorDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to update author")
+		return author, err
+	}
+
+	if _, err = tx.Exec(ctx, "UPDATE articles SET author = $1, updated_at = NOW(), version = version + 1 WHERE author_id = $2", updatedAuthor.Name, updatedAuthor.Id); err != nil {
+		log.Err(err).Msg("Failed to update author")
+		return author, err
+	}
+
+	return updatedAuthor, nil
+}
+
+func deleteAuthor(ctx context.Context, tx pgx.Tx, author Author) (err error) {
+	var inUse bool
+	if err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM articles WHERE author_id = $1)", author.Id).Scan(&inUse); err != nil {
+		log.Err(err).Msg("Failed to delete author")
+		return err
+	}
+	if inUse {
+		return ErrAuthorInUse
+	}
+
+	if _, err = tx.Exec(ctx, "UPDATE authors SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", author.DeletedAt, author.Id); err != nil {
+		log.Err(err).Msg("Failed to delete author")
+		return err
+	}
+
+	return nil
+}
+
+// saveMergedAuthors moves every article of the merged authors to the target and
+// deletes the merged authors. The target must already carry their aliases.
+func saveMergedAuthors(ctx context.Context, tx pgx.Tx, target Author, merged []*Author) (Author, error) {
+	ids := make([]ulid.ULID, 0, len(merged))
+	for _, author := range merged {
+		ids = append(ids, author.Id)
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE authors SET deleted_at = NOW() WHERE id = ANY($1) AND deleted_at IS NULL", ulidBytes(ids)); err != nil {
+		log.Err(err).Msg("Failed to merge authors")
+		return target, err
+	}
+
+	if _, err := tx.Exec(
+		ctx,
+		"UPDATE articles SET author_id = $1, author = $2, updated_at = NOW(), version = version + 1 WHERE author_id = ANY($3)",
+		target.Id,
+		target.Name,
+		ulidBytes(ids),
+	); err != nil {
+		log.Err(err).Msg("Failed to merge authors")
+		return target, err
+	}
+
+	return updateAuthorById(ctx, tx, target)
+}
+
+// resolveArticleAuthor links the article to the author its name resolves to,
+// creating one when nothing matches. Articles without an author are unlinked.
+func resolveArticleAuthor(ctx context.Context, tx pgx.Tx, article *Article) error {
+	if strings.TrimSpace(article.Author.String) == "" {
+		article.AuthorId = nil
+		article.Author = null.NewString("", false)
+		return nil
+	}
+
+	author, err := findAuthorByName(ctx, tx, article.Author.String)
+	if err == ErrAuthorDoesNotExist {
+		var newAuthor Author
+		if newAuthor, err = NewAuthor(article.Author.String); err != nil {
+			return err
+		}
+
+		author, err = saveAuthor(ctx, tx, newAuthor)
+	}
+	if err != nil {
+		return err
+	}
+
+	article.AuthorId = &author.Id
+	article.Author = null.StringFrom(author.Name)
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package article
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+func getAuthors(ctx context.Context, query string, limit uint) (authors []*AuthorViewModel, err error) {
+	query = strings.TrimSpace(query)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get authors")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	authors, err = findAuthors(ctx, tx, query, limit)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get authors")
+		return
+	}
+
+	return authors, nil
+}
+
+func getAuthorDetail(ctx context.Context, idStr string, req getArticlesReq) (detail AuthorDetail, err error) {
+	id, err := validateAuthorId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get author detail")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	author, err := findAuthorById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get author detail")
+		return
+	}
+
+	req.AuthorIds = []string{author.Id.String()}
+	articles, err := getArticles(ctx, req)
+	if err != nil {
+		return
+	}
+
+	return AuthorDetail{Author: author, Articles: articles}, nil
+}
+
+func createAuthor(ctx context.Context, body createAuthorReq) (author Author, err error) {
+	author, err = NewAuthor(body.Name)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to create author")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	author, err = saveAuthor(ctx, tx, author)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to create author")
+		return
+	}
+
+	return author, nil
+}
+
+func updateAuthor(ctx context.Context, idStr string, body updateAuthorReq) (author Author, err error) {
+	id, err := validateAuthorId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to update author")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	author, err = findAuthorById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	if err = author.Update(body.Name); err != nil {
+		return
+	}
+
+	author, err = updateAuthorById(ctx, tx, author)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to update author")
+		return
+	}
+
+	return author, nil
+}
+
+func removeAuthor(ctx context.Context, idStr string) (err error) {
+	id, err := validateAuthorId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to remove author")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	author, err := findAuthorById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	author.Delete()
+	if err = deleteAuthor(ctx, tx, author); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to remove author")
+		return
+	}
+
+	return nil
+}
+
+func mergeAuthors(ctx context.Context, idStr string, body mergeAuthorsReq) (author Author, err error) {
+	id, err := validateAuthorId(idStr)
+	if err != nil {
+		return
+	}
+
+	mergedIds, err := validateAuthorMergeIds(id, body.AuthorIds)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to merge authors")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	author, err = findAuthorById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	merged, err := findAuthorsByIds(ctx, tx, mergedIds)
+	if err != nil {
+		return
+	}
+
+	author.Merge(merged)
+	author, err = saveMergedAuthors(ctx, tx, author, merged)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to merge authors")
+		return
+	}
+
+	return author, nil
+}
@@ -0,0 +1,55 @@
+package article
+
+import (
+	"strings"
+
+	"github.com/jellydator/validation"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrInvalidAuthorId     = validation.NewError("article:invalid_author_id", "Invalid author id")
+	ErrAuthorNameEmpty     = validation.NewError("article:author_name_empty", "Author name can't be empty")
+	ErrAuthorNameTooLong   = validation.NewError("article:author_name_too_long", "Author name can't be longer than 255 characters")
+	ErrAuthorMergeEmpty    = validation.NewError("article:author_merge_empty", "At least one author to merge is required")
+	ErrAuthorMergeIntoSelf = validation.NewError("article:author_merge_into_self", "An author can't be merged into itself")
+)
+
+func validateAuthorId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidAuthorId
+	}
+
+	return id, nil
+}
+
+func validateAuthorName(name string) error {
+	name = strings.TrimSpace(name)
+	return validation.Validate(
+		&name,
+		validation.Required.ErrorObject(ErrAuthorNameEmpty),
+		validation.Length(1, 255).ErrorObject(ErrAuthorNameTooLong),
+	)
+}
+
+// validateAuthorMergeIds parses the ids of the authors merged into target.
+func validateAuthorMergeIds(target ulid.ULID, idStrs []string) (ids []ulid.ULID, err error) {
+	if len(idStrs) == 0 {
+		return nil, ErrAuthorMergeEmpty
+	}
+
+	for _, idStr := range idStrs {
+		id, err := validateAuthorId(idStr)
+		if err != nil {
+			return nil, err
+		}
+		if id.Compare(target) == 0 {
+			return nil, ErrAuthorMergeIntoSelf
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,11 @@
+package article
+
+type AuthorViewModel struct {
+	Author
+	ArticleCount uint `json:"article_count"`
+}
+
+type AuthorDetail struct {
+	Author
+	Articles Articles `json:"articles"`
+}
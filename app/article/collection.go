@@ -28,6 +28,7 @@ type Collection struct {
 	CreatorId  ulid.ULID            `json:"creator_id"`
 	Name       string               `json:"name"`
 	Visibility CollectionVisibility `json:"visibility"`
+	Version    uint                 `json:"version"`
 	CreatedAt  time.Time            `json:"created_at"`
 	UpdatedAt  null.Time            `json:"updated_at"`
 	DeletedAt  null.Time            `json:"deleted_at"`
@@ -59,6 +60,7 @@ func NewCollection(creatorIdStr, name, visibilityStr string) (Collection, map[st
 		CreatorId: creatorId,
 		Name: name,
 		Visibility: visibility,
+		Version: 1,
 		CreatedAt: time.Now(),
 	}, nil
 }
@@ -106,4 +108,11 @@ func (c *Collection) Delete(creatorId ulid.ULID) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (c *Collection) Restore() {
+	if c.DeletedAt.Valid {
+		c.DeletedAt = null.NewTime(time.Time{}, false)
+		c.UpdatedAt = null.TimeFrom(time.Now())
+	}
+}
@@ -39,7 +39,7 @@ func createCollectionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusCreated, collection)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusCreated, collection, collection.Version)
 }
 
 func updateCollectionHandler(w http.ResponseWriter, r *http.Request) {
@@ -52,6 +52,11 @@ func updateCollectionHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	collectionId := chi.URLParam(r, "collectionId")
+	version, err := app.IfMatchVersion(r)
+	if err != nil {
+		app.WriteHttpIfMatchError(w, err)
+		return
+	}
 
 	var body updateCollectionReq
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -59,7 +64,7 @@ func updateCollectionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	collection, errs, err := updateCollection(ctx, collectionId, user.Id.String(), body)
+	collection, errs, err := updateCollection(ctx, collectionId, user.Id.String(), version, body)
 	if errs != nil {
 		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
 		return
@@ -72,6 +77,8 @@ func updateCollectionHandler(w http.ResponseWriter, r *http.Request) {
 			app.WriteHttpError(w, http.StatusNotFound, err)
 		case errors.Is(err, ErrNotAllowedToUpdateCollection):
 			app.WriteHttpError(w, http.StatusForbidden, err)
+		case errors.Is(err, ErrCollectionVersionConflict):
+			app.WriteHttpPreconditionFailed(w, err, collection, collection.Version)
 		default:
 			app.WriteHttpInternalServerError(w)
 		}
@@ -79,7 +86,7 @@ func updateCollectionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusCreated, collection)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusCreated, collection, collection.Version)
 }
 
 func deleteCollectionHandler(w http.ResponseWriter, r *http.Request) {
@@ -137,7 +144,7 @@ func getCollectionDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, collection)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusOK, collection, collection.Version)
 }
 
 func getOwnCollectionsHandler(w http.ResponseWriter, r *http.Request) {
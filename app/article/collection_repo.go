@@ -13,7 +13,8 @@ import (
 )
 
 var (
-	ErrCollectionDoesNotExist = errors.New("collection does not exist")
+	ErrCollectionDoesNotExist    = errors.New("collection does not exist")
+	ErrCollectionVersionConflict = errors.New("collection was changed by someone else since it was loaded")
 )
 
 func findCollectionById(ctx context.Context, tx pgx.Tx, collectionId ulid.ULID) (collection Collection, err error) {
@@ -101,8 +102,11 @@ func findCollectionDetail(ctx context.Context, tx pgx.Tx, collectionId, userId u
 	q = `
 	SELECT
 	  a.*,
-	  (CASE WHEN ac.deleted_at IS NULL THEN ac.name ELSE 'Deleted Category' END) category_name,
-	  (CASE WHEN LENGTH(at.content) >= 255 THEN SUBSTRING(at.content, 1, 255) || '...' ELSE at.content END) teaser
+	  ac.name category_name,
+	  (CASE WHEN LENGTH(at.content) >= 255 THEN SUBSTRING(at.content, 1, 255) || '...' ELSE at.content END) teaser,
+	  at.word_count,
+	  at.reading_time_minutes,
+	  ` + availableDifficultiesColumn + `
 	FROM articles a
 	INNER JOIN article_categories ac
 	ON a.category_id = ac.id
@@ -116,6 +120,7 @@ func findCollectionDetail(ctx context.Context, tx pgx.Tx, collectionId, userId u
 	  a.is_published = TRUE AND
 	  a.deleted_at IS NULL AND
 	  at.difficulty = 'ADVANCED' AND
+	  at.moderation_status = 'APPROVED' AND
 	  at.deleted_at IS NULL AND
 	  c.id = $1 AND
 	  ca.deleted_at IS NULL
@@ -129,7 +134,7 @@ func findCollectionDetail(ctx context.Context, tx pgx.Tx, collectionId, userId u
 
 	detail = CollectionDetail{
 		CollectionMetadata: metadata,
-		Articles: articles,
+		Articles:           articles,
 	}
 
 	return detail, nil
@@ -138,8 +143,8 @@ func findCollectionDetail(ctx context.Context, tx pgx.Tx, collectionId, userId u
 func updateCollectionEntity(ctx context.Context, tx pgx.Tx, collection Collection) (Collection, error) {
 	q := `
 	UPDATE collections
-	SET name = $2, visibility = $3, updated_at = $4
-	WHERE id = $1 AND deleted_at IS NULL
+	SET name = $2, visibility = $3, updated_at = $4, version = version + 1
+	WHERE id = $1 AND version = $5 AND deleted_at IS NULL
 	RETURNING *
 	`
 
@@ -152,9 +157,10 @@ func updateCollectionEntity(ctx context.Context, tx pgx.Tx, collection Collectio
 		collection.Name,
 		collection.Visibility,
 		collection.UpdatedAt,
+		collection.Version,
 	); err != nil {
 		if err.Error() == "scanning one: no rows in result set" {
-			return Collection{}, ErrCollectionDoesNotExist
+			return Collection{}, ErrCollectionVersionConflict
 		}
 
 		log.Err(err).Msg("Failed to update collection entity")
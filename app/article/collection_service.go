@@ -62,7 +62,9 @@ func getCollectionDetail(ctx context.Context, collectionIdStr string, userId uli
 	return detail, nil
 }
 
-func updateCollection(ctx context.Context, collectionIdStr, creatorIdStr string, body updateCollectionReq) (collection Collection, errs map[string]error, err error) {
+// updateCollection applies body only when the collection is still at version.
+// On a conflict the current collection is returned with the error.
+func updateCollection(ctx context.Context, collectionIdStr, creatorIdStr string, version uint, body updateCollectionReq) (collection Collection, errs map[string]error, err error) {
 	collectionId, err := validateCollectionId(collectionIdStr)
 	if err != nil {
 		return
@@ -90,9 +92,22 @@ func updateCollection(ctx context.Context, collectionIdStr, creatorIdStr string,
 	if err != nil || errs != nil {
 		return
 	}
+	if collection.Version != version {
+		collection, err = findCollectionById(ctx, tx, collectionId)
+		if err == nil {
+			err = ErrCollectionVersionConflict
+		}
+		return
+	}
 
 	collection, err = updateCollectionEntity(ctx, tx, collection)
 	if err != nil {
+		// Someone committed in between the read and the write
+		if err == ErrCollectionVersionConflict {
+			if collection, err = findCollectionById(ctx, tx, collectionId); err == nil {
+				err = ErrCollectionVersionConflict
+			}
+		}
 		return
 	}
 
@@ -2,21 +2,85 @@ package article
 
 import (
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/lexica-app/lexicapi/adapters"
 	"github.com/rs/zerolog/log"
-	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	defaultTrashRetentionDays = 30
+	defaultChunkTokens        = 1500
+	defaultChunkConcurrency   = 1
+	defaultQualityMaxAttempts = 3
 )
 
 var (
-	openAIAdapter *openai.Client
+	llmAdapter         adapters.LLM
+	trashRetention     time.Duration
+	previewTokenIssuer string
+	previewTokenSecret []byte
+	chunkTokens        = defaultChunkTokens
+	chunkConcurrency   = defaultChunkConcurrency
+	qualityMaxAttempts = defaultQualityMaxAttempts
 
-	ErrNilOpenAIAdapter = errors.New("OpenAI adapter can't be nil")
+	ErrNilLLMAdapter           = errors.New("LLM adapter can't be nil")
+	ErrPreviewTokenIssuerEmpty = errors.New("Preview token issuer can't be empty")
+	ErrPreviewTokenSecretEmpty = errors.New("Preview token secret can't be empty")
 )
 
-func SetOpenAIAdapter(adapter *openai.Client) {
+func SetLLMAdapter(adapter adapters.LLM) {
 	if adapter == nil {
-		log.Fatal().Err(ErrNilOpenAIAdapter).Msg("Failed to set OpenAI adapter for article module")
+		log.Fatal().Err(ErrNilLLMAdapter).Msg("Failed to set LLM adapter for article module")
+	}
+
+	llmAdapter = adapter
+}
+
+func ConfigureTrashRetention(days int) {
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+
+	trashRetention = time.Duration(days) * 24 * time.Hour
+}
+
+func ConfigurePreviewTokens(issuer, secret string) {
+	issuer = strings.TrimSpace(issuer)
+	if len(issuer) == 0 {
+		log.Fatal().Err(ErrPreviewTokenIssuerEmpty).Msg("Failed to configure article preview tokens")
+	}
+	secret = strings.TrimSpace(secret)
+	if len(secret) == 0 {
+		log.Fatal().Err(ErrPreviewTokenSecretEmpty).Msg("Failed to configure article preview tokens")
+	}
+
+	previewTokenIssuer = issuer
+	previewTokenSecret = []byte(secret)
+}
+
+// ConfigureChunking sets the estimated size in tokens of the parts long texts
+// are split into before they're adapted, and how many parts are adapted at the
+// same time. A concurrency of 1 adapts the parts one after another.
+func ConfigureChunking(tokens, concurrency int) {
+	if tokens <= 0 {
+		tokens = defaultChunkTokens
+	}
+	if concurrency <= 0 {
+		concurrency = defaultChunkConcurrency
+	}
+
+	chunkTokens = tokens
+	chunkConcurrency = concurrency
+}
+
+// ConfigureQualityChecks sets how many times a generated text that fails the
+// quality checks is generated in total, 1 never regenerates.
+func ConfigureQualityChecks(maxAttempts int) {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultQualityMaxAttempts
 	}
 
-	openAIAdapter = adapter
+	qualityMaxAttempts = maxAttempts
 }
@@ -6,9 +6,12 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/adapters"
 	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/moderation"
 )
 
 func regenerateOpenAIArticleTextHandler(w http.ResponseWriter, r *http.Request) {
@@ -16,6 +19,11 @@ func regenerateOpenAIArticleTextHandler(w http.ResponseWriter, r *http.Request)
 
 	id := chi.URLParam(r, "id")
 	articleId := chi.URLParam(r, "articleId")
+	version, err := app.IfMatchVersion(r)
+	if err != nil {
+		app.WriteHttpIfMatchError(w, err)
+		return
+	}
 
 	var body regenerateOpenAIArticleTextReq
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -23,34 +31,44 @@ func regenerateOpenAIArticleTextHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	text, errs, err := regenerateOpenAIArticleText(ctx, id, articleId, body)
+	text, errs, err := regenerateOpenAIArticleText(ctx, id, articleId, version, body)
 	if errs != nil {
 		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
 		return
 	}
 	if err != nil {
+		var flaggedErr *moderation.FlaggedError
+		var qualityErr *ArticleTextQualityError
 		switch {
 		case errors.As(err, &ErrInvalidArticleId),
 			errors.As(err, &ErrInvalidArticleTextId),
 			errors.As(err, &ErrInvalidArticleTextDifficulty),
-			errors.Is(err, ErrArticleTextDifficultyExist):
+			errors.Is(err, ErrArticleTextDifficultyExist),
+			errors.Is(err, adapters.ErrInvalidLLMTaskSettings):
 			app.WriteHttpError(w, http.StatusBadRequest, err)
-		case errors.Is(err, ErrInvalidOpenAIAPIKey):
-			app.WriteHttpError(w, http.StatusUnauthorized, err)
 		case errors.Is(err, ErrArticleDoesNotExist), errors.Is(err, ErrArticleTextDoesNotExist):
 			app.WriteHttpError(w, http.StatusNotFound, err)
-		case errors.Is(err, ErrOpenAIRateLimited):
-			app.WriteHttpError(w, http.StatusTooManyRequests, err)
-		case errors.Is(err, ErrOpenAIServiceError):
-			app.WriteHttpError(w, http.StatusServiceUnavailable, err)
+		case errors.Is(err, ErrArticleTextVersionConflict):
+			app.WriteHttpPreconditionFailed(w, err, text.ArticleText, text.Version)
+		case errors.Is(err, ErrArticleTextTruncated):
+			app.WriteHttpError(w, http.StatusBadGateway, err)
+		case errors.Is(err, ErrGeneratedArticleDocumentInvalid):
+			app.WriteHttpError(w, http.StatusUnprocessableEntity, err)
+		case errors.As(err, &flaggedErr):
+			moderation.WriteFlaggedError(w, flaggedErr)
+		case errors.As(err, &qualityErr):
+			app.WriteHttpBodyJson(w, http.StatusUnprocessableEntity, map[string]any{
+				"message": qualityErr.Error(),
+				"quality": qualityErr.Quality,
+			})
 		default:
-			app.WriteHttpInternalServerError(w)
+			app.WriteHttpLLMError(w, err)
 		}
 
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, text)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusOK, text, text.Version)
 }
 
 func generateOpenAIArticleTextHandler(w http.ResponseWriter, r *http.Request) {
@@ -68,19 +86,29 @@ func generateOpenAIArticleTextHandler(w http.ResponseWriter, r *http.Request) {
 		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
 	}
 	if err != nil {
+		var flaggedErr *moderation.FlaggedError
+		var qualityErr *ArticleTextQualityError
 		switch {
-		case errors.As(err, &ErrInvalidArticleId), errors.As(err, &ErrInvalidArticleTextDifficulty), errors.Is(err, ErrArticleTextDifficultyExist):
+		case errors.As(err, &ErrInvalidArticleId),
+			errors.As(err, &ErrInvalidArticleTextDifficulty),
+			errors.Is(err, ErrArticleTextDifficultyExist),
+			errors.Is(err, adapters.ErrInvalidLLMTaskSettings):
 			app.WriteHttpError(w, http.StatusBadRequest, err)
-		case errors.Is(err, ErrInvalidOpenAIAPIKey):
-			app.WriteHttpError(w, http.StatusUnauthorized, err)
 		case errors.Is(err, ErrArticleDoesNotExist):
 			app.WriteHttpError(w, http.StatusNotFound, err)
-		case errors.Is(err, ErrOpenAIRateLimited):
-			app.WriteHttpError(w, http.StatusTooManyRequests, err)
-		case errors.Is(err, ErrOpenAIServiceError):
-			app.WriteHttpError(w, http.StatusServiceUnavailable, err)
+		case errors.Is(err, ErrArticleTextTruncated):
+			app.WriteHttpError(w, http.StatusBadGateway, err)
+		case errors.Is(err, ErrGeneratedArticleDocumentInvalid):
+			app.WriteHttpError(w, http.StatusUnprocessableEntity, err)
+		case errors.As(err, &flaggedErr):
+			moderation.WriteFlaggedError(w, flaggedErr)
+		case errors.As(err, &qualityErr):
+			app.WriteHttpBodyJson(w, http.StatusUnprocessableEntity, map[string]any{
+				"message": qualityErr.Error(),
+				"quality": qualityErr.Quality,
+			})
 		default:
-			app.WriteHttpInternalServerError(w)
+			app.WriteHttpLLMError(w, err)
 		}
 
 		return
@@ -111,7 +139,21 @@ func getArticleCategoriesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, categories)
+	var lastModified time.Time
+	for _, category := range categories {
+		if category.LastModified().After(lastModified) {
+			lastModified = category.LastModified()
+		}
+	}
+
+	// Admins edit categories one by one with If-Match on each version, a hash
+	// of the whole list would only be mistaken for one
+	if strings.HasPrefix(r.URL.Path, "/admin") {
+		app.WriteHttpBodyJson(w, http.StatusOK, categories)
+		return
+	}
+
+	app.WriteHttpBodyJsonConditional(w, r, http.StatusOK, categories, lastModified)
 }
 
 func getArticleCategoryByIdHandler(w http.ResponseWriter, r *http.Request) {
@@ -132,7 +174,12 @@ func getArticleCategoryByIdHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, category)
+	if strings.HasPrefix(r.URL.Path, "/admin") {
+		app.WriteHttpBodyJsonVersioned(w, http.StatusOK, category, category.Version)
+		return
+	}
+
+	app.WriteHttpBodyJsonConditional(w, r, http.StatusOK, category, category.LastModified())
 }
 
 func createArticleCategoryHandler(w http.ResponseWriter, r *http.Request) {
@@ -156,17 +203,22 @@ func createArticleCategoryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusCreated, category)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusCreated, category, category.Version)
 }
 
 func deleteArticleCategoryHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	id := chi.URLParam(r, "id")
-	if err := deleteArticleCategory(ctx, id); err != nil {
-		switch err {
-		case ErrInvalidArticleCategoryId:
+	reassignTo := r.URL.Query().Get("reassign_to")
+	if err := deleteArticleCategory(ctx, id, reassignTo); err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidArticleCategoryId):
 			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrArticleCategoryDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrArticleCategoryInUse):
+			app.WriteHttpError(w, http.StatusConflict, err)
 		default:
 			app.WriteHttpInternalServerError(w)
 		}
@@ -177,10 +229,43 @@ func deleteArticleCategoryHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func moveArticleCategoryArticlesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+
+	var body moveArticleCategoryArticlesReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	moved, err := moveArticleCategoryArticles(ctx, id, body)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidArticleCategoryId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrArticleCategoryDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, map[string]int64{"moved": moved})
+}
+
 func updateArticleCategoryHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	id := chi.URLParam(r, "id")
+	version, err := app.IfMatchVersion(r)
+	if err != nil {
+		app.WriteHttpIfMatchError(w, err)
+		return
+	}
 
 	var body updateArticleCategoryReq
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -188,9 +273,11 @@ func updateArticleCategoryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	category, err := updateArticleCategory(ctx, id, body.Name)
+	category, err := updateArticleCategory(ctx, id, body.Name, version)
 	if err != nil {
 		switch err {
+		case ErrArticleCategoryVersionConflict:
+			app.WriteHttpPreconditionFailed(w, err, category, category.Version)
 		case ErrArticleCategoryNameTooLong, ErrArticleCategoryNameEmpty,
 			ErrInvalidArticleCategoryId, ErrArticleCategoryNameExists:
 			app.WriteHttpError(w, http.StatusBadRequest, err)
@@ -203,34 +290,70 @@ func updateArticleCategoryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, category)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusOK, category, category.Version)
 }
 
 func getArticlesHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	q := r.URL.Query().Get("q")
-	categoryId := r.URL.Query().Get("category_id")
-	pageSizeStr := r.URL.Query().Get("page_size")
-	direction := r.URL.Query().Get("direction")
-	cursor := r.URL.Query().Get("cursor")
+	articles, err := getArticles(ctx, newGetArticlesReq(r))
+	if err != nil {
+		switch {
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
 
-	includeUnpublished := strings.HasPrefix(r.URL.Path, "/admin")
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/admin") {
+		app.WriteHttpBodyJson(w, http.StatusOK, articles)
+		return
+	}
+
+	// Listings have no reliable Last-Modified since removed articles leave no
+	// trace in the page, so they are validated by ETag alone
+	app.WriteHttpBodyJsonConditional(w, r, http.StatusOK, articles, time.Time{})
+}
+
+func newGetArticlesReq(r *http.Request) getArticlesReq {
+	query := r.URL.Query()
+	pageSizeStr := query.Get("page_size")
 
 	pageSize, err := strconv.Atoi(pageSizeStr)
 	if err != nil || pageSize <= 0 {
 		pageSize = 100
 	}
 
-	articles, err := getArticles(ctx, q, categoryId, uint(pageSize), direction, cursor, includeUnpublished)
-	if err != nil {
-		switch {
-		default:
-			app.WriteHttpInternalServerError(w)
-		}
+	return getArticlesReq{
+		Query:              query.Get("q"),
+		CategoryIds:        splitQueryValues(query["category_id"]),
+		SourceIds:          splitQueryValues(query["source_id"]),
+		AuthorIds:          splitQueryValues(query["author_id"]),
+		SeriesIds:          splitQueryValues(query["series_id"]),
+		Source:             query.Get("source"),
+		Author:             query.Get("author"),
+		PublishedFrom:      query.Get("published_from"),
+		PublishedUntil:     query.Get("published_until"),
+		Difficulties:       splitQueryValues(query["difficulty"]),
+		MinReadingTime:     query.Get("min_reading_time"),
+		MaxReadingTime:     query.Get("max_reading_time"),
+		Sort:               query.Get("sort"),
+		Direction:          query.Get("direction"),
+		Cursor:             query.Get("cursor"),
+		PageSize:           uint(pageSize),
+		IncludeUnpublished: strings.HasPrefix(r.URL.Path, "/admin"),
 	}
+}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, articles)
+// splitQueryValues accepts both ?key=a&key=b and ?key=a,b
+func splitQueryValues(values []string) []string {
+	split := []string{}
+	for _, value := range values {
+		split = append(split, strings.Split(value, ",")...)
+	}
+
+	return split
 }
 
 func createArticleHandler(w http.ResponseWriter, r *http.Request) {
@@ -258,14 +381,15 @@ func createArticleHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusCreated, article)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusCreated, article, article.Version)
 }
 
 func getArticleByIdHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	id := chi.URLParam(r, "id")
-	article, err := getArticleById(ctx, id)
+	isAdmin := strings.HasPrefix(r.URL.Path, "/admin")
+	article, err := getArticleById(ctx, id, isAdmin)
 	if err != nil {
 		switch {
 		case errors.As(err, &ErrInvalidArticleId):
@@ -279,20 +403,66 @@ func getArticleByIdHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, article)
+	// Admins get the version ETag their next edit sends back in If-Match
+	if isAdmin {
+		app.WriteHttpBodyJsonVersioned(w, http.StatusOK, article, article.Version)
+		return
+	}
+
+	app.WriteHttpBodyJsonConditional(w, r, http.StatusOK, article, article.LastModified())
+}
+
+func renderArticleTextHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	articleId := chi.URLParam(r, "articleId")
+	difficulty := r.URL.Query().Get("difficulty")
+	format := r.URL.Query().Get("format")
+
+	rendered, renderedFormat, err := renderArticleText(ctx, articleId, difficulty, format)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidArticleId), errors.Is(err, ErrInvalidArticleDocumentFormat):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrArticleDoesNotExist), errors.Is(err, ErrArticleTextDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	switch renderedFormat {
+	case HTML:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	case MARKDOWN:
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(rendered))
 }
 
 func updateArticleHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	id := chi.URLParam(r, "id")
+	version, err := app.IfMatchVersion(r)
+	if err != nil {
+		app.WriteHttpIfMatchError(w, err)
+		return
+	}
+
 	var body updateArticleReq
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		app.WriteHttpError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	article, errs, err := updateArticle(ctx, id, body)
+	article, errs, err := updateArticle(ctx, id, version, body)
 	if errs != nil {
 		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
 		return
@@ -303,6 +473,8 @@ func updateArticleHandler(w http.ResponseWriter, r *http.Request) {
 			app.WriteHttpError(w, http.StatusBadRequest, err)
 		case errors.Is(err, ErrArticleCategoryDoesNotExist), errors.Is(err, ErrArticleDoesNotExist):
 			app.WriteHttpError(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrArticleVersionConflict):
+			app.WriteHttpPreconditionFailed(w, err, article, article.Version)
 		default:
 			app.WriteHttpInternalServerError(w)
 		}
@@ -310,7 +482,7 @@ func updateArticleHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, article)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusOK, article, article.Version)
 }
 
 func removeArticleHandler(w http.ResponseWriter, r *http.Request) {
@@ -361,7 +533,7 @@ func createArticleTextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusCreated, text)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusCreated, text, text.Version)
 }
 
 func updateArticleTextHandler(w http.ResponseWriter, r *http.Request) {
@@ -369,6 +541,11 @@ func updateArticleTextHandler(w http.ResponseWriter, r *http.Request) {
 
 	id := chi.URLParam(r, "id")
 	articleId := chi.URLParam(r, "articleId")
+	version, err := app.IfMatchVersion(r)
+	if err != nil {
+		app.WriteHttpIfMatchError(w, err)
+		return
+	}
 
 	var body updateArticleTextReq
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -376,7 +553,7 @@ func updateArticleTextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	text, errs, err := updateArticleText(ctx, id, articleId, body)
+	text, errs, err := updateArticleText(ctx, id, articleId, version, body)
 	if errs != nil {
 		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
 		return
@@ -387,6 +564,8 @@ func updateArticleTextHandler(w http.ResponseWriter, r *http.Request) {
 			app.WriteHttpError(w, http.StatusBadRequest, err)
 		case errors.Is(err, ErrArticleTextDoesNotExist), errors.Is(err, ErrArticleDoesNotExist):
 			app.WriteHttpError(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrArticleTextVersionConflict):
+			app.WriteHttpPreconditionFailed(w, err, text, text.Version)
 		default:
 			app.WriteHttpInternalServerError(w)
 		}
@@ -394,7 +573,30 @@ func updateArticleTextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, text)
+	app.WriteHttpBodyJsonVersioned(w, http.StatusOK, text, text.Version)
+}
+
+func getArticleTextHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+	articleId := chi.URLParam(r, "articleId")
+
+	text, err := getArticleText(ctx, id, articleId)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidArticleTextId), errors.As(err, &ErrInvalidArticleId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrArticleTextDoesNotExist), errors.Is(err, ErrArticleDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJsonVersioned(w, http.StatusOK, text, text.Version)
 }
 
 func removeArticleTextHandler(w http.ResponseWriter, r *http.Request) {
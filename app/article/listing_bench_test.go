@@ -0,0 +1,100 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lexica-app/lexicapi/tools/articlebench/fixture"
+	"github.com/oklog/ulid/v2"
+)
+
+// The listing benchmarks run against a migrated database given by
+// BENCH_DB_DSN and are skipped without one. They share the articlebench
+// fixture of benchArticleCount articles, seeded once and removed when the run
+// ends:
+//
+//	BENCH_DB_DSN=postgres://... go test ./app/article -run '^$' -bench Listing -benchtime 20x
+const benchArticleCount = 100000
+
+var benchCategoryId ulid.ULID
+
+func TestMain(m *testing.M) {
+	dsn := os.Getenv("BENCH_DB_DSN")
+	if dsn == "" {
+		os.Exit(m.Run())
+	}
+
+	ctx := context.Background()
+	benchPool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to benchmark database:", err)
+		os.Exit(1)
+	}
+
+	SetPool(benchPool)
+	if err = fixture.Remove(ctx, benchPool); err == nil {
+		benchCategoryId, err = fixture.Seed(ctx, benchPool, benchArticleCount)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to seed listing fixture:", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	if err = fixture.Remove(ctx, benchPool); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to remove listing fixture:", err)
+	}
+	benchPool.Close()
+
+	os.Exit(code)
+}
+
+func BenchmarkListing(b *testing.B) {
+	if pool == nil {
+		b.Skip("BENCH_DB_DSN isn't set")
+	}
+
+	lastMonth := time.Now().AddDate(0, -1, 0).Format("2006-01-02")
+	scenarios := []struct {
+		name  string
+		req   getArticlesReq
+		pages int
+	}{
+		{name: "newest", req: getArticlesReq{}},
+		{name: "oldest", req: getArticlesReq{Sort: string(OLDEST)}},
+		{name: "title", req: getArticlesReq{Sort: string(TITLE)}},
+		{name: "search", req: getArticlesReq{Query: "dolor"}},
+		{name: "category", req: getArticlesReq{CategoryIds: []string{benchCategoryId.String()}}},
+		{name: "source", req: getArticlesReq{Source: fixture.Source}},
+		{name: "difficulty", req: getArticlesReq{Difficulties: []string{string(BEGINNER)}}},
+		{name: "reading time", req: getArticlesReq{MinReadingTime: "2", MaxReadingTime: "3"}},
+		{name: "published range", req: getArticlesReq{PublishedFrom: lastMonth}},
+		{name: "newest 10 pages", req: getArticlesReq{}, pages: 10},
+		{name: "title 10 pages", req: getArticlesReq{Sort: string(TITLE)}, pages: 10},
+	}
+
+	for _, s := range scenarios {
+		b.Run(s.name, func(b *testing.B) {
+			ctx := context.Background()
+			for i := 0; i < b.N; i++ {
+				req := s.req
+				req.PageSize = 20
+				for page := 0; page == 0 || page < s.pages; page++ {
+					articles, err := getArticles(ctx, req)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if !articles.Cursor.Valid {
+						break
+					}
+					req.Cursor = articles.Cursor.String
+				}
+			}
+		})
+	}
+}
@@ -4,77 +4,282 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/http"
+	"strings"
+	"sync"
 
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/lexica-app/lexicapi/app/prompt"
+	"github.com/lexica-app/lexicapi/app/usage"
 	"github.com/rs/zerolog/log"
-	"github.com/sashabaranov/go-openai"
 )
 
+// A truncated chunk is retried in smaller parts, at most this many times over
+const maxChunkSplits = 2
+
+// Headings are validated against this many runes, see validateArticleBlock
+const maxHeadingLength = 255
+
 var (
-	ErrInvalidOpenAIAPIKey = errors.New("Invalid OpenAI API key")
-	ErrOpenAIRateLimited   = errors.New("OpenAI has rate limited us due to too many requests. Please try again later")
-	ErrOpenAIServiceError  = errors.New("OpenAI service is currently unavailable. Please try again later")
+	ErrArticleTextTruncated            = errors.New("The generated article text was cut off by the model's output limit")
+	ErrGeneratedArticleDocumentInvalid = errors.New("The generated article document isn't valid")
 )
 
-func generateArticleText(ctx context.Context, originalDifficulty, targetDifficulty, text string) (generatedText string, err error) {
-	systemPrompt := `Kamu bertugas untuk menyederhanakan bacaan sesuai dengan level pemahaman baca yang diinginkan. Ada tiga level pemahaman baca:
-
-1. ADVANCED, ditujukan untuk teks yang butuh pemahaman baca tinggi. Seperti untuk orang-orang di dunia kerja dan mahasiswa.
-2. INTERMEDIATE, ditujukan untuk teks yang butuh pemahaman baca menengah. Seperti siswa-siswa SMP kelas 7 di Indonesia sampai SMA kelas 12.
-3. BEGINNER, ditujukan untuk teks yang butuh pemahaman baca pemula. Seperti siswa-siswa SD di Indonesia kelas 1 sampai 6.
-
-  User akan memberi tahu kamu apa level pemahaman baca dari bacaan yang diberi serta level pemahaman baca yang user inginkan. Lalu di bawahnya, user akan memberikan bacaan yang akan kamu sederhanakan ke level pemahaman baca yang user inginkan
-`
-	prompt := fmt.Sprintf(`Teks di bawah ini dalam level pemahaman baca %s. Saya ingin kamu menyederhanakan teks berikut ke level pemahaman baca %s:
-
-%s`, originalDifficulty, targetDifficulty, text)
-
-	res, err := openAIAdapter.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo16K,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: systemPrompt,
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-			MaxTokens:   8000,
-			Temperature: 0.8,
-		},
+// generateArticleText adapts a text that fits in one chunk with a single call.
+// Longer texts are split between paragraphs and every chunk is given a rolling
+// summary of the chunks before it, so the model keeps track of the article
+// even though it only sees a part of it. The adapted chunks are stitched back
+// together in order. Feedback on a rejected earlier attempt goes with every
+// chunk.
+func generateArticleText(ctx context.Context, settings adapters.LLMTaskSettings, originalDifficulty, targetDifficulty, text, feedback string) (generatedText, promptVersion string, err error) {
+	chunks := splitArticleChunks(text, chunkTokenLimit(settings))
+	if len(chunks) == 1 {
+		return adaptArticleChunk(ctx, settings, originalDifficulty, targetDifficulty, text, "", feedback, 0)
+	}
+
+	log.Info().Fields(map[string]any{
+		"chunks":      len(chunks),
+		"concurrency": chunkConcurrency,
+	}).Msg("Generating article text in chunks")
+
+	summaries, err := summarizeArticleChunks(ctx, chunks)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		errOnce   sync.Once
+		generated = make([]string, len(chunks))
+		versions  = make([]string, len(chunks))
+		semaphore = make(chan struct{}, chunkConcurrency)
 	)
 
+	for i := range chunks {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				// A chunk that never ran leaves a hole in the text, so the
+				// cancellation has to surface even when no other chunk failed
+				errOnce.Do(func() {
+					err = ctx.Err()
+				})
+				return
+			}
+
+			text, version, chunkErr := adaptArticleChunk(ctx, settings, originalDifficulty, targetDifficulty, chunks[i], summaries[i], feedback, 0)
+			if chunkErr != nil {
+				errOnce.Do(func() {
+					err = chunkErr
+					cancel()
+				})
+				return
+			}
+
+			generated[i], versions[i] = text, version
+		}(i)
+	}
+
+	wg.Wait()
 	if err != nil {
-		switch e := err.(type) {
-		case *openai.APIError:
-			switch e.HTTPStatusCode {
-			case http.StatusUnauthorized:
-				return generatedText, ErrInvalidOpenAIAPIKey
-			case http.StatusTooManyRequests:
-				return generatedText, ErrOpenAIRateLimited
-			case http.StatusInternalServerError, http.StatusServiceUnavailable:
-				log.Err(ErrOpenAIServiceError).Msg("Failed to generate OpenAI article text")
-				return generatedText, ErrOpenAIServiceError
-			default:
-				log.Err(err).Msg("Failed to generate OpenAI article text")
+		return "", "", err
+	}
+
+	return strings.Join(generated, "\n\n"), versions[0], nil
+}
+
+// chunkTokenLimit keeps every chunk well within the output limit since the
+// adapted text can come out longer than the original.
+func chunkTokenLimit(settings adapters.LLMTaskSettings) int {
+	limit := chunkTokens
+	if half := settings.MaxTokens / 2; half > 0 && half < limit {
+		limit = half
+	}
+
+	return limit
+}
+
+// adaptArticleChunk retries a chunk the model cut off in smaller parts, all of
+// them keep the summary of what came before the chunk.
+func adaptArticleChunk(ctx context.Context, settings adapters.LLMTaskSettings, originalDifficulty, targetDifficulty, text, summary, feedback string, splits int) (generatedText, promptVersion string, err error) {
+	generatedText, promptVersion, finishReason, err := completeArticleText(ctx, settings, originalDifficulty, targetDifficulty, text, summary, feedback)
+	if err != nil || finishReason != adapters.LLMFinishReasonLength {
+		return
+	}
+
+	parts := splitArticleChunks(text, (adapters.EstimateTokens(text)+1)/2)
+	if splits >= maxChunkSplits || len(parts) < 2 {
+		log.Err(ErrArticleTextTruncated).Int("tokens", adapters.EstimateTokens(text)).Msg("Failed to generate OpenAI article text")
+		return "", "", ErrArticleTextTruncated
+	}
+
+	log.Warn().Fields(map[string]any{
+		"tokens": adapters.EstimateTokens(text),
+		"parts":  len(parts),
+		"splits": splits + 1,
+	}).Msg("Retrying truncated article text chunk in smaller parts")
+
+	adapted := make([]string, 0, len(parts))
+	for _, part := range parts {
+		var partText string
+		partText, promptVersion, err = adaptArticleChunk(ctx, settings, originalDifficulty, targetDifficulty, part, summary, feedback, splits+1)
+		if err != nil {
+			return "", "", err
+		}
+
+		adapted = append(adapted, partText)
+	}
+
+	return strings.Join(adapted, "\n\n"), promptVersion, nil
+}
+
+func completeArticleText(ctx context.Context, settings adapters.LLMTaskSettings, originalDifficulty, targetDifficulty, text, summary, feedback string) (generatedText, promptVersion string, finishReason adapters.LLMFinishReason, err error) {
+	rendered, err := prompt.Render(ctx, prompt.ARTICLE_TEXT, map[string]any{
+		"OriginalDifficulty": originalDifficulty,
+		"TargetDifficulty":   targetDifficulty,
+		"Text":               text,
+		"Context":            summary,
+		"Feedback":           feedback,
+	})
+	if err != nil {
+		return
+	}
+
+	res, err := usage.Complete(ctx, llmAdapter, adapters.LLMTaskArticleText, settings.Request(
+		adapters.LLMMessage{Role: adapters.LLMRoleSystem, Content: rendered.System},
+		adapters.LLMMessage{Role: adapters.LLMRoleUser, Content: rendered.User},
+	))
+	if err != nil {
+		log.Err(err).Msg("Failed to generate OpenAI article text")
+		return
+	}
+
+	log.Info().Fields(map[string]any{
+		"provider":      llmAdapter.Provider(),
+		"prompt":        rendered.Ref,
+		"id":            res.Id,
+		"model":         res.Model,
+		"usage":         res.Usage,
+		"finish_reason": res.FinishReason,
+	}).Msg("LLM - Generate Article Text Request")
+
+	return res.Content, rendered.Ref, res.FinishReason, nil
+}
+
+// summarizeArticleChunks returns the summary each chunk is adapted with, which
+// covers every chunk before it. It's built from the original chunks so the
+// chunks themselves can be adapted in parallel.
+func summarizeArticleChunks(ctx context.Context, chunks []string) (summaries []string, err error) {
+	settings := adapters.LLMTaskSettingsFor(adapters.LLMTaskArticleSummary)
+	summaries = make([]string, len(chunks))
+
+	for i := 1; i < len(chunks); i++ {
+		rendered, err := prompt.Render(ctx, prompt.ARTICLE_SUMMARY, map[string]any{
+			"Summary": summaries[i-1],
+			"Text":    chunks[i-1],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := usage.Complete(ctx, llmAdapter, adapters.LLMTaskArticleSummary, settings.Request(
+			adapters.LLMMessage{Role: adapters.LLMRoleSystem, Content: rendered.System},
+			adapters.LLMMessage{Role: adapters.LLMRoleUser, Content: rendered.User},
+		))
+		if err != nil {
+			log.Err(err).Msg("Failed to summarize OpenAI article text")
+			return nil, err
+		}
+
+		log.Info().Fields(map[string]any{
+			"provider":      llmAdapter.Provider(),
+			"prompt":        rendered.Ref,
+			"id":            res.Id,
+			"model":         res.Model,
+			"usage":         res.Usage,
+			"finish_reason": res.FinishReason,
+		}).Msg("LLM - Summarize Article Text Request")
+
+		summaries[i] = strings.TrimSpace(res.Content)
+	}
+
+	return summaries, nil
+}
+
+// generateArticleDocument adapts the document one block at a time so headings,
+// quotes and images stay where the editor put them.
+func generateArticleDocument(ctx context.Context, settings adapters.LLMTaskSettings, originalDifficulty, targetDifficulty string, document ArticleDocument, feedback string) (generatedDocument ArticleDocument, promptVersion string, err error) {
+	generatedDocument = ArticleDocument{Blocks: make([]ArticleBlock, 0, len(document.Blocks))}
+
+	for _, block := range document.Blocks {
+		switch block.Type {
+		case HEADING:
+			block.Text, err = generateArticleHeading(ctx, settings, originalDifficulty, targetDifficulty, block.Text)
+			if err != nil {
+				return
+			}
+		case PARAGRAPH, QUOTE:
+			block.Text, promptVersion, err = generateArticleText(ctx, settings, originalDifficulty, targetDifficulty, block.Text, feedback)
+			if err != nil {
 				return
 			}
-		default:
-			log.Err(err).Msg("Failed to generate OpenAI article text")
-			return
 		}
+
+		generatedDocument.Blocks = append(generatedDocument.Blocks, block)
+	}
+
+	generatedDocument.Sanitize()
+	if err = validateArticleDocument(generatedDocument); err != nil {
+		log.Err(err).Msg("Failed to generate OpenAI article document")
+		return generatedDocument, promptVersion, fmt.Errorf("%w: %s", ErrGeneratedArticleDocumentInvalid, err)
+	}
+
+	return generatedDocument, promptVersion, nil
+}
+
+// generateArticleHeading adapts a heading with its own short prompt, a heading
+// that still doesn't come back as a single short line keeps the original.
+func generateArticleHeading(ctx context.Context, settings adapters.LLMTaskSettings, originalDifficulty, targetDifficulty, heading string) (string, error) {
+	rendered, err := prompt.Render(ctx, prompt.ARTICLE_HEADING, map[string]any{
+		"OriginalDifficulty": originalDifficulty,
+		"TargetDifficulty":   targetDifficulty,
+		"Text":               heading,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	res, err := usage.Complete(ctx, llmAdapter, adapters.LLMTaskArticleText, settings.Request(
+		adapters.LLMMessage{Role: adapters.LLMRoleSystem, Content: rendered.System},
+		adapters.LLMMessage{Role: adapters.LLMRoleUser, Content: rendered.User},
+	))
+	if err != nil {
+		log.Err(err).Msg("Failed to generate OpenAI article heading")
+		return "", err
 	}
 
 	log.Info().Fields(map[string]any{
-		"id":      res.ID,
-		"model":   res.Model,
-		"usage":   res.Usage,
-		"choices": res.Choices,
-	}).Msg("OpenAI - Generate Article Text Request")
+		"provider":      llmAdapter.Provider(),
+		"prompt":        rendered.Ref,
+		"id":            res.Id,
+		"model":         res.Model,
+		"usage":         res.Usage,
+		"finish_reason": res.FinishReason,
+	}).Msg("LLM - Generate Article Heading Request")
+
+	generated := strings.Trim(strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(res.Content), "#")), "\"'“”")
+	if generated == "" || strings.Contains(generated, "\n") || len([]rune(generated)) > maxHeadingLength {
+		log.Warn().Int("length", len([]rune(generated))).Msg("Generated article heading isn't a single short line, keeping the original")
+		return heading, nil
+	}
 
-	return res.Choices[0].Message.Content, nil
+	return generated, nil
 }
@@ -0,0 +1,109 @@
+package article
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+)
+
+func issueArticlePreviewTokenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// The body is optional, an empty one issues a token with the default lifetime
+	var body issueArticlePreviewTokenReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	articleId := chi.URLParam(r, "articleId")
+	token, errs, err := issueArticlePreviewToken(ctx, articleId, body)
+	if errs != nil {
+		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidArticleId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrArticleDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrArticleAlreadyPublished):
+			app.WriteHttpError(w, http.StatusConflict, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusCreated, token)
+}
+
+func getArticlePreviewTokensHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	articleId := chi.URLParam(r, "articleId")
+	tokens, err := getArticlePreviewTokens(ctx, articleId)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidArticleId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrArticleDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, tokens)
+}
+
+func revokeArticlePreviewTokenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+	articleId := chi.URLParam(r, "articleId")
+	token, err := revokeArticlePreviewToken(ctx, id, articleId)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidArticleId), errors.As(err, &ErrInvalidArticlePreviewTokenId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrArticlePreviewTokenDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, token)
+}
+
+func getArticlePreviewHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token := chi.URLParam(r, "token")
+	article, err := getArticlePreview(ctx, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidArticlePreviewToken):
+			app.WriteHttpError(w, http.StatusUnauthorized, err)
+		case errors.Is(err, ErrArticleDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, article)
+}
@@ -0,0 +1,71 @@
+package article
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrArticlePreviewTokenDoesNotExist = errors.New("Preview token does not exist")
+
+func findArticlePreviewTokensByArticleId(ctx context.Context, tx pgx.Tx, articleId ulid.ULID) (tokens []*ArticlePreviewToken, err error) {
+	q := "SELECT * FROM article_preview_tokens WHERE article_id = $1 ORDER BY created_at DESC"
+
+	tokens = []*ArticlePreviewToken{}
+	if err = pgxscan.Select(ctx, tx, &tokens, q, articleId); err != nil {
+		log.Err(err).Msg("Failed to find article preview tokens by article id")
+		return
+	}
+
+	return tokens, nil
+}
+
+func findArticlePreviewTokenByIdAndArticleId(ctx context.Context, tx pgx.Tx, id, articleId ulid.ULID) (token ArticlePreviewToken, err error) {
+	q := "SELECT * FROM article_preview_tokens WHERE id = $1 AND article_id = $2"
+
+	if err = pgxscan.Get(ctx, tx, &token, q, id, articleId); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return token, ErrArticlePreviewTokenDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find article preview token by id and article id")
+		return token, err
+	}
+
+	return token, nil
+}
+
+func saveArticlePreviewToken(ctx context.Context, tx pgx.Tx, token ArticlePreviewToken) (ArticlePreviewToken, error) {
+	q := `
+	INSERT INTO article_preview_tokens(id, article_id, expires_at, created_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING *
+	`
+
+	var savedToken ArticlePreviewToken
+	if err := pgxscan.Get(ctx, tx, &savedToken, q, token.Id, token.ArticleId, token.ExpiresAt, token.CreatedAt); err != nil {
+		log.Err(err).Msg("Failed to save article preview token")
+		return ArticlePreviewToken{}, err
+	}
+
+	return savedToken, nil
+}
+
+func updateArticlePreviewTokenById(ctx context.Context, tx pgx.Tx, token ArticlePreviewToken) (updatedToken ArticlePreviewToken, err error) {
+	q := "UPDATE article_preview_tokens SET revoked_at = $2 WHERE id = $1 RETURNING *"
+
+	if err = pgxscan.Get(ctx, tx, &updatedToken, q, token.Id, token.RevokedAt); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return updatedToken, ErrArticlePreviewTokenDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to update article preview token by id")
+		return updatedToken, err
+	}
+
+	return updatedToken, nil
+}
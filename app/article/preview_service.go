@@ -0,0 +1,240 @@
+package article
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const articlePreviewScope = "ARTICLE_PREVIEW"
+
+var (
+	ErrArticleAlreadyPublished    = errors.New("Article is already published, share its public link instead")
+	ErrInvalidArticlePreviewToken = errors.New("Preview link is invalid, revoked or has expired")
+)
+
+type articlePreviewClaims struct {
+	jwt.RegisteredClaims
+	Scope string `json:"scope"`
+}
+
+func issueArticlePreviewToken(ctx context.Context, articleIdStr string, body issueArticlePreviewTokenReq) (issued IssuedArticlePreviewToken, errs map[string]error, err error) {
+	articleId, err := validateArticleId(articleIdStr)
+	if err != nil {
+		return
+	}
+
+	previewToken, errs := NewArticlePreviewToken(articleId, body.TtlHours)
+	if errs != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to issue article preview token")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	article, err := findArticleById(ctx, tx, articleId)
+	if err != nil {
+		return
+	}
+	if article.IsPublished {
+		return issued, nil, ErrArticleAlreadyPublished
+	}
+
+	previewToken, err = saveArticlePreviewToken(ctx, tx, previewToken)
+	if err != nil {
+		return
+	}
+
+	token, err := signArticlePreviewToken(previewToken)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to issue article preview token")
+		return
+	}
+
+	return IssuedArticlePreviewToken{ArticlePreviewToken: previewToken, Token: token}, nil, nil
+}
+
+func getArticlePreviewTokens(ctx context.Context, articleIdStr string) (tokens []*ArticlePreviewToken, err error) {
+	articleId, err := validateArticleId(articleIdStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get article preview tokens")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if _, err = findArticleById(ctx, tx, articleId); err != nil {
+		return
+	}
+
+	tokens, err = findArticlePreviewTokensByArticleId(ctx, tx, articleId)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get article preview tokens")
+		return
+	}
+
+	return tokens, nil
+}
+
+func revokeArticlePreviewToken(ctx context.Context, idStr, articleIdStr string) (token ArticlePreviewToken, err error) {
+	articleId, err := validateArticleId(articleIdStr)
+	if err != nil {
+		return
+	}
+
+	id, err := validateArticlePreviewTokenId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to revoke article preview token")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	token, err = findArticlePreviewTokenByIdAndArticleId(ctx, tx, id, articleId)
+	if err != nil {
+		return
+	}
+
+	// Revoking twice keeps the original revocation time
+	if !token.RevokedAt.Valid {
+		token.Revoke()
+		token, err = updateArticlePreviewTokenById(ctx, tx, token)
+		if err != nil {
+			return
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to revoke article preview token")
+		return
+	}
+
+	return token, nil
+}
+
+// getArticlePreview resolves a preview link into the article it was issued
+// for, published or not. The signature and expiry are checked first, then the
+// stored token so revoked links stop working before they expire.
+func getArticlePreview(ctx context.Context, tokenStr string) (articleDetail ArticleDetail, err error) {
+	id, articleId, err := parseArticlePreviewToken(tokenStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get article preview")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	previewToken, err := findArticlePreviewTokenByIdAndArticleId(ctx, tx, id, articleId)
+	if err != nil {
+		if err == ErrArticlePreviewTokenDoesNotExist {
+			err = ErrInvalidArticlePreviewToken
+		}
+		return
+	}
+	if !previewToken.IsActive() {
+		return articleDetail, ErrInvalidArticlePreviewToken
+	}
+
+	article, err := findArticleById(ctx, tx, articleId)
+	if err != nil {
+		return
+	}
+
+	articleDetail, err = findArticleDetail(ctx, tx, article, false)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get article preview")
+		return
+	}
+
+	return articleDetail, nil
+}
+
+func signArticlePreviewToken(previewToken ArticlePreviewToken) (token string, err error) {
+	tokenObj := jwt.NewWithClaims(jwt.SigningMethodHS256, articlePreviewClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    previewTokenIssuer,
+			Audience:  []string{previewTokenIssuer},
+			IssuedAt:  jwt.NewNumericDate(previewToken.CreatedAt),
+			ExpiresAt: jwt.NewNumericDate(previewToken.ExpiresAt),
+			ID:        previewToken.Id.String(),
+			Subject:   previewToken.ArticleId.String(),
+		},
+		Scope: articlePreviewScope,
+	})
+
+	token, err = tokenObj.SignedString(previewTokenSecret)
+	if err != nil {
+		log.Err(err).Msg("Failed to sign article preview token")
+		return
+	}
+
+	return token, nil
+}
+
+func parseArticlePreviewToken(tokenStr string) (id, articleId ulid.ULID, err error) {
+	token, err := jwt.ParseWithClaims(
+		tokenStr,
+		&articlePreviewClaims{},
+		func(t *jwt.Token) (interface{}, error) {
+			return previewTokenSecret, nil
+		},
+		jwt.WithIssuer(previewTokenIssuer),
+		jwt.WithAudience(previewTokenIssuer),
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return id, articleId, ErrInvalidArticlePreviewToken
+	}
+
+	claims, isValidClaims := token.Claims.(*articlePreviewClaims)
+	if !isValidClaims || claims.Scope != articlePreviewScope || claims.ExpiresAt == nil {
+		return id, articleId, ErrInvalidArticlePreviewToken
+	}
+
+	id, err = ulid.Parse(claims.ID)
+	if err != nil {
+		return id, articleId, ErrInvalidArticlePreviewToken
+	}
+	articleId, err = ulid.Parse(claims.Subject)
+	if err != nil {
+		return id, articleId, ErrInvalidArticlePreviewToken
+	}
+
+	return id, articleId, nil
+}
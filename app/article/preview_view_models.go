@@ -0,0 +1,6 @@
+package article
+
+type IssuedArticlePreviewToken struct {
+	ArticlePreviewToken
+	Token string `json:"token"`
+}
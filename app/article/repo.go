@@ -3,10 +3,7 @@ package article
 import (
 	"context"
 	"errors"
-	"fmt"
-	"strings"
 
-	sq "github.com/Masterminds/squirrel"
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -21,6 +18,11 @@ var (
 	ErrArticleDoesNotExist         = errors.New("Article does not exist")
 	ErrArticleTextDoesNotExist     = errors.New("Article text does not exist")
 	ErrArticleTextDifficultyExist  = errors.New("Article text with that difficulty exists")
+	ErrArticleCategoryInUse        = errors.New("Article category still has articles, move them to another category first")
+
+	ErrArticleCategoryVersionConflict = errors.New("Article category was changed by someone else since it was loaded")
+	ErrArticleVersionConflict         = errors.New("Article was changed by someone else since it was loaded")
+	ErrArticleTextVersionConflict     = errors.New("Article text was changed by someone else since it was loaded")
 )
 
 func findArticleCategories(ctx context.Context, tx pgx.Tx, search string, limit uint) (categories []*ArticleCategory, err error) {
@@ -81,10 +83,48 @@ func deleteArticleCategoryById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (er
 	return nil
 }
 
-func updateArticleCategoryById(ctx context.Context, tx pgx.Tx, id ulid.ULID, name string) (category ArticleCategory, err error) {
-	q := "UPDATE article_categories SET name = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING *"
+// countArticlesByCategoryId also counts trashed articles, they would be
+// restored into the category otherwise.
+func countArticlesByCategoryId(ctx context.Context, tx pgx.Tx, categoryId ulid.ULID) (count uint, err error) {
+	q := "SELECT COUNT(*) FROM articles WHERE category_id = $1"
+
+	if err = tx.QueryRow(ctx, q, categoryId).Scan(&count); err != nil {
+		log.Err(err).Msg("Failed to count articles by category id")
+		return
+	}
 
-	err = pgxscan.Get(ctx, tx, &category, q, id, name)
+	return count, nil
+}
+
+// moveArticlesToCategory moves the given articles, or every article when none
+// are given, from one category into another. Trashed articles move as well.
+func moveArticlesToCategory(ctx context.Context, tx pgx.Tx, fromId, toId ulid.ULID, articleIds []ulid.ULID) (moved int64, err error) {
+	q := `
+	UPDATE articles
+	SET category_id = $2, updated_at = NOW(), version = version + 1
+	WHERE category_id = $1 AND (CARDINALITY($3::BYTEA[]) = 0 OR id = ANY($3))
+	`
+
+	tag, err := tx.Exec(ctx, q, fromId, toId, ulidBytes(articleIds))
+	if err != nil {
+		log.Err(err).Msg("Failed to move articles to category")
+		return
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// updateArticleCategoryById only applies when the stored version still equals
+// version, a concurrent edit in between surfaces as a version conflict.
+func updateArticleCategoryById(ctx context.Context, tx pgx.Tx, id ulid.ULID, name string, version uint) (category ArticleCategory, err error) {
+	q := `
+	UPDATE article_categories
+	SET name = $2, updated_at = NOW(), version = version + 1
+	WHERE id = $1 AND version = $3 AND deleted_at IS NULL
+	RETURNING *
+	`
+
+	err = pgxscan.Get(ctx, tx, &category, q, id, name, version)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -94,7 +134,7 @@ func updateArticleCategoryById(ctx context.Context, tx pgx.Tx, id ulid.ULID, nam
 		}
 
 		if err.Error() == "scanning one: no rows in result set" {
-			return category, ErrArticleCategoryDoesNotExist
+			return category, ErrArticleCategoryVersionConflict
 		}
 
 		log.Err(err).Msg("Failed to update article category")
@@ -106,95 +146,53 @@ func updateArticleCategoryById(ctx context.Context, tx pgx.Tx, id ulid.ULID, nam
 
 func findArticles(
 	ctx context.Context, tx pgx.Tx,
-	query string, categoryId ulid.ULID, pageSize uint, direction ArticlePaginationDirection,
-	cursor ulid.ULID, includeUnpublished bool,
+	query articleQuery, pageSize uint, direction ArticlePaginationDirection,
+	cursorId ulid.ULID, cursorValue any,
 ) (articles Articles, err error) {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-
-	var categoryFilter string
-	if categoryId != (ulid.ULID{}) {
-		categoryFilter = "category_id = $2"
-	} else {
-		categoryFilter = "category_id != $2"
-	}
-	rowNumber := `WITH rows AS (
-    SELECT ROW_NUMBER() OVER (ORDER BY id DESC) row, id 
-    FROM articles 
-    WHERE deleted_at IS NULL AND title ILIKE '%' || $1 || '%' AND ` + categoryFilter + `
-    ORDER BY id DESC
-    )`
-	sBuilder := psql.
-		Select(
-			"rows.row",
-			"a.*",
-			"(CASE WHEN ac.deleted_at IS NULL THEN ac.name ELSE 'Deleted Category' END) category_name",
-			"(CASE WHEN LENGTH(at.content) >= 255 THEN SUBSTRING(at.content, 1, 255) || '...' ELSE at.content END) teaser",
-		).
-		From("articles a").
-		InnerJoin("rows ON rows.id = a.id").
-		InnerJoin("article_categories ac ON a.category_id = ac.id").
-		InnerJoin("article_texts at ON a.id = at.article_id")
-
-	if !includeUnpublished {
-		sBuilder = sBuilder.Where("a.is_published IS TRUE")
-	}
-
-	sBuilder = sBuilder.
-		Where("title ILIKE '%' || ? || '%'").
-		Where("a.deleted_at IS NULL").
-		Where("at.difficulty = 'ADVANCED'").
-		Where("at.deleted_at IS NULL")
-
-	if categoryId != (ulid.ULID{}) {
-		sBuilder = sBuilder.Where(sq.Eq{"category_id": categoryId})
-	} else {
-		sBuilder = sBuilder.Where(sq.NotEq{"category_id": categoryId})
-	}
-
-	switch direction {
-	case NEXT:
-		if cursor != (ulid.ULID{}) {
-			sBuilder = sBuilder.Where(sq.LtOrEq{"a.id": cursor})
-		} else {
-			sBuilder = sBuilder.Where(sq.NotEq{"a.id": cursor})
-		}
-		sBuilder = sBuilder.OrderBy("a.id DESC").Limit(uint64(pageSize) + 1)
-	case PREVIOUS:
-		if cursor != (ulid.ULID{}) {
-			sBuilder = sBuilder.Where(sq.GtOrEq{"a.id": cursor})
-		} else {
-			sBuilder = sBuilder.Where(sq.NotEq{"a.id": cursor})
-		}
-		sBuilder = sBuilder.OrderBy("a.id ASC").Limit(uint64(pageSize) + 1)
-	}
-
-	q, _, err := sBuilder.ToSql()
+	q, args, err := query.page(direction, cursorId, cursorValue, pageSize+1).ToSql()
 	if err != nil {
 		log.Err(err).Msg("Failed to find articles")
 		return
 	}
 
-	articleQuery := rowNumber + " " + q
 	listOfArticles := []*ArticleWithRowNumber{}
-	if err = pgxscan.Select(ctx, tx, &listOfArticles, articleQuery, query, categoryId, cursor); err != nil {
+	if err = pgxscan.Select(ctx, tx, &listOfArticles, q, args...); err != nil {
 		log.Err(err).Msg("Failed to get articles")
 		return articles, err
 	}
 
-	totalQuery := strings.ReplaceAll(q, "rows.row, a.*, (CASE WHEN ac.deleted_at IS NULL THEN ac.name ELSE 'Deleted Category' END) category_name, (CASE WHEN LENGTH(at.content) >= 255 THEN SUBSTRING(at.content, 1, 255) || '...' ELSE at.content END) teaser", "COUNT(*) total")
-	totalQuery = strings.ReplaceAll(totalQuery, "INNER JOIN rows ON rows.id = a.id", "")
-	totalQuery = strings.ReplaceAll(totalQuery, fmt.Sprintf("AND a.id >= $3 ORDER BY a.id ASC LIMIT %d", pageSize+1), "")
-	totalQuery = strings.ReplaceAll(totalQuery, fmt.Sprintf("AND a.id <= $3 ORDER BY a.id DESC LIMIT %d", pageSize+1), "")
-	totalQuery = strings.ReplaceAll(totalQuery, fmt.Sprintf("AND a.id <> $3 ORDER BY a.id ASC LIMIT %d", pageSize+1), "")
-	totalQuery = strings.ReplaceAll(totalQuery, fmt.Sprintf("AND a.id <> $3 ORDER BY a.id DESC LIMIT %d", pageSize+1), "")
+	totalQuery, totalArgs, err := query.count().ToSql()
+	if err != nil {
+		log.Err(err).Msg("Failed to get articles")
+		return
+	}
+
 	var totalResult struct {
 		Total uint
 	}
-	if err = pgxscan.Get(ctx, tx, &totalResult, totalQuery, query, categoryId); err != nil {
+	if err = pgxscan.Get(ctx, tx, &totalResult, totalQuery, totalArgs...); err != nil {
 		log.Err(err).Msg("Failed to get articles")
 		return articles, err
 	}
 
+	// The extra article tells whether there's another page in the same direction
+	var newCursor null.String
+	if len(listOfArticles) > int(pageSize) {
+		boundary := listOfArticles[pageSize]
+		if direction == PREVIOUS {
+			boundary = listOfArticles[pageSize-1]
+		}
+
+		cursorStr, err := newArticleCursor(query.sort, boundary)
+		if err != nil {
+			log.Err(err).Msg("Failed to get articles")
+			return articles, err
+		}
+
+		newCursor = null.StringFrom(cursorStr)
+		listOfArticles = listOfArticles[:pageSize]
+	}
+
 	if direction == PREVIOUS {
 		for i := 0; i < len(listOfArticles)/2; i++ {
 			j := len(listOfArticles) - i - 1
@@ -202,34 +200,34 @@ func findArticles(
 		}
 	}
 
-	var newCursor null.String
 	firstRow := uint(0)
 	lastRow := uint(0)
-	if len(listOfArticles) > int(pageSize) {
-		if direction == PREVIOUS {
-			newCursor = null.StringFrom(listOfArticles[0].Id.String())
-		} else {
+	if len(listOfArticles) > 0 {
+		// Rows are counted up to the first article instead of numbering the whole table
+		firstRow = 1
+		if direction == PREVIOUS || cursorId != (ulid.ULID{}) {
+			first := listOfArticles[0]
+			offsetQuery, offsetArgs, err := query.countBefore(first.Id, first.SortValue).ToSql()
+			if err != nil {
+				log.Err(err).Msg("Failed to get articles")
+				return articles, err
+			}
 
-			newCursor = null.StringFrom(listOfArticles[len(listOfArticles)-1].Id.String())
-		}
-		firstRow = listOfArticles[0].Row
-		lastRow = listOfArticles[len(listOfArticles)-2].Row
-		listOfArticles = listOfArticles[:len(listOfArticles)-1]
-	} else if len(listOfArticles) > 0 {
-		if direction == PREVIOUS {
-			newCursor = null.StringFrom(listOfArticles[0].Id.String())
-		} else {
+			var offsetResult struct {
+				Total uint
+			}
+			if err = pgxscan.Get(ctx, tx, &offsetResult, offsetQuery, offsetArgs...); err != nil {
+				log.Err(err).Msg("Failed to get articles")
+				return articles, err
+			}
 
-			newCursor = null.StringFrom(listOfArticles[len(listOfArticles)-1].Id.String())
+			firstRow += offsetResult.Total
 		}
-		firstRow = listOfArticles[0].Row
-		lastRow = listOfArticles[len(listOfArticles)-1].Row
-	}
 
-	if newCursor.String == cursor.String() {
-		firstRow, lastRow = 0, 0
-		newCursor = null.NewString("", false)
-		listOfArticles = make([]*ArticleWithRowNumber, 0)
+		for i, article := range listOfArticles {
+			article.Row = firstRow + uint(i)
+		}
+		lastRow = firstRow + uint(len(listOfArticles)) - 1
 	}
 
 	articles = Articles{
@@ -251,8 +249,8 @@ func saveArticle(ctx context.Context, tx pgx.Tx, article Article) (Article, erro
 	}
 
 	q := `
-  INSERT INTO articles(id, category_id, title, thumbnail_url, original_url, source, author, is_published, created_at) VALUES
-  ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+  INSERT INTO articles(id, category_id, title, thumbnail_url, original_url, source, author, is_published, created_at, published_at, source_id, author_id) VALUES
+  ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
   RETURNING *
   `
 
@@ -271,6 +269,9 @@ func saveArticle(ctx context.Context, tx pgx.Tx, article Article) (Article, erro
 		article.Author,
 		article.IsPublished,
 		article.CreatedAt,
+		article.PublishedAt,
+		article.SourceId,
+		article.AuthorId,
 	); err != nil {
 		log.Err(err).Msg("Failed to save article")
 		return newArticle, err
@@ -284,10 +285,11 @@ func saveArticleText(ctx context.Context, tx pgx.Tx, text ArticleText) (ArticleT
 		return text, err
 	}
 
-	q := `INSERT INTO article_texts(id, article_id, content, difficulty, is_adapted, created_at) VALUES
-  ($1, $2, $3, $4, $5, $6)
+	q := `INSERT INTO article_texts(id, article_id, content, difficulty, is_adapted, created_at, document, word_count, reading_time_minutes, prompt_version, quality, moderation_status) VALUES
+  ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
   ON CONFLICT(id)
-  DO UPDATE SET content = $3, difficulty = $4, is_adapted = $5, updated_at = NOW()
+  DO UPDATE SET content = $3, difficulty = $4, is_adapted = $5, document = $7, word_count = $8, reading_time_minutes = $9, prompt_version = $10, quality = $11,
+  moderation_status = $12, updated_at = NOW()
   RETURNING *
   `
 
@@ -303,6 +305,12 @@ func saveArticleText(ctx context.Context, tx pgx.Tx, text ArticleText) (ArticleT
 		text.Difficulty,
 		text.IsAdapted,
 		text.CreatedAt,
+		text.Document,
+		text.WordCount,
+		text.ReadingTimeMinutes,
+		text.PromptVersion,
+		text.Quality,
+		text.ModerationStatus,
 	); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -333,14 +341,16 @@ func findArticleById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (article Arti
 	return article, nil
 }
 
-func findArticleTextsByArticleId(ctx context.Context, tx pgx.Tx, articleId ulid.ULID) (texts []*ArticleText, err error) {
+// findArticleTextsByArticleId leaves out texts held for review unless
+// includePending, which is only for admins.
+func findArticleTextsByArticleId(ctx context.Context, tx pgx.Tx, articleId ulid.ULID, includePending bool) (texts []*ArticleText, err error) {
 	if _, err := findArticleById(ctx, tx, articleId); err != nil {
 		return texts, err
 	}
 
-	q := "SELECT * FROM article_texts WHERE article_id = $1 AND deleted_at IS NULL"
+	q := "SELECT * FROM article_texts WHERE article_id = $1 AND (moderation_status = $2 OR $3) AND deleted_at IS NULL"
 
-	if err = pgxscan.Select(ctx, tx, &texts, q, articleId); err != nil {
+	if err = pgxscan.Select(ctx, tx, &texts, q, articleId, TEXT_APPROVED, includePending); err != nil {
 		log.Err(err).Msg("Failed to find article texts")
 		return
 	}
@@ -348,6 +358,43 @@ func findArticleTextsByArticleId(ctx context.Context, tx pgx.Tx, articleId ulid.
 	return texts, nil
 }
 
+// findArticleDetail assembles the category name and texts of an already loaded
+// article. Categories can't be deleted while they hold articles, so an article
+// whose category is missing anyway is reported as missing itself.
+func findArticleDetail(ctx context.Context, tx pgx.Tx, article Article, includePending bool) (articleDetail ArticleDetail, err error) {
+	category, err := findArticleCategoryById(ctx, tx, article.CategoryId)
+	if err != nil {
+		if err == ErrArticleCategoryDoesNotExist {
+			log.Warn().Str("article_id", article.Id.String()).Msg("Article category is missing")
+			return articleDetail, ErrArticleDoesNotExist
+		}
+		return
+	}
+
+	texts, err := findArticleTextsByArticleId(ctx, tx, article.Id, includePending)
+	if err != nil {
+		return
+	}
+
+	textMap := make(map[string]ArticleText)
+	for _, text := range texts {
+		textMap[text.Difficulty] = *text
+	}
+
+	series, err := findArticleSeriesNavigation(ctx, tx, article.Id)
+	if err != nil {
+		return
+	}
+
+	return ArticleDetail{
+		Article:           article,
+		CategoryName:      category.Name,
+		Texts:             textMap,
+		Series:            series,
+		categoryUpdatedAt: category.LastModified(),
+	}, nil
+}
+
 func updateArticleById(ctx context.Context, tx pgx.Tx, article Article) (updatedArticle Article, err error) {
 	if _, err := findArticleCategoryById(ctx, tx, article.CategoryId); err != nil {
 		return article, err
@@ -355,8 +402,9 @@ func updateArticleById(ctx context.Context, tx pgx.Tx, article Article) (updated
 
 	q := `UPDATE articles
   SET category_id = $1, title = $2, thumbnail_url = $3, original_url = $4, 
-  source = $5, author = $6, is_published = $7, updated_at = $8
-  WHERE id = $9 AND deleted_at IS NULL
+  source = $5, author = $6, is_published = $7, updated_at = $8, published_at = $10,
+  source_id = $11, author_id = $12, version = version + 1
+  WHERE id = $9 AND version = $13 AND deleted_at IS NULL
   RETURNING *
   `
 
@@ -374,10 +422,14 @@ func updateArticleById(ctx context.Context, tx pgx.Tx, article Article) (updated
 		article.IsPublished,
 		article.UpdatedAt,
 		article.Id,
+		article.PublishedAt,
+		article.SourceId,
+		article.AuthorId,
+		article.Version,
 	)
 	if err != nil {
 		if err.Error() == "scanning one: no rows in result set" {
-			return updatedArticle, ErrArticleDoesNotExist
+			return updatedArticle, ErrArticleVersionConflict
 		}
 
 		log.Err(err).Msg("Failed to update article category")
@@ -430,6 +482,21 @@ func findArticleTextByArticleIdAndDifficulty(ctx context.Context, tx pgx.Tx, art
 	return text, nil
 }
 
+func findArticleTextById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (text ArticleText, err error) {
+	q := "SELECT * FROM article_texts WHERE id = $1 AND deleted_at IS NULL"
+
+	if err = pgxscan.Get(ctx, tx, &text, q, id); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return text, ErrArticleTextDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find article text")
+		return
+	}
+
+	return text, nil
+}
+
 func findArticleTextByIdAndArticleId(ctx context.Context, tx pgx.Tx, id ulid.ULID, articleId ulid.ULID) (text ArticleText, err error) {
 	if _, err := findArticleById(ctx, tx, articleId); err != nil {
 		return text, err
@@ -449,6 +516,44 @@ func findArticleTextByIdAndArticleId(ctx context.Context, tx pgx.Tx, id ulid.ULI
 	return text, nil
 }
 
+// findGeneratedArticleTextTarget finds the text being regenerated, or none for
+// a new text, and makes sure no other text of the article has difficulty.
+func findGeneratedArticleTextTarget(ctx context.Context, tx pgx.Tx, articleId ulid.ULID, textId *ulid.ULID, difficulty string) (text ArticleText, err error) {
+	if textId != nil {
+		if text, err = findArticleTextByIdAndArticleId(ctx, tx, *textId, articleId); err != nil {
+			return
+		}
+	} else if _, err = findArticleById(ctx, tx, articleId); err != nil {
+		return
+	}
+
+	existingText, err := findArticleTextByArticleIdAndDifficulty(ctx, tx, articleId, difficulty)
+	if err != nil {
+		if err == ErrArticleTextDoesNotExist {
+			return text, nil
+		}
+		return
+	}
+
+	if textId == nil || existingText.Id != *textId {
+		return text, ErrArticleTextDifficultyExist
+	}
+
+	return text, nil
+}
+
+// approveArticleTextById publishes a text that was held for review
+func approveArticleTextById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (err error) {
+	q := "UPDATE article_texts SET moderation_status = $1 WHERE id = $2 AND deleted_at IS NULL"
+
+	if _, err = tx.Exec(ctx, q, TEXT_APPROVED, id); err != nil {
+		log.Err(err).Msg("Failed to approve article text")
+		return err
+	}
+
+	return nil
+}
+
 func updateArticleTextById(ctx context.Context, tx pgx.Tx, text ArticleText) (updatedText ArticleText, err error) {
 	if _, err = findArticleById(ctx, tx, text.ArticleId); err != nil {
 		return text, err
@@ -456,8 +561,9 @@ func updateArticleTextById(ctx context.Context, tx pgx.Tx, text ArticleText) (up
 
 	q := `
   UPDATE article_texts
-  SET content = $1, difficulty = $2, is_adapted = $3, updated_at = $4
-  WHERE id = $5 AND deleted_at IS NULL
+  SET content = $1, difficulty = $2, is_adapted = $3, updated_at = $4, document = $6, word_count = $7, reading_time_minutes = $8,
+  prompt_version = $10, quality = $11, moderation_status = $12, version = version + 1
+  WHERE id = $5 AND version = $9 AND deleted_at IS NULL
   RETURNING *
   `
 
@@ -471,7 +577,18 @@ func updateArticleTextById(ctx context.Context, tx pgx.Tx, text ArticleText) (up
 		text.IsAdapted,
 		text.UpdatedAt,
 		text.Id,
+		text.Document,
+		text.WordCount,
+		text.ReadingTimeMinutes,
+		text.Version,
+		text.PromptVersion,
+		text.Quality,
+		text.ModerationStatus,
 	); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return text, ErrArticleTextVersionConflict
+		}
+
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			if pgErr.Code == "23505" {
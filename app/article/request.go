@@ -1,6 +1,7 @@
 package article
 
 import (
+	"github.com/lexica-app/lexicapi/adapters"
 	"github.com/oklog/ulid/v2"
 	"gopkg.in/guregu/null.v4"
 )
@@ -14,14 +15,16 @@ type updateArticleCategoryReq struct {
 }
 
 type createArticleReq struct {
-	CategoryId      string      `json:"category_id"`
-	Title           string      `json:"title"`
-	ThumbnailUrl    null.String `json:"thumbnail_url"`
-	OriginalUrl     string      `json:"original_url"`
-	Source          string      `json:"source"`
-	Author          null.String `json:"author"`
-	IsPublished     null.Bool   `json:"is_published"`
-	OriginalContent string      `json:"original_content"`
+	CategoryId       string           `json:"category_id"`
+	Title            string           `json:"title"`
+	ThumbnailUrl     null.String      `json:"thumbnail_url"`
+	OriginalUrl      string           `json:"original_url"`
+	Source           string           `json:"source"`
+	Author           null.String      `json:"author"`
+	IsPublished      null.Bool        `json:"is_published"`
+	OriginalContent  string           `json:"original_content"`
+	OriginalDocument *ArticleDocument `json:"original_document"`
+	OriginalMarkdown null.String      `json:"original_markdown"`
 }
 
 type updateArticleReq struct {
@@ -41,28 +44,75 @@ const (
 	PREVIOUS ArticlePaginationDirection = "previous"
 )
 
+// getArticlesReq holds the raw listing query parameters. Anything that can't
+// be parsed is ignored rather than rejected.
+type getArticlesReq struct {
+	Query              string
+	CategoryIds        []string
+	SourceIds          []string
+	AuthorIds          []string
+	SeriesIds          []string
+	Source             string
+	Author             string
+	PublishedFrom      string
+	PublishedUntil     string
+	Difficulties       []string
+	MinReadingTime     string
+	MaxReadingTime     string
+	Sort               string
+	Direction          string
+	Cursor             string
+	PageSize           uint
+	IncludeUnpublished bool
+}
+
+type ArticleSort string
+
+const (
+	NEWEST     ArticleSort = "newest"
+	OLDEST     ArticleSort = "oldest"
+	TITLE      ArticleSort = "title"
+	POPULARITY ArticleSort = "popularity"
+	// SERIES orders by position and is only honored when filtering by one series
+	SERIES ArticleSort = "series"
+)
+
 type createArticleTextReq struct {
-	Content    string `json:"content"`
-	Difficulty string `json:"difficulty"`
-	IsAdapted  bool   `json:"is_adapted"`
+	Content    string           `json:"content"`
+	Document   *ArticleDocument `json:"document"`
+	Markdown   null.String      `json:"markdown"`
+	Difficulty string           `json:"difficulty"`
+	IsAdapted  bool             `json:"is_adapted"`
 }
 
 type updateArticleTextReq struct {
-	Content    string `json:"content"`
-	Difficulty string `json:"difficulty"`
-	IsAdapted  bool   `json:"is_adapted"`
+	Content    string           `json:"content"`
+	Document   *ArticleDocument `json:"document"`
+	Markdown   null.String      `json:"markdown"`
+	Difficulty string           `json:"difficulty"`
+	IsAdapted  bool             `json:"is_adapted"`
 }
 
 type generateOpenAIArticleTextReq struct {
-	Content    string `json:"content"`
-	Difficulty string `json:"difficulty"`
-	IsAdapted  bool   `json:"is_adapted"`
+	Content    string           `json:"content"`
+	Document   *ArticleDocument `json:"document"`
+	Markdown   null.String      `json:"markdown"`
+	Difficulty string           `json:"difficulty"`
+	IsAdapted  bool             `json:"is_adapted"`
+
+	// Only reachable by superadmins, lets them try other model settings
+	ModelSettings *adapters.LLMTaskSettingsOverride `json:"model_settings"`
 }
 
 type regenerateOpenAIArticleTextReq struct {
-	Content    string `json:"content"`
-	Difficulty string `json:"difficulty"`
-	IsAdapted  bool   `json:"is_adapted"`
+	Content    string           `json:"content"`
+	Document   *ArticleDocument `json:"document"`
+	Markdown   null.String      `json:"markdown"`
+	Difficulty string           `json:"difficulty"`
+	IsAdapted  bool             `json:"is_adapted"`
+
+	// Only reachable by superadmins, lets them try other model settings
+	ModelSettings *adapters.LLMTaskSettingsOverride `json:"model_settings"`
 }
 
 type createCollectionReq struct {
@@ -78,3 +128,58 @@ type updateCollectionReq struct {
 type addArticleToCollectionsReq struct {
 	CollectionIds []ulid.ULID `json:"collection_ids"`
 }
+
+type createSourceReq struct {
+	Name    string      `json:"name"`
+	Domain  null.String `json:"domain"`
+	LogoUrl null.String `json:"logo_url"`
+}
+
+type updateSourceReq struct {
+	Name    null.String `json:"name"`
+	Domain  null.String `json:"domain"`
+	LogoUrl null.String `json:"logo_url"`
+}
+
+type mergeSourcesReq struct {
+	SourceIds []string `json:"source_ids"`
+}
+
+type createAuthorReq struct {
+	Name string `json:"name"`
+}
+
+type updateAuthorReq struct {
+	Name string `json:"name"`
+}
+
+type mergeAuthorsReq struct {
+	AuthorIds []string `json:"author_ids"`
+}
+
+type issueArticlePreviewTokenReq struct {
+	TtlHours null.Int `json:"ttl_hours"`
+}
+
+type moveArticleCategoryArticlesReq struct {
+	ToCategoryId string   `json:"to_category_id"`
+	ArticleIds   []string `json:"article_ids"`
+}
+
+type createSeriesReq struct {
+	Title       string      `json:"title"`
+	Description null.String `json:"description"`
+}
+
+type updateSeriesReq struct {
+	Title       null.String `json:"title"`
+	Description null.String `json:"description"`
+}
+
+type setSeriesArticlesReq struct {
+	ArticleIds []string `json:"article_ids"`
+}
+
+type publishSeriesReq struct {
+	IsPublished bool `json:"is_published"`
+}
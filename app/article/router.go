@@ -1,20 +1,59 @@
 package article
 
 import (
+	"time"
+
 	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
 	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/lexica-app/lexicapi/app/usage"
 )
 
 func AdminRouter() *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(auth.SuperadminAuthMiddleware)
+	r.Use(app.CacheControl(0))
+	r.Use(app.InvalidateResponseCacheMiddleware)
 
 	r.Get("/category", getArticleCategoriesHandler)
 	r.Post("/category", createArticleCategoryHandler)
 	r.Get("/category/{id}", getArticleCategoryByIdHandler)
 	r.Delete("/category/{id}", deleteArticleCategoryHandler)
 	r.Patch("/category/{id}", updateArticleCategoryHandler)
+	r.Post("/category/{id}/move", moveArticleCategoryArticlesHandler)
+
+	r.Get("/source", getSourcesHandler)
+	r.Post("/source", createSourceHandler)
+	r.Get("/source/{id}", getSourceDetailHandler)
+	r.Patch("/source/{id}", updateSourceHandler)
+	r.Delete("/source/{id}", removeSourceHandler)
+	r.Post("/source/{id}/merge", mergeSourcesHandler)
+
+	r.Get("/author", getAuthorsHandler)
+	r.Post("/author", createAuthorHandler)
+	r.Get("/author/{id}", getAuthorDetailHandler)
+	r.Patch("/author/{id}", updateAuthorHandler)
+	r.Delete("/author/{id}", removeAuthorHandler)
+	r.Post("/author/{id}/merge", mergeAuthorsHandler)
+
+	r.Get("/series", getSeriesHandler)
+	r.Post("/series", createSeriesHandler)
+	r.Get("/series/{id}", getSeriesDetailHandler)
+	r.Patch("/series/{id}", updateSeriesHandler)
+	r.Delete("/series/{id}", removeSeriesHandler)
+	r.Put("/series/{id}/articles", setSeriesArticlesHandler)
+	r.Patch("/series/{id}/publish", publishSeriesHandler)
+
+	r.Get("/trash", getTrashHandler)
+	r.Patch("/trash/article/{id}/restore", restoreArticleHandler)
+	r.Delete("/trash/article/{id}", purgeArticleHandler)
+	r.Patch("/trash/text/{id}/restore", restoreArticleTextHandler)
+	r.Delete("/trash/text/{id}", purgeArticleTextHandler)
+	r.Patch("/trash/category/{id}/restore", restoreArticleCategoryHandler)
+	r.Delete("/trash/category/{id}", purgeArticleCategoryHandler)
+	r.Patch("/trash/collection/{id}/restore", restoreCollectionHandler)
+	r.Delete("/trash/collection/{id}", purgeCollectionHandler)
 
 	r.Get("/", getArticlesHandler)
 	r.Post("/", createArticleHandler)
@@ -23,10 +62,15 @@ func AdminRouter() *chi.Mux {
 	r.Delete("/{id}", removeArticleHandler)
 
 	r.Post("/{articleId}/text", createArticleTextHandler)
+	r.Get("/{articleId}/text/{id}", getArticleTextHandler)
 	r.Patch("/{articleId}/text/{id}", updateArticleTextHandler)
 	r.Delete("/{articleId}/text/{id}", removeArticleTextHandler)
-	r.Post("/{articleId}/text/generate", generateOpenAIArticleTextHandler)
-	r.Patch("/{articleId}/text/{id}/regenerate", regenerateOpenAIArticleTextHandler)
+	r.With(usage.BudgetMiddleware).Post("/{articleId}/text/generate", generateOpenAIArticleTextHandler)
+	r.With(usage.BudgetMiddleware).Patch("/{articleId}/text/{id}/regenerate", regenerateOpenAIArticleTextHandler)
+
+	r.Get("/{articleId}/preview", getArticlePreviewTokensHandler)
+	r.Post("/{articleId}/preview", issueArticlePreviewTokenHandler)
+	r.Delete("/{articleId}/preview/{id}", revokeArticlePreviewTokenHandler)
 
 	return r
 }
@@ -34,11 +78,25 @@ func AdminRouter() *chi.Mux {
 func Router() *chi.Mux {
 	r := chi.NewRouter()
 
-	r.Get("/category", getArticleCategoriesHandler)
-	r.Get("/category/{id}", getArticleCategoryByIdHandler)
+	r.Group(func(r chi.Router) {
+		r.Use(app.ResponseCacheMiddleware)
 
-	r.Get("/", getArticlesHandler)
-	r.Get("/{id}", getArticleByIdHandler)
+		r.With(app.CacheControl(10*time.Minute)).Get("/category", getArticleCategoriesHandler)
+		r.With(app.CacheControl(10*time.Minute)).Get("/category/{id}", getArticleCategoryByIdHandler)
+		r.With(app.CacheControl(time.Minute)).Get("/", getArticlesHandler)
+		r.With(app.CacheControl(5*time.Minute)).Get("/{id}", getArticleByIdHandler)
+	})
+
+	r.Get("/source", getSourcesHandler)
+	r.Get("/source/{id}", getSourceDetailHandler)
+	r.Get("/author", getAuthorsHandler)
+	r.Get("/author/{id}", getAuthorDetailHandler)
+	r.Get("/series", getSeriesHandler)
+	r.Get("/series/{id}", getSeriesDetailHandler)
+
+	r.Get("/preview/{token}", getArticlePreviewHandler)
+
+	r.Get("/{articleId}/render", renderArticleTextHandler)
 	
 	r.Group(func(r chi.Router) {
 		r.Use(auth.UserAuthMiddleware)
@@ -46,6 +104,10 @@ func Router() *chi.Mux {
 		r.Get("/{articleId}/collection", getAddedCollectionsHandler)
 		r.Post("/{articleId}/collection", addArticleToCollectionsHandler)
 
+		r.Get("/series/{id}/progress", getSeriesProgressHandler)
+		r.Put("/series/{id}/progress/{articleId}", markSeriesArticleReadHandler)
+		r.Delete("/series/{id}/progress", resetSeriesProgressHandler)
+
 		r.Post("/collection/new", createCollectionHandler)
 		r.Get("/collection/{collectionId}", getCollectionDetailHandler)
 		r.Put("/collection/{collectionId}", updateCollectionHandler)
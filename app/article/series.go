@@ -0,0 +1,102 @@
+package article
+
+import (
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Series groups the parts of long-form content. Its articles are kept in order
+// in series_articles, an article belongs to at most one series.
+type Series struct {
+	Id          ulid.ULID   `json:"id"`
+	Title       string      `json:"title"`
+	Description null.String `json:"description"`
+	IsPublished bool        `json:"is_published"`
+	PublishedAt null.Time   `json:"published_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   null.Time   `json:"updated_at"`
+	DeletedAt   null.Time   `json:"deleted_at"`
+}
+
+func NewSeries(title string, description null.String) (Series, map[string]error) {
+	errs := make(map[string]error)
+
+	title = strings.TrimSpace(title)
+	if err := validateSeriesTitle(title); err != nil {
+		errs["title"] = err
+	}
+	if err := validateSeriesDescription(description.String); err != nil {
+		errs["description"] = err
+	}
+
+	if len(errs) != 0 {
+		return Series{}, errs
+	}
+
+	return Series{
+		Id:          ulid.Make(),
+		Title:       title,
+		Description: normalizeSeriesDescription(description),
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+func (s *Series) Update(title, description null.String) map[string]error {
+	errs := make(map[string]error)
+
+	if title.Valid {
+		if err := validateSeriesTitle(title.String); err != nil {
+			errs["title"] = err
+		}
+		s.Title = strings.TrimSpace(title.String)
+	}
+
+	if description.Valid {
+		if err := validateSeriesDescription(description.String); err != nil {
+			errs["description"] = err
+		}
+		s.Description = normalizeSeriesDescription(description)
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+
+	s.UpdatedAt = null.TimeFrom(time.Now())
+
+	return nil
+}
+
+// Publish toggles whether readers can see the series. Only a series with parts
+// can be published, the date of the first publish is kept like for articles.
+func (s *Series) Publish(isPublished bool, articleCount uint) error {
+	if isPublished && articleCount == 0 {
+		return ErrSeriesEmpty
+	}
+
+	s.IsPublished = isPublished
+	if s.IsPublished && !s.PublishedAt.Valid {
+		s.PublishedAt = null.TimeFrom(time.Now())
+	}
+	s.UpdatedAt = null.TimeFrom(time.Now())
+
+	return nil
+}
+
+func (s *Series) Delete() {
+	if !s.DeletedAt.Valid {
+		s.DeletedAt = null.TimeFrom(time.Now())
+	}
+}
+
+func normalizeSeriesDescription(description null.String) null.String {
+	description.String = strings.TrimSpace(description.String)
+	if description.String == "" {
+		return null.NewString("", false)
+	}
+
+	return description
+}
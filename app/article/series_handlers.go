@@ -0,0 +1,276 @@
+package article
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+)
+
+func getSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := r.URL.Query().Get("q")
+	limitStr := r.URL.Query().Get("limit")
+
+	// Don't throw error to client just because of misinputs
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+
+	series, err := getSeries(ctx, query, uint(limit), strings.HasPrefix(r.URL.Path, "/admin"))
+	if err != nil {
+		switch err {
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, series)
+}
+
+func getSeriesDetailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+	series, err := getSeriesDetail(ctx, id, strings.HasPrefix(r.URL.Path, "/admin"))
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidSeriesId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrSeriesDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, series)
+}
+
+func createSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body createSeriesReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	series, errs, err := createSeries(ctx, body)
+	if errs != nil {
+		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
+		return
+	}
+	if err != nil {
+		switch err {
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusCreated, series)
+}
+
+func updateSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+
+	var body updateSeriesReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	series, errs, err := updateSeries(ctx, id, body)
+	if errs != nil {
+		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidSeriesId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrSeriesDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, series)
+}
+
+func removeSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+	if err := removeSeries(ctx, id); err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidSeriesId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrSeriesDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setSeriesArticlesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+
+	var body setSeriesArticlesReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	series, err := setSeriesArticles(ctx, id, body)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidSeriesId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrSeriesDoesNotExist), errors.Is(err, ErrSeriesArticleDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrArticleInAnotherSeries):
+			app.WriteHttpError(w, http.StatusConflict, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, series)
+}
+
+func publishSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "id")
+
+	var body publishSeriesReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	series, err := publishSeries(ctx, id, body)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidSeriesId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrSeriesDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, series)
+}
+
+func getSeriesProgressHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	progress, err := getSeriesProgress(ctx, id, user.Id)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidSeriesId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrSeriesDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, progress)
+}
+
+func markSeriesArticleReadHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	articleId := chi.URLParam(r, "articleId")
+	progress, err := markSeriesArticleRead(ctx, id, articleId, user.Id)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidSeriesId), errors.As(err, &ErrInvalidArticleId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrSeriesDoesNotExist), errors.Is(err, ErrArticleNotInSeries):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, progress)
+}
+
+func resetSeriesProgressHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if err := resetSeriesProgress(ctx, id, user.Id); err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidSeriesId):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrSeriesDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,372 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrSeriesDoesNotExist        = errors.New("Series does not exist")
+	ErrArticleInAnotherSeries    = errors.New("Article is already part of another series")
+	ErrArticleNotInSeries        = errors.New("Article is not a published part of this series")
+	ErrSeriesArticleDoesNotExist = errors.New("One or more of the series articles do not exist")
+)
+
+func findSeries(ctx context.Context, tx pgx.Tx, search string, limit uint, includeUnpublished bool) (series []*SeriesViewModel, err error) {
+	q := `
+	SELECT
+	  s.*,
+	  (
+	    SELECT COUNT(*) FROM series_articles sa
+	    INNER JOIN articles a ON a.id = sa.article_id
+	    WHERE sa.series_id = s.id AND a.deleted_at IS NULL AND (a.is_published IS TRUE OR $3)
+	  ) article_count
+	FROM series s
+	WHERE s.title ILIKE '%' || $1 || '%' AND s.deleted_at IS NULL AND (s.is_published IS TRUE OR $3)
+	ORDER BY COALESCE(s.published_at, s.created_at) DESC, s.id DESC
+	LIMIT $2
+	`
+
+	series = []*SeriesViewModel{}
+	if err = pgxscan.Select(ctx, tx, &series, q, search, limit, includeUnpublished); err != nil {
+		log.Err(err).Msg("Failed to find series")
+		return
+	}
+
+	return series, nil
+}
+
+func findSeriesById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (series Series, err error) {
+	q := "SELECT * FROM series WHERE id = $1 AND deleted_at IS NULL"
+
+	if err = pgxscan.Get(ctx, tx, &series, q, id); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return series, ErrSeriesDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find series by id")
+		return series, err
+	}
+
+	return series, nil
+}
+
+// findSeriesDetail hides unpublished series and parts unless includeUnpublished
+// is set for admins.
+func findSeriesDetail(ctx context.Context, tx pgx.Tx, id ulid.ULID, includeUnpublished bool) (detail SeriesDetail, err error) {
+	series, err := findSeriesById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+	if !series.IsPublished && !includeUnpublished {
+		return detail, ErrSeriesDoesNotExist
+	}
+
+	articles, err := findSeriesArticles(ctx, tx, id, includeUnpublished)
+	if err != nil {
+		return
+	}
+
+	return SeriesDetail{Series: series, Articles: articles}, nil
+}
+
+// findSeriesArticles lists the parts of a series in reading order, shaped like
+// listing entries so clients can render them the same way.
+func findSeriesArticles(ctx context.Context, tx pgx.Tx, seriesId ulid.ULID, includeUnpublished bool) (articles []*SeriesArticle, err error) {
+	query := articleQuery{filter: articleFilter{IncludeUnpublished: includeUnpublished}}
+	q, args, err := query.
+		selectFrom(append([]string{"sa.position"}, articleViewModelColumns...)...).
+		InnerJoin("article_categories ac ON a.category_id = ac.id").
+		InnerJoin("series_articles sa ON sa.article_id = a.id").
+		Where("sa.series_id = ?", seriesId.Bytes()).
+		OrderBy("sa.position").
+		ToSql()
+	if err != nil {
+		log.Err(err).Msg("Failed to find series articles")
+		return
+	}
+
+	articles = []*SeriesArticle{}
+	if err = pgxscan.Select(ctx, tx, &articles, q, args...); err != nil {
+		log.Err(err).Msg("Failed to find series articles")
+		return
+	}
+
+	return articles, nil
+}
+
+// findSeriesParts lists the published parts of a series in reading order.
+func findSeriesParts(ctx context.Context, tx pgx.Tx, seriesId ulid.ULID) (parts []*SeriesArticleLink, err error) {
+	q := `
+	SELECT a.id, a.title, sa.position
+	FROM series_articles sa
+	INNER JOIN articles a ON a.id = sa.article_id
+	WHERE sa.series_id = $1 AND a.deleted_at IS NULL AND a.is_published IS TRUE
+	ORDER BY sa.position
+	`
+
+	parts = []*SeriesArticleLink{}
+	if err = pgxscan.Select(ctx, tx, &parts, q, seriesId); err != nil {
+		log.Err(err).Msg("Failed to find series parts")
+		return
+	}
+
+	return parts, nil
+}
+
+// findArticleSeriesNavigation returns nil when the article isn't part of a
+// published series.
+func findArticleSeriesNavigation(ctx context.Context, tx pgx.Tx, articleId ulid.ULID) (navigation *ArticleSeriesNavigation, err error) {
+	q := `
+	SELECT s.id, s.title, sa.position
+	FROM series_articles sa
+	INNER JOIN series s ON s.id = sa.series_id
+	WHERE sa.article_id = $1 AND s.deleted_at IS NULL AND s.is_published IS TRUE
+	`
+
+	var current SeriesArticleLink
+	if err = pgxscan.Get(ctx, tx, &current, q, articleId); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return nil, nil
+		}
+
+		log.Err(err).Msg("Failed to find article series navigation")
+		return nil, err
+	}
+
+	parts, err := findSeriesParts(ctx, tx, current.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	navigation = &ArticleSeriesNavigation{
+		Id:         current.Id,
+		Title:      current.Title,
+		Part:       1,
+		TotalParts: uint(len(parts)),
+	}
+	for _, part := range parts {
+		switch {
+		case part.Position < current.Position:
+			navigation.Part++
+			navigation.Previous = part
+		case part.Position > current.Position && navigation.Next == nil:
+			navigation.Next = part
+		}
+	}
+
+	return navigation, nil
+}
+
+func saveSeries(ctx context.Context, tx pgx.Tx, series Series) (savedSeries Series, err error) {
+	q := `
+	INSERT INTO series(id, title, description, is_published, published_at, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING *
+	`
+
+	if err = pgxscan.Get(
+		ctx,
+		tx,
+		&savedSeries,
+		q,
+		series.Id,
+		series.Title,
+		series.Description,
+		series.IsPublished,
+		series.PublishedAt,
+		series.CreatedAt,
+	); err != nil {
+		log.Err(err).Msg("Failed to save series")
+		return
+	}
+
+	return savedSeries, nil
+}
+
+func updateSeriesById(ctx context.Context, tx pgx.Tx, series Series) (updatedSeries Series, err error) {
+	q := `
+	UPDATE series
+	SET title = $2, description = $3, is_published = $4, published_at = $5, updated_at = $6
+	WHERE id = $1 AND deleted_at IS NULL
+	RETURNING *
+	`
+
+	if err = pgxscan.Get(
+		ctx,
+		tx,
+		&updatedSeries,
+		q,
+		series.Id,
+		series.Title,
+		series.Description,
+		series.IsPublished,
+		series.PublishedAt,
+		series.UpdatedAt,
+	); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return updatedSeries, ErrSeriesDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to update series")
+		return
+	}
+
+	return updatedSeries, nil
+}
+
+// deleteSeries frees its articles right away so they can join another series.
+func deleteSeries(ctx context.Context, tx pgx.Tx, series Series) (err error) {
+	if _, err = tx.Exec(ctx, "DELETE FROM series_articles WHERE series_id = $1", series.Id); err != nil {
+		log.Err(err).Msg("Failed to delete series")
+		return
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM series_progress WHERE series_id = $1", series.Id); err != nil {
+		log.Err(err).Msg("Failed to delete series")
+		return
+	}
+
+	if _, err = tx.Exec(ctx, "UPDATE series SET deleted_at = $2 WHERE id = $1", series.Id, series.DeletedAt); err != nil {
+		log.Err(err).Msg("Failed to delete series")
+		return
+	}
+
+	return nil
+}
+
+// saveSeriesArticles replaces the parts of a series, positions follow the
+// order of articleIds.
+func saveSeriesArticles(ctx context.Context, tx pgx.Tx, seriesId ulid.ULID, articleIds []ulid.ULID) (err error) {
+	var count int
+	q := "SELECT COUNT(*) FROM articles WHERE id = ANY($1) AND deleted_at IS NULL"
+	if err = tx.QueryRow(ctx, q, ulidBytes(articleIds)).Scan(&count); err != nil {
+		log.Err(err).Msg("Failed to save series articles")
+		return
+	}
+	if count != len(articleIds) {
+		return ErrSeriesArticleDoesNotExist
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM series_articles WHERE series_id = $1", seriesId); err != nil {
+		log.Err(err).Msg("Failed to save series articles")
+		return
+	}
+
+	q = `
+	INSERT INTO series_articles(series_id, article_id, position)
+	SELECT $1, parts.article_id, parts.position
+	FROM UNNEST($2::BYTEA[]) WITH ORDINALITY parts(article_id, position)
+	`
+	if _, err = tx.Exec(ctx, q, seriesId, ulidBytes(articleIds)); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				return ErrArticleInAnotherSeries
+			}
+		}
+
+		log.Err(err).Msg("Failed to save series articles")
+		return
+	}
+
+	return nil
+}
+
+func findSeriesReads(ctx context.Context, tx pgx.Tx, userId, seriesId ulid.ULID) (reads []*seriesRead, err error) {
+	q := "SELECT article_id, read_at FROM series_progress WHERE user_id = $1 AND series_id = $2"
+
+	reads = []*seriesRead{}
+	if err = pgxscan.Select(ctx, tx, &reads, q, userId, seriesId); err != nil {
+		log.Err(err).Msg("Failed to find series reads")
+		return
+	}
+
+	return reads, nil
+}
+
+func saveSeriesRead(ctx context.Context, tx pgx.Tx, userId, seriesId, articleId ulid.ULID) (err error) {
+	q := `
+	INSERT INTO series_progress(user_id, series_id, article_id)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (user_id, series_id, article_id) DO UPDATE SET read_at = NOW()
+	`
+
+	if _, err = tx.Exec(ctx, q, userId, seriesId, articleId); err != nil {
+		log.Err(err).Msg("Failed to save series read")
+		return
+	}
+
+	return nil
+}
+
+func deleteSeriesReads(ctx context.Context, tx pgx.Tx, userId, seriesId ulid.ULID) (err error) {
+	if _, err = tx.Exec(ctx, "DELETE FROM series_progress WHERE user_id = $1 AND series_id = $2", userId, seriesId); err != nil {
+		log.Err(err).Msg("Failed to delete series reads")
+		return
+	}
+
+	return nil
+}
+
+// findSeriesProgress counts reads against the current published parts, so
+// parts removed from the series later stop counting.
+func findSeriesProgress(ctx context.Context, tx pgx.Tx, seriesId, userId ulid.ULID) (progress SeriesProgress, err error) {
+	series, err := findSeriesById(ctx, tx, seriesId)
+	if err != nil {
+		return
+	}
+	if !series.IsPublished {
+		return progress, ErrSeriesDoesNotExist
+	}
+
+	parts, err := findSeriesParts(ctx, tx, seriesId)
+	if err != nil {
+		return
+	}
+
+	reads, err := findSeriesReads(ctx, tx, userId, seriesId)
+	if err != nil {
+		return
+	}
+
+	readAt := make(map[ulid.ULID]time.Time)
+	for _, read := range reads {
+		readAt[read.ArticleId] = read.ReadAt
+	}
+
+	progress = SeriesProgress{
+		SeriesId:       seriesId,
+		ReadArticleIds: []ulid.ULID{},
+		TotalParts:     uint(len(parts)),
+	}
+
+	var lastReadAt time.Time
+	for _, part := range parts {
+		partReadAt, isRead := readAt[part.Id]
+		if !isRead {
+			if progress.NextArticleId == nil {
+				progress.NextArticleId = &part.Id
+			}
+			continue
+		}
+
+		progress.ReadArticleIds = append(progress.ReadArticleIds, part.Id)
+		if partReadAt.After(lastReadAt) {
+			lastReadAt = partReadAt
+			progress.LastReadArticleId = &part.Id
+		}
+	}
+
+	progress.ReadParts = uint(len(progress.ReadArticleIds))
+	progress.IsCompleted = progress.TotalParts != 0 && progress.ReadParts == progress.TotalParts
+
+	return progress, nil
+}
@@ -0,0 +1,353 @@
+package article
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func getSeries(ctx context.Context, query string, limit uint, includeUnpublished bool) (series []*SeriesViewModel, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get series")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	series, err = findSeries(ctx, tx, strings.TrimSpace(query), limit, includeUnpublished)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get series")
+		return
+	}
+
+	return series, nil
+}
+
+func getSeriesDetail(ctx context.Context, idStr string, includeUnpublished bool) (detail SeriesDetail, err error) {
+	id, err := validateSeriesId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get series detail")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	detail, err = findSeriesDetail(ctx, tx, id, includeUnpublished)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get series detail")
+		return
+	}
+
+	return detail, nil
+}
+
+func createSeries(ctx context.Context, body createSeriesReq) (series Series, errs map[string]error, err error) {
+	series, errs = NewSeries(body.Title, body.Description)
+	if errs != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to create series")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	series, err = saveSeries(ctx, tx, series)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to create series")
+		return
+	}
+
+	return series, nil, nil
+}
+
+func updateSeries(ctx context.Context, idStr string, body updateSeriesReq) (series Series, errs map[string]error, err error) {
+	id, err := validateSeriesId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to update series")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	series, err = findSeriesById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	if errs = series.Update(body.Title, body.Description); errs != nil {
+		return
+	}
+
+	series, err = updateSeriesById(ctx, tx, series)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to update series")
+		return
+	}
+
+	return series, nil, nil
+}
+
+func removeSeries(ctx context.Context, idStr string) (err error) {
+	id, err := validateSeriesId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to remove series")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	series, err := findSeriesById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	series.Delete()
+	if err = deleteSeries(ctx, tx, series); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to remove series")
+		return
+	}
+
+	return nil
+}
+
+// setSeriesArticles replaces the parts of a series, which also covers adding,
+// removing and reordering them. A published series can't be emptied.
+func setSeriesArticles(ctx context.Context, idStr string, body setSeriesArticlesReq) (detail SeriesDetail, err error) {
+	id, err := validateSeriesId(idStr)
+	if err != nil {
+		return
+	}
+
+	articleIds, err := validateSeriesArticleIds(body.ArticleIds)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to set series articles")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	series, err := findSeriesById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+	if series.IsPublished && len(articleIds) == 0 {
+		return detail, ErrSeriesEmpty
+	}
+
+	if err = saveSeriesArticles(ctx, tx, id, articleIds); err != nil {
+		return
+	}
+
+	detail, err = findSeriesDetail(ctx, tx, id, true)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to set series articles")
+		return
+	}
+
+	return detail, nil
+}
+
+func publishSeries(ctx context.Context, idStr string, body publishSeriesReq) (series Series, err error) {
+	id, err := validateSeriesId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to publish series")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	detail, err := findSeriesDetail(ctx, tx, id, true)
+	if err != nil {
+		return
+	}
+
+	series = detail.Series
+	if err = series.Publish(body.IsPublished, uint(len(detail.Articles))); err != nil {
+		return
+	}
+
+	series, err = updateSeriesById(ctx, tx, series)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to publish series")
+		return
+	}
+
+	// Cached article listings filter and label articles by their published series
+	app.PurgeResponseCache()
+
+	return series, nil
+}
+
+func getSeriesProgress(ctx context.Context, idStr string, userId ulid.ULID) (progress SeriesProgress, err error) {
+	id, err := validateSeriesId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get series progress")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	progress, err = findSeriesProgress(ctx, tx, id, userId)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get series progress")
+		return
+	}
+
+	return progress, nil
+}
+
+func markSeriesArticleRead(ctx context.Context, idStr, articleIdStr string, userId ulid.ULID) (progress SeriesProgress, err error) {
+	id, err := validateSeriesId(idStr)
+	if err != nil {
+		return
+	}
+
+	articleId, err := validateArticleId(articleIdStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to mark series article as read")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if _, err = findSeriesProgress(ctx, tx, id, userId); err != nil {
+		return
+	}
+
+	parts, err := findSeriesParts(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	isPart := false
+	for _, part := range parts {
+		if part.Id.Compare(articleId) == 0 {
+			isPart = true
+			break
+		}
+	}
+	if !isPart {
+		return progress, ErrArticleNotInSeries
+	}
+
+	if err = saveSeriesRead(ctx, tx, userId, id, articleId); err != nil {
+		return
+	}
+
+	progress, err = findSeriesProgress(ctx, tx, id, userId)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to mark series article as read")
+		return
+	}
+
+	return progress, nil
+}
+
+func resetSeriesProgress(ctx context.Context, idStr string, userId ulid.ULID) (err error) {
+	id, err := validateSeriesId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to reset series progress")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if _, err = findSeriesProgress(ctx, tx, id, userId); err != nil {
+		return
+	}
+
+	if err = deleteSeriesReads(ctx, tx, userId, id); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to reset series progress")
+		return
+	}
+
+	return nil
+}
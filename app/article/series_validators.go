@@ -0,0 +1,63 @@
+package article
+
+import (
+	"strings"
+
+	"github.com/jellydator/validation"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrInvalidSeriesId          = validation.NewError("article:invalid_series_id", "Invalid series id")
+	ErrSeriesTitleEmpty         = validation.NewError("article:series_title_empty", "Series title can't be empty")
+	ErrSeriesTitleTooLong       = validation.NewError("article:series_title_too_long", "Series title can't be longer than 255 characters")
+	ErrSeriesDescriptionTooLong = validation.NewError("article:series_description_too_long", "Series description can't be longer than 2000 characters")
+	ErrSeriesDuplicateArticle   = validation.NewError("article:series_duplicate_article", "An article can only appear once in a series")
+	ErrSeriesEmpty              = validation.NewError("article:series_empty", "A series needs at least one article to be published")
+)
+
+func validateSeriesId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidSeriesId
+	}
+
+	return id, nil
+}
+
+func validateSeriesTitle(title string) error {
+	title = strings.TrimSpace(title)
+	return validation.Validate(
+		&title,
+		validation.Required.ErrorObject(ErrSeriesTitleEmpty),
+		validation.Length(1, 255).ErrorObject(ErrSeriesTitleTooLong),
+	)
+}
+
+func validateSeriesDescription(description string) error {
+	description = strings.TrimSpace(description)
+	return validation.Validate(
+		&description,
+		validation.RuneLength(0, 2000).ErrorObject(ErrSeriesDescriptionTooLong),
+	)
+}
+
+// validateSeriesArticleIds parses the parts of a series in reading order.
+func validateSeriesArticleIds(idStrs []string) (ids []ulid.ULID, err error) {
+	seen := make(map[ulid.ULID]bool)
+	ids = []ulid.ULID{}
+	for _, idStr := range idStrs {
+		id, err := validateArticleId(idStr)
+		if err != nil {
+			return nil, err
+		}
+		if seen[id] {
+			return nil, ErrSeriesDuplicateArticle
+		}
+
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
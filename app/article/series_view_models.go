@@ -0,0 +1,54 @@
+package article
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type SeriesViewModel struct {
+	Series
+	ArticleCount uint `json:"article_count"`
+}
+
+type SeriesArticle struct {
+	ArticleViewModel
+	Position uint `json:"position"`
+}
+
+type SeriesDetail struct {
+	Series
+	Articles []*SeriesArticle `json:"articles"`
+}
+
+type SeriesArticleLink struct {
+	Id       ulid.ULID `json:"id"`
+	Title    string    `json:"title"`
+	Position uint      `json:"position"`
+}
+
+// ArticleSeriesNavigation places an article within its series. Parts are
+// counted over published articles only, the way readers see the series.
+type ArticleSeriesNavigation struct {
+	Id         ulid.ULID          `json:"id"`
+	Title      string             `json:"title"`
+	Part       uint               `json:"part"`
+	TotalParts uint               `json:"total_parts"`
+	Previous   *SeriesArticleLink `json:"previous"`
+	Next       *SeriesArticleLink `json:"next"`
+}
+
+type SeriesProgress struct {
+	SeriesId          ulid.ULID   `json:"series_id"`
+	ReadArticleIds    []ulid.ULID `json:"read_article_ids"`
+	ReadParts         uint        `json:"read_parts"`
+	TotalParts        uint        `json:"total_parts"`
+	LastReadArticleId *ulid.ULID  `json:"last_read_article_id"`
+	NextArticleId     *ulid.ULID  `json:"next_article_id"`
+	IsCompleted       bool        `json:"is_completed"`
+}
+
+type seriesRead struct {
+	ArticleId ulid.ULID
+	ReadAt    time.Time
+}
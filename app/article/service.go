@@ -2,13 +2,19 @@ package article
 
 import (
 	"context"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/lexica-app/lexicapi/app/moderation"
 	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
 )
 
-func regenerateOpenAIArticleText(ctx context.Context, idStr, articleIdStr string, body regenerateOpenAIArticleTextReq) (text ArticleText, errs map[string]error, err error) {
+func regenerateOpenAIArticleText(ctx context.Context, idStr, articleIdStr string, version uint, body regenerateOpenAIArticleTextReq) (generated GeneratedArticleText, errs map[string]error, err error) {
 	articleId, err := validateArticleId(articleIdStr)
 	if err != nil {
 		return
@@ -22,62 +28,79 @@ func regenerateOpenAIArticleText(ctx context.Context, idStr, articleIdStr string
 	switch body.Difficulty {
 	case string(ADVANCED), string(INTERMEDIATE), string(BEGINNER):
 	default:
-		return text, nil, ErrInvalidArticleTextDifficulty
+		return generated, nil, ErrInvalidArticleTextDifficulty
 	}
 
-	tx, err := pool.Begin(ctx)
+	document, err := parseArticleDocument(body.Document, body.Markdown)
 	if err != nil {
-		log.Err(err).Msg("Failed to regenerate OpenAI article text")
-		return
+		return generated, map[string]error{"document": err}, nil
 	}
 
-	defer tx.Rollback(ctx)
+	settings, err := adapters.LLMTaskSettingsFor(adapters.LLMTaskArticleText).Override(body.ModelSettings)
+	if err != nil {
+		return
+	}
 
-	_, err = findArticleById(ctx, tx, articleId)
+	text, err := checkGeneratedArticleTextTarget(ctx, articleId, &id, body.Difficulty)
 	if err != nil {
 		return
 	}
+	// Don't spend a generation on a text that was already edited
+	if text.Version != version {
+		return GeneratedArticleText{ArticleText: text}, nil, ErrArticleTextVersionConflict
+	}
 
-	text, err = findArticleTextByIdAndArticleId(ctx, tx, id, articleId)
+	generatedText, generatedDocument, promptVersion, quality, err := generateArticleTextContent(ctx, settings, body.Difficulty, body.Content, document)
 	if err != nil {
 		return
 	}
 
-	existingText, err := findArticleTextByArticleIdAndDifficulty(ctx, tx, articleId, body.Difficulty)
-	if err != nil || existingText != (ArticleText{}) {
-		if err == ErrArticleTextDoesNotExist || existingText.Id == text.Id {
-			generatedText, err := generateArticleText(ctx, string(ADVANCED), body.Difficulty, body.Content)
-			if err != nil {
-				return text, nil, err
-			}
+	if errs = text.Update(generatedText, body.Difficulty, body.IsAdapted); errs != nil {
+		return generated, errs, nil
+	}
+	text.SetDocument(generatedDocument)
+	text.PromptVersion = null.StringFrom(promptVersion)
+	text.Quality = &quality
 
-			if errs = text.Update(generatedText, body.Difficulty, body.IsAdapted); errs != nil {
-				return text, errs, nil
-			}
+	verdict := moderateArticleText(ctx, &text)
+	if verdict.Decision == moderation.BLOCK {
+		return generated, nil, verdict.Err()
+	}
 
-			text, err = updateArticleTextById(ctx, tx, text)
-			if err != nil {
-				return text, nil, err
-			}
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to regenerate OpenAI article text")
+		return
+	}
 
-			if err = tx.Commit(ctx); err != nil {
-				log.Err(err).Msg("Failed to regenerate OpenAI article text")
-				return text, nil, err
-			}
+	defer tx.Rollback(ctx)
+
+	// Generation can take minutes, so the difficulty is checked again and the
+	// text is only replaced when nobody edited it in the meantime
+	if _, err = findGeneratedArticleTextTarget(ctx, tx, articleId, &id, body.Difficulty); err != nil {
+		return
+	}
 
-			return text, nil, nil
-		} else if existingText != (ArticleText{}) && existingText.Id != text.Id {
-			return text, nil, ErrArticleTextDifficultyExist
+	text, err = updateArticleTextById(ctx, tx, text)
+	if err != nil {
+		if err == ErrArticleTextVersionConflict {
+			if text, err = findArticleTextByIdAndArticleId(ctx, tx, id, articleId); err == nil {
+				err = ErrArticleTextVersionConflict
+			}
+			generated.ArticleText = text
 		}
+		return
+	}
 
+	if err = tx.Commit(ctx); err != nil {
 		log.Err(err).Msg("Failed to regenerate OpenAI article text")
-		return text, nil, err
+		return
 	}
 
-	return text, nil, ErrArticleTextDifficultyExist
+	return GeneratedArticleText{ArticleText: text, Moderation: verdict}, nil, nil
 }
 
-func generateOpenAIArticleText(ctx context.Context, articleIdStr string, body generateOpenAIArticleTextReq) (text ArticleText, errs map[string]error, err error) {
+func generateOpenAIArticleText(ctx context.Context, articleIdStr string, body generateOpenAIArticleTextReq) (generated GeneratedArticleText, errs map[string]error, err error) {
 	articleId, err := validateArticleId(articleIdStr)
 	if err != nil {
 		return
@@ -86,7 +109,39 @@ func generateOpenAIArticleText(ctx context.Context, articleIdStr string, body ge
 	switch body.Difficulty {
 	case string(ADVANCED), string(INTERMEDIATE), string(BEGINNER):
 	default:
-		return text, nil, ErrInvalidArticleTextDifficulty
+		return generated, nil, ErrInvalidArticleTextDifficulty
+	}
+
+	document, err := parseArticleDocument(body.Document, body.Markdown)
+	if err != nil {
+		return generated, map[string]error{"document": err}, nil
+	}
+
+	settings, err := adapters.LLMTaskSettingsFor(adapters.LLMTaskArticleText).Override(body.ModelSettings)
+	if err != nil {
+		return
+	}
+
+	if _, err = checkGeneratedArticleTextTarget(ctx, articleId, nil, body.Difficulty); err != nil {
+		return
+	}
+
+	generatedText, generatedDocument, promptVersion, quality, err := generateArticleTextContent(ctx, settings, body.Difficulty, body.Content, document)
+	if err != nil {
+		return
+	}
+
+	text, errs := NewArticleText(articleIdStr, generatedText, body.Difficulty, body.IsAdapted)
+	if errs != nil {
+		return generated, errs, nil
+	}
+	text.SetDocument(generatedDocument)
+	text.PromptVersion = null.StringFrom(promptVersion)
+	text.Quality = &quality
+
+	verdict := moderateArticleText(ctx, &text)
+	if verdict.Decision == moderation.BLOCK {
+		return generated, nil, verdict.Err()
 	}
 
 	tx, err := pool.Begin(ctx)
@@ -97,40 +152,110 @@ func generateOpenAIArticleText(ctx context.Context, articleIdStr string, body ge
 
 	defer tx.Rollback(ctx)
 
-	_, err = findArticleById(ctx, tx, articleId)
+	// Another text may have taken the difficulty while this one was generated
+	if _, err = findGeneratedArticleTextTarget(ctx, tx, articleId, nil, body.Difficulty); err != nil {
+		return
+	}
+
+	text, err = saveArticleText(ctx, tx, text)
 	if err != nil {
 		return
 	}
 
-	if _, err = findArticleTextByArticleIdAndDifficulty(ctx, tx, articleId, body.Difficulty); err != nil {
-		if err == ErrArticleTextDoesNotExist {
-			generatedText, err := generateArticleText(ctx, string(ADVANCED), body.Difficulty, body.Content)
-			if err != nil {
-				return text, nil, err
-			}
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to generate OpenAI article text")
+		return
+	}
 
-			text, errs := NewArticleText(articleIdStr, generatedText, body.Difficulty, body.IsAdapted)
-			if errs != nil {
-				return text, errs, nil
-			}
+	return GeneratedArticleText{ArticleText: text, Moderation: verdict}, nil, nil
+}
 
-			text, err = saveArticleText(ctx, tx, text)
-			if err != nil {
-				return text, nil, err
-			}
+// checkGeneratedArticleTextTarget fails fast before any tokens are spent on a
+// text that couldn't be saved. Generation runs outside of a transaction, so
+// the check is repeated right before saving.
+func checkGeneratedArticleTextTarget(ctx context.Context, articleId ulid.ULID, textId *ulid.ULID, difficulty string) (text ArticleText, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to check generated article text")
+		return
+	}
 
-			if err = tx.Commit(ctx); err != nil {
-				log.Err(err).Msg("Failed to generate OpenAI article text")
-				return text, nil, err
-			}
+	defer tx.Rollback(ctx)
 
-			return text, nil, nil
-		}
+	text, err = findGeneratedArticleTextTarget(ctx, tx, articleId, textId, difficulty)
+	if err != nil {
+		return
+	}
 
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to check generated article text")
 		return
 	}
 
-	return text, nil, ErrArticleTextDifficultyExist
+	return text, nil
+}
+
+// moderateArticleText checks a generated text before it's saved. Blocked
+// texts are never saved, texts held for review are saved hidden from readers
+// until an admin approves or rejects them, see ApproveModeratedText.
+func moderateArticleText(ctx context.Context, text *ArticleText) moderation.Verdict {
+	verdict := moderation.Check(ctx, moderation.Subject{Kind: moderation.ARTICLE_TEXT, Id: &text.Id, Text: text.Content})
+
+	text.ModerationStatus = TEXT_APPROVED
+	if verdict.Decision == moderation.REVIEW {
+		text.ModerationStatus = TEXT_PENDING
+	}
+
+	return verdict
+}
+
+// generateArticleTextContent checks every generated text against the original
+// and regenerates it with feedback on what failed, up to the configured number
+// of attempts. A text that never passes isn't returned, only the reports are.
+func generateArticleTextContent(ctx context.Context, settings adapters.LLMTaskSettings, targetDifficulty, content string, document *ArticleDocument) (generatedText string, generatedDocument *ArticleDocument, promptVersion string, quality ArticleTextQuality, err error) {
+	original := content
+	if document != nil {
+		original = document.PlainText()
+	}
+
+	feedback := ""
+	for attempt := 1; attempt <= qualityMaxAttempts; attempt++ {
+		generatedText, generatedDocument, promptVersion, err = generateArticleTextAttempt(ctx, settings, targetDifficulty, content, document, feedback)
+		if err != nil {
+			return "", nil, "", quality, err
+		}
+
+		report := checkArticleTextQuality(attempt, string(ADVANCED), targetDifficulty, original, generatedText)
+		quality.Attempts = append(quality.Attempts, report)
+		if report.IsPassed {
+			quality.IsPassed = true
+			return generatedText, generatedDocument, promptVersion, quality, nil
+		}
+
+		log.Warn().Fields(map[string]any{
+			"attempt":      attempt,
+			"max_attempts": qualityMaxAttempts,
+			"failed":       report.failedChecks(),
+		}).Msg("Generated article text failed quality checks")
+
+		feedback = report.feedback()
+	}
+
+	return "", nil, "", quality, &ArticleTextQualityError{Quality: quality}
+}
+
+func generateArticleTextAttempt(ctx context.Context, settings adapters.LLMTaskSettings, targetDifficulty, content string, document *ArticleDocument, feedback string) (string, *ArticleDocument, string, error) {
+	if document == nil {
+		generatedText, promptVersion, err := generateArticleText(ctx, settings, string(ADVANCED), targetDifficulty, content, feedback)
+		return generatedText, nil, promptVersion, err
+	}
+
+	generatedDocument, promptVersion, err := generateArticleDocument(ctx, settings, string(ADVANCED), targetDifficulty, *document, feedback)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	return generatedDocument.PlainText(), &generatedDocument, promptVersion, nil
 }
 
 func getArticleCategories(ctx context.Context, query string, limit uint) (categories []*ArticleCategory, err error) {
@@ -212,12 +337,22 @@ func createArticleCategory(ctx context.Context, name string) (category ArticleCa
 	return category, nil
 }
 
-func deleteArticleCategory(ctx context.Context, idStr string) (err error) {
+// deleteArticleCategory refuses to leave articles behind in a deleted
+// category. They are moved into reassignToStr first when it's given, otherwise
+// the delete is blocked while the category still has articles.
+func deleteArticleCategory(ctx context.Context, idStr, reassignToStr string) (err error) {
 	id, err := validateArticleCategoryId(idStr)
 	if err != nil {
 		return
 	}
 
+	var reassignTo ulid.ULID
+	if reassignToStr != "" {
+		if reassignTo, _, err = validateArticleCategoryMove(id, reassignToStr, nil); err != nil {
+			return
+		}
+	}
+
 	tx, err := pool.Begin(ctx)
 	if err != nil {
 		log.Err(err).Msg("Failed to delete article category")
@@ -226,6 +361,27 @@ func deleteArticleCategory(ctx context.Context, idStr string) (err error) {
 
 	defer tx.Rollback(ctx)
 
+	if _, err = findArticleCategoryById(ctx, tx, id); err != nil {
+		return
+	}
+
+	if reassignToStr != "" {
+		if _, err = findArticleCategoryById(ctx, tx, reassignTo); err != nil {
+			return
+		}
+		if _, err = moveArticlesToCategory(ctx, tx, id, reassignTo, nil); err != nil {
+			return
+		}
+	} else {
+		count, err := countArticlesByCategoryId(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if count != 0 {
+			return ErrArticleCategoryInUse
+		}
+	}
+
 	err = deleteArticleCategoryById(ctx, tx, id)
 	if err != nil {
 		return
@@ -239,7 +395,48 @@ func deleteArticleCategory(ctx context.Context, idStr string) (err error) {
 	return nil
 }
 
-func updateArticleCategory(ctx context.Context, idStr, name string) (category ArticleCategory, err error) {
+func moveArticleCategoryArticles(ctx context.Context, idStr string, body moveArticleCategoryArticlesReq) (moved int64, err error) {
+	id, err := validateArticleCategoryId(idStr)
+	if err != nil {
+		return
+	}
+
+	to, articleIds, err := validateArticleCategoryMove(id, body.ToCategoryId, body.ArticleIds)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to move article category articles")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if _, err = findArticleCategoryById(ctx, tx, id); err != nil {
+		return
+	}
+	if _, err = findArticleCategoryById(ctx, tx, to); err != nil {
+		return
+	}
+
+	moved, err = moveArticlesToCategory(ctx, tx, id, to, articleIds)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to move article category articles")
+		return
+	}
+
+	return moved, nil
+}
+
+// updateArticleCategory applies the rename only when the category is still at
+// version. On a conflict the current category is returned with the error.
+func updateArticleCategory(ctx context.Context, idStr, name string, version uint) (category ArticleCategory, err error) {
 	id, err := validateArticleCategoryId(idStr)
 	if err != nil {
 		return
@@ -256,10 +453,25 @@ func updateArticleCategory(ctx context.Context, idStr, name string) (category Ar
 
 	defer tx.Rollback(ctx)
 
-	category, err = updateArticleCategoryById(ctx, tx, id, name)
+	category, err = findArticleCategoryById(ctx, tx, id)
 	if err != nil {
 		return
 	}
+	if category.Version != version {
+		return category, ErrArticleCategoryVersionConflict
+	}
+
+	updatedCategory, err := updateArticleCategoryById(ctx, tx, id, name, version)
+	if err != nil {
+		// Someone committed in between the read and the write
+		if err == ErrArticleCategoryVersionConflict {
+			if category, err = findArticleCategoryById(ctx, tx, id); err == nil {
+				err = ErrArticleCategoryVersionConflict
+			}
+		}
+		return
+	}
+	category = updatedCategory
 
 	if err = tx.Commit(ctx); err != nil {
 		log.Err(err).Msg("Failed to update article category")
@@ -269,32 +481,81 @@ func updateArticleCategory(ctx context.Context, idStr, name string) (category Ar
 	return category, nil
 }
 
-func getArticles(
-	ctx context.Context,
-	query string,
-	categoryIdStr string,
-	pageSize uint,
-	directionStr string,
-	cursorStr string,
-	includeUnpublished bool,
-) (articles Articles, err error) {
-	query = strings.TrimSpace(query)
-	categoryId, err := validateArticleCategoryId(categoryIdStr)
-	if err != nil {
-		categoryId = ulid.ULID{}
+func getArticles(ctx context.Context, req getArticlesReq) (articles Articles, err error) {
+	filter := articleFilter{
+		Query:              strings.TrimSpace(req.Query),
+		Source:             strings.TrimSpace(req.Source),
+		Author:             strings.TrimSpace(req.Author),
+		IncludeUnpublished: req.IncludeUnpublished,
+	}
+
+	for _, categoryIdStr := range req.CategoryIds {
+		if categoryId, err := validateArticleCategoryId(strings.TrimSpace(categoryIdStr)); err == nil {
+			filter.CategoryIds = append(filter.CategoryIds, categoryId)
+		}
+	}
+	for _, sourceIdStr := range req.SourceIds {
+		if sourceId, err := validateSourceId(strings.TrimSpace(sourceIdStr)); err == nil {
+			filter.SourceIds = append(filter.SourceIds, sourceId)
+		}
+	}
+	for _, authorIdStr := range req.AuthorIds {
+		if authorId, err := validateAuthorId(strings.TrimSpace(authorIdStr)); err == nil {
+			filter.AuthorIds = append(filter.AuthorIds, authorId)
+		}
+	}
+	for _, seriesIdStr := range req.SeriesIds {
+		if seriesId, err := validateSeriesId(strings.TrimSpace(seriesIdStr)); err == nil {
+			filter.SeriesIds = append(filter.SeriesIds, seriesId)
+		}
+	}
+	for _, difficulty := range req.Difficulties {
+		difficulty = strings.TrimSpace(difficulty)
+		if validateArticleTextDifficulty(difficulty) == nil {
+			filter.Difficulties = append(filter.Difficulties, difficulty)
+		}
+	}
+
+	if publishedFrom, ok := parseArticleListingTime(req.PublishedFrom, false); ok {
+		filter.PublishedFrom = null.TimeFrom(publishedFrom)
+	}
+	if publishedUntil, ok := parseArticleListingTime(req.PublishedUntil, true); ok {
+		filter.PublishedUntil = null.TimeFrom(publishedUntil)
+	}
+
+	if minutes, err := strconv.Atoi(req.MinReadingTime); err == nil && minutes >= 0 {
+		filter.MinReadingTime = null.IntFrom(int64(minutes))
+	}
+	if minutes, err := strconv.Atoi(req.MaxReadingTime); err == nil && minutes >= 0 {
+		filter.MaxReadingTime = null.IntFrom(int64(minutes))
+	}
+
+	var sort ArticleSort
+	switch req.Sort {
+	case string(OLDEST), string(TITLE), string(POPULARITY):
+		sort = ArticleSort(req.Sort)
+	case string(SERIES):
+		// Positions only make sense within a single series
+		sort = NEWEST
+		if len(filter.SeriesIds) == 1 {
+			sort = SERIES
+		}
+	default:
+		sort = NEWEST
 	}
 
 	var direction ArticlePaginationDirection
-	switch directionStr {
+	switch req.Direction {
 	case string(PREVIOUS):
 		direction = PREVIOUS
 	default:
 		direction = NEXT
 	}
 
-	cursor, err := validateArticleId(cursorStr)
+	// A stale or tampered cursor starts over from the first page
+	cursorId, cursorValue, err := decodeArticleCursor(req.Cursor, sort)
 	if err != nil {
-		cursor = ulid.ULID{}
+		cursorId, cursorValue = ulid.ULID{}, nil
 	}
 
 	tx, err := pool.Begin(ctx)
@@ -305,7 +566,7 @@ func getArticles(
 
 	defer tx.Rollback(ctx)
 
-	articles, err = findArticles(ctx, tx, query, categoryId, pageSize, direction, cursor, includeUnpublished)
+	articles, err = findArticles(ctx, tx, articleQuery{filter: filter, sort: sort}, req.PageSize, direction, cursorId, cursorValue)
 	if err != nil {
 		return
 	}
@@ -318,12 +579,38 @@ func getArticles(
 	return articles, nil
 }
 
+// parseArticleListingTime accepts either a date or a full timestamp. A date used
+// as an upper bound covers the whole day.
+func parseArticleListingTime(timeStr string, upperBound bool) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, timeStr); err == nil {
+		return t, true
+	}
+
+	t, err := time.Parse("2006-01-02", timeStr)
+	if err != nil {
+		return t, false
+	}
+	if upperBound {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return t, true
+}
+
 func createArticle(ctx context.Context, body createArticleReq) (articleDetail ArticleDetail, errs map[string]error, err error) {
 	article, errs := NewArticle(body.CategoryId, body.Title, body.ThumbnailUrl, body.OriginalUrl, body.Source, body.Author, body.IsPublished)
 	if errs != nil {
 		return articleDetail, errs, nil
 	}
 
+	originalDocument, err := parseArticleDocument(body.OriginalDocument, body.OriginalMarkdown)
+	if err != nil {
+		return articleDetail, map[string]error{"original_document": err}, nil
+	}
+	if originalDocument != nil {
+		body.OriginalContent = originalDocument.PlainText()
+	}
+
 	tx, err := pool.Begin(ctx)
 	if err != nil {
 		log.Err(err).Msg("Failed to create article")
@@ -332,6 +619,13 @@ func createArticle(ctx context.Context, body createArticleReq) (articleDetail Ar
 
 	defer tx.Rollback(ctx)
 
+	if err = resolveArticleSource(ctx, tx, &article); err != nil {
+		return
+	}
+	if err = resolveArticleAuthor(ctx, tx, &article); err != nil {
+		return
+	}
+
 	article, err = saveArticle(ctx, tx, article)
 	if err != nil {
 		return
@@ -346,6 +640,8 @@ func createArticle(ctx context.Context, body createArticleReq) (articleDetail Ar
 	if errs != nil {
 		return articleDetail, errs, nil
 	}
+	originalText.SetDocument(originalDocument)
+
 	originalText, err = saveArticleText(ctx, tx, originalText)
 	if err != nil {
 		return
@@ -359,7 +655,7 @@ func createArticle(ctx context.Context, body createArticleReq) (articleDetail Ar
 	return ArticleDetail{Article: article, Texts: map[string]ArticleText{originalText.Difficulty: originalText}}, nil, nil
 }
 
-func getArticleById(ctx context.Context, idStr string) (articleDetail ArticleDetail, err error) {
+func getArticleById(ctx context.Context, idStr string, includeUnpublished bool) (articleDetail ArticleDetail, err error) {
 	id, err := validateArticleId(idStr)
 	if err != nil {
 		return
@@ -377,39 +673,75 @@ func getArticleById(ctx context.Context, idStr string) (articleDetail ArticleDet
 	if err != nil {
 		return
 	}
+	if !article.IsPublished && !includeUnpublished {
+		err = ErrArticleDoesNotExist
+		return
+	}
 
-	var categoryName string
-	category, err := findArticleCategoryById(ctx, tx, article.CategoryId)
+	articleDetail, err = findArticleDetail(ctx, tx, article, includeUnpublished)
 	if err != nil {
-		if err != ErrArticleCategoryDoesNotExist {
-			return
-		}
+		return
+	}
 
-		categoryName = "Deleted Category"
-		err = nil
-	} else {
-		categoryName = category.Name
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get article by id")
+		return
 	}
 
-	texts, err := findArticleTextsByArticleId(ctx, tx, article.Id)
+	return articleDetail, nil
+}
+
+func renderArticleText(ctx context.Context, articleIdStr, difficulty, formatStr string) (rendered string, format ArticleDocumentFormat, err error) {
+	articleId, err := validateArticleId(articleIdStr)
 	if err != nil {
 		return
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		log.Err(err).Msg("Failed to get article by id")
+	format, err = validateArticleDocumentFormat(formatStr)
+	if err != nil {
+		return
+	}
+
+	if difficulty == "" {
+		difficulty = string(ADVANCED)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to render article text")
 		return
 	}
 
-	textMap := make(map[string]ArticleText)
-	for _, text := range texts {
-		textMap[text.Difficulty] = *text
+	defer tx.Rollback(ctx)
+
+	// Rendering is public, drafts are only readable through preview links
+	article, err := findArticleById(ctx, tx, articleId)
+	if err != nil {
+		return
+	}
+	if !article.IsPublished {
+		return rendered, format, ErrArticleDoesNotExist
 	}
 
-	return ArticleDetail{Article: article, CategoryName: categoryName, Texts: textMap}, nil
+	text, err := findArticleTextByArticleIdAndDifficulty(ctx, tx, articleId, difficulty)
+	if err != nil {
+		return
+	}
+	if text.ModerationStatus != TEXT_APPROVED {
+		return rendered, format, ErrArticleTextDoesNotExist
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to render article text")
+		return
+	}
+
+	return text.RenderableDocument().Render(format), format, nil
 }
 
-func updateArticle(ctx context.Context, idStr string, body updateArticleReq) (article Article, errs map[string]error, err error) {
+// updateArticle applies body only when the article is still at version. On a
+// conflict the current article is returned with the error.
+func updateArticle(ctx context.Context, idStr string, version uint, body updateArticleReq) (article Article, errs map[string]error, err error) {
 	id, err := validateArticleId(idStr)
 	if err != nil {
 		return
@@ -427,6 +759,9 @@ func updateArticle(ctx context.Context, idStr string, body updateArticleReq) (ar
 	if err != nil {
 		return
 	}
+	if article.Version != version {
+		return article, nil, ErrArticleVersionConflict
+	}
 
 	if errs = article.Update(
 		body.CategoryId,
@@ -440,8 +775,25 @@ func updateArticle(ctx context.Context, idStr string, body updateArticleReq) (ar
 		return
 	}
 
+	if body.Source.Valid {
+		if err = resolveArticleSource(ctx, tx, &article); err != nil {
+			return
+		}
+	}
+	if body.Author.Valid {
+		if err = resolveArticleAuthor(ctx, tx, &article); err != nil {
+			return
+		}
+	}
+
 	article, err = updateArticleById(ctx, tx, article)
 	if err != nil {
+		// Someone committed in between the read and the write
+		if err == ErrArticleVersionConflict {
+			if article, err = findArticleById(ctx, tx, id); err == nil {
+				err = ErrArticleVersionConflict
+			}
+		}
 		return
 	}
 
@@ -489,10 +841,19 @@ func removeArticle(ctx context.Context, idStr string) (err error) {
 }
 
 func createArticleText(ctx context.Context, articleId string, body createArticleTextReq) (text ArticleText, errs map[string]error, err error) {
+	document, err := parseArticleDocument(body.Document, body.Markdown)
+	if err != nil {
+		return text, map[string]error{"document": err}, nil
+	}
+	if document != nil {
+		body.Content = document.PlainText()
+	}
+
 	text, errs = NewArticleText(articleId, body.Content, body.Difficulty, body.IsAdapted)
 	if errs != nil {
 		return
 	}
+	text.SetDocument(document)
 
 	tx, err := pool.Begin(ctx)
 	if err != nil {
@@ -515,7 +876,9 @@ func createArticleText(ctx context.Context, articleId string, body createArticle
 	return text, nil, nil
 }
 
-func updateArticleText(ctx context.Context, idStr, articleIdStr string, body updateArticleTextReq) (text ArticleText, errs map[string]error, err error) {
+// updateArticleText applies body only when the text is still at version. On a
+// conflict the current text is returned with the error.
+func updateArticleText(ctx context.Context, idStr, articleIdStr string, version uint, body updateArticleTextReq) (text ArticleText, errs map[string]error, err error) {
 	id, err := validateArticleTextId(idStr)
 	if err != nil {
 		return
@@ -526,6 +889,14 @@ func updateArticleText(ctx context.Context, idStr, articleIdStr string, body upd
 		return
 	}
 
+	document, err := parseArticleDocument(body.Document, body.Markdown)
+	if err != nil {
+		return text, map[string]error{"document": err}, nil
+	}
+	if document != nil {
+		body.Content = document.PlainText()
+	}
+
 	tx, err := pool.Begin(ctx)
 	if err != nil {
 		log.Err(err).Msg("Failed to update article text")
@@ -538,13 +909,23 @@ func updateArticleText(ctx context.Context, idStr, articleIdStr string, body upd
 	if err != nil {
 		return
 	}
+	if text.Version != version {
+		return text, nil, ErrArticleTextVersionConflict
+	}
 
 	if errs = text.Update(body.Content, body.Difficulty, body.IsAdapted); errs != nil {
 		return
 	}
+	text.SetDocument(document)
 
 	text, err = updateArticleTextById(ctx, tx, text)
 	if err != nil {
+		// Someone committed in between the read and the write
+		if err == ErrArticleTextVersionConflict {
+			if text, err = findArticleTextByIdAndArticleId(ctx, tx, id, articleId); err == nil {
+				err = ErrArticleTextVersionConflict
+			}
+		}
 		return
 	}
 
@@ -556,6 +937,38 @@ func updateArticleText(ctx context.Context, idStr, articleIdStr string, body upd
 	return text, nil, nil
 }
 
+func getArticleText(ctx context.Context, idStr, articleIdStr string) (text ArticleText, err error) {
+	id, err := validateArticleTextId(idStr)
+	if err != nil {
+		return
+	}
+
+	articleId, err := validateArticleId(articleIdStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get article text")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	text, err = findArticleTextByIdAndArticleId(ctx, tx, id, articleId)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get article text")
+		return
+	}
+
+	return text, nil
+}
+
 func removeArticleText(ctx context.Context, idStr, articleIdStr string) (err error) {
 	id, err := validateArticleTextId(idStr)
 	if err != nil {
@@ -592,3 +1005,46 @@ func removeArticleText(ctx context.Context, idStr, articleIdStr string) (err err
 
 	return nil
 }
+
+// ApproveModeratedText publishes a text held for review once an admin approved
+// it. A text that's gone or was regenerated since it was flagged is left as is.
+func ApproveModeratedText(ctx context.Context, tx pgx.Tx, id ulid.ULID, contentHash string) (err error) {
+	text, err := findModeratedText(ctx, tx, id, contentHash)
+	if err != nil || text == nil {
+		return
+	}
+
+	return approveArticleTextById(ctx, tx, text.Id)
+}
+
+// TrashModeratedText moves a text an admin rejected in moderation to the
+// trash. A text that's gone or was regenerated since it was flagged is left as
+// is, its new content went through moderation on its own.
+func TrashModeratedText(ctx context.Context, tx pgx.Tx, id ulid.ULID, contentHash string) (err error) {
+	text, err := findModeratedText(ctx, tx, id, contentHash)
+	if err != nil || text == nil {
+		return
+	}
+
+	text.Delete()
+	return deleteArticleText(ctx, tx, *text)
+}
+
+// findModeratedText returns nil when there's no longer a text with the
+// content a flag was raised on.
+func findModeratedText(ctx context.Context, tx pgx.Tx, id ulid.ULID, contentHash string) (*ArticleText, error) {
+	text, err := findArticleTextById(ctx, tx, id)
+	if err != nil {
+		if err == ErrArticleTextDoesNotExist {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if moderation.ContentHash(text.Content) != contentHash {
+		log.Info().Str("article_text_id", id.String()).Msg("Moderated article text changed since it was flagged, leaving it")
+		return nil, nil
+	}
+
+	return &text, nil
+}
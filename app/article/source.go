@@ -0,0 +1,142 @@
+package article
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Source is the canonical publisher of articles. Aliases hold the lowercased
+// names of sources merged into it so they keep resolving here.
+type Source struct {
+	Id        ulid.ULID   `json:"id"`
+	Name      string      `json:"name"`
+	Domain    null.String `json:"domain"`
+	LogoUrl   null.String `json:"logo_url"`
+	Aliases   []string    `json:"aliases"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt null.Time   `json:"updated_at"`
+	DeletedAt null.Time   `json:"deleted_at"`
+}
+
+func NewSource(name string, domain, logoUrl null.String) (Source, map[string]error) {
+	errs := make(map[string]error)
+
+	name = strings.TrimSpace(name)
+	if err := validateSourceName(name); err != nil {
+		errs["name"] = err
+	}
+	if err := validateSourceDomain(domain.String); err != nil {
+		errs["domain"] = err
+	}
+	if err := validateSourceLogoUrl(logoUrl.String); err != nil {
+		errs["logo_url"] = err
+	}
+
+	if len(errs) != 0 {
+		return Source{}, errs
+	}
+
+	return Source{
+		Id:        ulid.Make(),
+		Name:      name,
+		Domain:    normalizeSourceDomain(domain),
+		LogoUrl:   logoUrl,
+		Aliases:   []string{},
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (s *Source) Update(name, domain, logoUrl null.String) map[string]error {
+	errs := make(map[string]error)
+
+	if name.Valid {
+		if err := validateSourceName(name.String); err != nil {
+			errs["name"] = err
+		}
+		s.Name = strings.TrimSpace(name.String)
+	}
+
+	if domain.Valid {
+		if err := validateSourceDomain(domain.String); err != nil {
+			errs["domain"] = err
+		}
+		s.Domain = normalizeSourceDomain(domain)
+	}
+
+	if logoUrl.Valid {
+		if err := validateSourceLogoUrl(logoUrl.String); err != nil {
+			errs["logo_url"] = err
+		}
+		s.LogoUrl = logoUrl
+		if logoUrl.String == "" {
+			s.LogoUrl = null.NewString("", false)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+
+	s.UpdatedAt = null.TimeFrom(time.Now())
+
+	return nil
+}
+
+// Merge takes over the names and aliases of the other sources, along with
+// their domain and logo when this source doesn't have its own.
+func (s *Source) Merge(others []*Source) {
+	aliases := make(map[string]bool)
+	for _, alias := range s.Aliases {
+		aliases[alias] = true
+	}
+
+	for _, other := range others {
+		for _, alias := range append([]string{strings.ToLower(other.Name)}, other.Aliases...) {
+			if alias != strings.ToLower(s.Name) && !aliases[alias] {
+				aliases[alias] = true
+				s.Aliases = append(s.Aliases, alias)
+			}
+		}
+
+		if !s.Domain.Valid {
+			s.Domain = other.Domain
+		}
+		if !s.LogoUrl.Valid {
+			s.LogoUrl = other.LogoUrl
+		}
+	}
+
+	s.UpdatedAt = null.TimeFrom(time.Now())
+}
+
+func (s *Source) Delete() {
+	if !s.DeletedAt.Valid {
+		s.DeletedAt = null.TimeFrom(time.Now())
+	}
+}
+
+// normalizeSourceDomain stores domains as bare lowercase hosts so they can be
+// compared against source names like "kompas.com".
+func normalizeSourceDomain(domain null.String) null.String {
+	host := strings.ToLower(strings.TrimSpace(domain.String))
+	host = strings.TrimPrefix(host, "www.")
+	if host == "" {
+		return null.NewString("", false)
+	}
+
+	return null.StringFrom(host)
+}
+
+// sourceDomainFromUrl guesses the domain of a new source from an article url.
+func sourceDomainFromUrl(articleUrl string) null.String {
+	u, err := url.Parse(strings.TrimSpace(articleUrl))
+	if err != nil || u.Hostname() == "" {
+		return null.NewString("", false)
+	}
+
+	return normalizeSourceDomain(null.StringFrom(u.Hostname()))
+}
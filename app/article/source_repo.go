@@ -0,0 +1,254 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrSourceDoesNotExist = errors.New("Source does not exist")
+	ErrSourceNameExists   = errors.New("Source with that name exists")
+	ErrSourceInUse        = errors.New("Source still has articles, merge it into another source instead")
+)
+
+func findSources(ctx context.Context, tx pgx.Tx, search string, limit uint) (sources []*SourceViewModel, err error) {
+	q := `
+	SELECT
+	  s.*,
+	  (SELECT COUNT(*) FROM articles a WHERE a.source_id = s.id AND a.is_published IS TRUE AND a.deleted_at IS NULL) article_count
+	FROM sources s
+	WHERE s.name ILIKE '%' || $1 || '%' AND s.deleted_at IS NULL
+	ORDER BY s.name
+	LIMIT $2
+	`
+
+	sources = []*SourceViewModel{}
+	if err = pgxscan.Select(ctx, tx, &sources, q, search, limit); err != nil {
+		log.Err(err).Msg("Failed to find sources")
+		return
+	}
+
+	return sources, nil
+}
+
+func findSourceById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (source Source, err error) {
+	q := "SELECT * FROM sources WHERE id = $1 AND deleted_at IS NULL"
+
+	if err = pgxscan.Get(ctx, tx, &source, q, id); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return source, ErrSourceDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find source by id")
+		return source, err
+	}
+
+	return source, nil
+}
+
+func findSourcesByIds(ctx context.Context, tx pgx.Tx, ids []ulid.ULID) (sources []*Source, err error) {
+	q := "SELECT * FROM sources WHERE id = ANY($1) AND deleted_at IS NULL"
+
+	sources = []*Source{}
+	if err = pgxscan.Select(ctx, tx, &sources, q, ulidBytes(ids)); err != nil {
+		log.Err(err).Msg("Failed to find sources by ids")
+		return
+	}
+
+	if len(sources) != len(ids) {
+		return sources, ErrSourceDoesNotExist
+	}
+
+	return sources, nil
+}
+
+// findSourceByName matches the canonical name, a merged alias or the domain so
+// "Kompas", "kompas" and "kompas.com" all resolve to the same source.
+func findSourceByName(ctx context.Context, tx pgx.Tx, name string) (source Source, err error) {
+	q := `
+	SELECT * FROM sources
+	WHERE
+	  deleted_at IS NULL AND
+	  (LOWER(name) = $1 OR $1 = ANY(aliases) OR domain = $1)
+	ORDER BY (CASE WHEN LOWER(name) = $1 THEN 0 WHEN $1 = ANY(aliases) THEN 1 ELSE 2 END)
+	LIMIT 1
+	`
+
+	if err = pgxscan.Get(ctx, tx, &source, q, strings.ToLower(strings.TrimSpace(name))); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return source, ErrSourceDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find source by name")
+		return source, err
+	}
+
+	return source, nil
+}
+
+func saveSource(ctx context.Context, tx pgx.Tx, source Source) (Source, error) {
+	q := `
+	INSERT INTO sources(id, name, domain, logo_url, aliases, created_at) VALUES
+	($1, $2, $3, $4, $5, $6)
+	RETURNING *
+	`
+
+	var newSource Source
+	if err := pgxscan.Get(
+		ctx,
+		tx,
+		&newSource,
+		q,
+		source.Id,
+		source.Name,
+		source.Domain,
+		source.LogoUrl,
+		source.Aliases,
+		source.CreatedAt,
+	); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				return source, ErrSourceNameExists
+			}
+		}
+
+		log.Err(err).Msg("Failed to save source")
+		return source, err
+	}
+
+	return newSource, nil
+}
+
+// updateSourceById also renames the source on its articles, which keep the
+// name around for clients that only read the string.
+func updateSourceById(ctx context.Context, tx pgx.Tx, source Source) (updatedSource Source, err error) {
+	q := `
+	UPDATE sources
+	SET name = $1, domain = $2, logo_url = $3, aliases = $4, updated_at = $5
+	WHERE id = $6 AND deleted_at IS NULL
+	RETURNING *
+	`
+
+	if err = pgxscan.Get(
+		ctx,
+		tx,
+		&updatedSource,
+		q,
+		source.Name,
+		source.Domain,
+		source.LogoUrl,
+		source.Aliases,
+		source.UpdatedAt,
+		source.Id,
+	); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				return source, ErrSourceNameExists
+			}
+		}
+
+		if err.Error() == "scanning one: no rows in result set" {
+			return source, ErrSourceDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to update source")
+		return source, err
+	}
+
+	if _, err = tx.Exec(ctx, "UPDATE articles SET source = $1, updated_at = NOW(), version = version + 1 WHERE source_id = $2", updatedSource.Name, updatedSource.Id); err != nil {
+		log.Err(err).Msg("Failed to update source")
+		return source, err
+	}
+
+	return updatedSource, nil
+}
+
+// deleteSource is blocked while any article, trashed ones included, points at
+// the source since articles can't exist without one.
+func deleteSource(ctx context.Context, tx pgx.Tx, source Source) (err error) {
+	var inUse bool
+	if err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM articles WHERE source_id = $1)", source.Id).Scan(&inUse); err != nil {
+		log.Err(err).Msg("Failed to delete source")
+		return err
+	}
+	if inUse {
+		return ErrSourceInUse
+	}
+
+	if _, err = tx.Exec(ctx, "UPDATE sources SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", source.DeletedAt, source.Id); err != nil {
+		log.Err(err).Msg("Failed to delete source")
+		return err
+	}
+
+	return nil
+}
+
+// saveMergedSources moves every article of the merged sources to the target and
+// deletes the merged sources. The target must already carry their aliases.
+func saveMergedSources(ctx context.Context, tx pgx.Tx, target Source, merged []*Source) (Source, error) {
+	ids := make([]ulid.ULID, 0, len(merged))
+	for _, source := range merged {
+		ids = append(ids, source.Id)
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE sources SET deleted_at = NOW() WHERE id = ANY($1) AND deleted_at IS NULL", ulidBytes(ids)); err != nil {
+		log.Err(err).Msg("Failed to merge sources")
+		return target, err
+	}
+
+	if _, err := tx.Exec(
+		ctx,
+		"UPDATE articles SET source_id = $1, source = $2, updated_at = NOW(), version = version + 1 WHERE source_id = ANY($3)",
+		target.Id,
+		target.Name,
+		ulidBytes(ids),
+	); err != nil {
+		log.Err(err).Msg("Failed to merge sources")
+		return target, err
+	}
+
+	return updateSourceById(ctx, tx, target)
+}
+
+// resolveArticleSource links the article to the source its name resolves to,
+// creating one when nothing matches. The article takes the canonical name.
+func resolveArticleSource(ctx context.Context, tx pgx.Tx, article *Article) error {
+	source, err := findSourceByName(ctx, tx, article.Source)
+	if err == ErrSourceDoesNotExist {
+		newSource, errs := NewSource(article.Source, null.NewString("", false), null.NewString("", false))
+		if errs != nil {
+			return errs["name"]
+		}
+
+		// The domain is only a guess, leave it out when another source owns it
+		domain := sourceDomainFromUrl(article.OriginalUrl)
+		if domain.Valid && validateSourceDomain(domain.String) == nil {
+			_, err = findSourceByName(ctx, tx, domain.String)
+			if err == ErrSourceDoesNotExist {
+				newSource.Domain = domain
+			} else if err != nil {
+				return err
+			}
+		}
+
+		source, err = saveSource(ctx, tx, newSource)
+	}
+	if err != nil {
+		return err
+	}
+
+	article.SourceId = source.Id
+	article.Source = source.Name
+
+	return nil
+}
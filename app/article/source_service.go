@@ -0,0 +1,203 @@
+package article
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+func getSources(ctx context.Context, query string, limit uint) (sources []*SourceViewModel, err error) {
+	query = strings.TrimSpace(query)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get sources")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	sources, err = findSources(ctx, tx, query, limit)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get sources")
+		return
+	}
+
+	return sources, nil
+}
+
+func getSourceDetail(ctx context.Context, idStr string, req getArticlesReq) (detail SourceDetail, err error) {
+	id, err := validateSourceId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get source detail")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	source, err := findSourceById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get source detail")
+		return
+	}
+
+	req.SourceIds = []string{source.Id.String()}
+	articles, err := getArticles(ctx, req)
+	if err != nil {
+		return
+	}
+
+	return SourceDetail{Source: source, Articles: articles}, nil
+}
+
+func createSource(ctx context.Context, body createSourceReq) (source Source, errs map[string]error, err error) {
+	source, errs = NewSource(body.Name, body.Domain, body.LogoUrl)
+	if errs != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to create source")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	source, err = saveSource(ctx, tx, source)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to create source")
+		return
+	}
+
+	return source, nil, nil
+}
+
+func updateSource(ctx context.Context, idStr string, body updateSourceReq) (source Source, errs map[string]error, err error) {
+	id, err := validateSourceId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to update source")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	source, err = findSourceById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	if errs = source.Update(body.Name, body.Domain, body.LogoUrl); errs != nil {
+		return
+	}
+
+	source, err = updateSourceById(ctx, tx, source)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to update source")
+		return
+	}
+
+	return source, nil, nil
+}
+
+func removeSource(ctx context.Context, idStr string) (err error) {
+	id, err := validateSourceId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to remove source")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	source, err := findSourceById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	source.Delete()
+	if err = deleteSource(ctx, tx, source); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to remove source")
+		return
+	}
+
+	return nil
+}
+
+func mergeSources(ctx context.Context, idStr string, body mergeSourcesReq) (source Source, err error) {
+	id, err := validateSourceId(idStr)
+	if err != nil {
+		return
+	}
+
+	mergedIds, err := validateSourceMergeIds(id, body.SourceIds)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to merge sources")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	source, err = findSourceById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	merged, err := findSourcesByIds(ctx, tx, mergedIds)
+	if err != nil {
+		return
+	}
+
+	source.Merge(merged)
+	source, err = saveMergedSources(ctx, tx, source, merged)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to merge sources")
+		return
+	}
+
+	return source, nil
+}
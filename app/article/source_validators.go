@@ -0,0 +1,80 @@
+package article
+
+import (
+	"strings"
+
+	"github.com/jellydator/validation"
+	"github.com/jellydator/validation/is"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrInvalidSourceId      = validation.NewError("article:invalid_source_id", "Invalid source id")
+	ErrSourceNameEmpty      = validation.NewError("article:source_name_empty", "Source name can't be empty")
+	ErrSourceNameTooLong    = validation.NewError("article:source_name_too_long", "Source name can't be longer than 255 characters")
+	ErrInvalidSourceDomain  = validation.NewError("article:invalid_source_domain", "Invalid source domain")
+	ErrInvalidSourceLogoUrl = validation.NewError("article:invalid_source_logo_url", "Invalid source logo url")
+	ErrSourceMergeEmpty     = validation.NewError("article:source_merge_empty", "At least one source to merge is required")
+	ErrSourceMergeIntoSelf  = validation.NewError("article:source_merge_into_self", "A source can't be merged into itself")
+)
+
+func validateSourceId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidSourceId
+	}
+
+	return id, nil
+}
+
+func validateSourceName(name string) error {
+	name = strings.TrimSpace(name)
+	return validation.Validate(
+		&name,
+		validation.Required.ErrorObject(ErrSourceNameEmpty),
+		validation.Length(1, 255).ErrorObject(ErrSourceNameTooLong),
+	)
+}
+
+func validateSourceDomain(domain string) error {
+	domain = strings.TrimSpace(domain)
+	return validation.Validate(
+		&domain,
+		validation.When(
+			!validation.IsEmpty(domain),
+			is.Domain.ErrorObject(ErrInvalidSourceDomain),
+		),
+	)
+}
+
+func validateSourceLogoUrl(url string) error {
+	url = strings.TrimSpace(url)
+	return validation.Validate(
+		&url,
+		validation.When(
+			!validation.IsEmpty(url),
+			is.URL.ErrorObject(ErrInvalidSourceLogoUrl),
+		),
+	)
+}
+
+// validateSourceMergeIds parses the ids of the sources merged into target.
+func validateSourceMergeIds(target ulid.ULID, idStrs []string) (ids []ulid.ULID, err error) {
+	if len(idStrs) == 0 {
+		return nil, ErrSourceMergeEmpty
+	}
+
+	for _, idStr := range idStrs {
+		id, err := validateSourceId(idStr)
+		if err != nil {
+			return nil, err
+		}
+		if id.Compare(target) == 0 {
+			return nil, ErrSourceMergeIntoSelf
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
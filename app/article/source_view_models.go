@@ -0,0 +1,11 @@
+package article
+
+type SourceViewModel struct {
+	Source
+	ArticleCount uint `json:"article_count"`
+}
+
+type SourceDetail struct {
+	Source
+	Articles Articles `json:"articles"`
+}
@@ -0,0 +1,535 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrTrashedArticleDoesNotExist         = errors.New("Article is not in the trash")
+	ErrTrashedArticleTextDoesNotExist     = errors.New("Article text is not in the trash")
+	ErrTrashedArticleCategoryDoesNotExist = errors.New("Article category is not in the trash")
+	ErrTrashedCollectionDoesNotExist      = errors.New("Collection is not in the trash")
+	ErrTrashedArticleCategoryGone         = errors.New("Article category no longer exists, restore it from the trash first")
+)
+
+func findTrashedArticles(ctx context.Context, tx pgx.Tx, limit uint) (articles []*TrashedArticle, err error) {
+	q := `
+	SELECT a.*, ac.name category_name
+	FROM articles a
+	INNER JOIN article_categories ac
+	ON a.category_id = ac.id
+	WHERE a.deleted_at IS NOT NULL
+	ORDER BY a.deleted_at DESC
+	LIMIT $1
+	`
+
+	articles = []*TrashedArticle{}
+	if err = pgxscan.Select(ctx, tx, &articles, q, limit); err != nil {
+		log.Err(err).Msg("Failed to find trashed articles")
+		return
+	}
+
+	return articles, nil
+}
+
+func findTrashedArticleTexts(ctx context.Context, tx pgx.Tx, limit uint) (texts []*TrashedArticleText, err error) {
+	q := `
+	SELECT at.*, a.title article_title
+	FROM article_texts at
+	INNER JOIN articles a
+	ON a.id = at.article_id
+	WHERE at.deleted_at IS NOT NULL
+	ORDER BY at.deleted_at DESC
+	LIMIT $1
+	`
+
+	texts = []*TrashedArticleText{}
+	if err = pgxscan.Select(ctx, tx, &texts, q, limit); err != nil {
+		log.Err(err).Msg("Failed to find trashed article texts")
+		return
+	}
+
+	return texts, nil
+}
+
+func findTrashedArticleCategories(ctx context.Context, tx pgx.Tx, limit uint) (categories []*ArticleCategory, err error) {
+	q := "SELECT * FROM article_categories WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT $1"
+
+	categories = []*ArticleCategory{}
+	if err = pgxscan.Select(ctx, tx, &categories, q, limit); err != nil {
+		log.Err(err).Msg("Failed to find trashed article categories")
+		return
+	}
+
+	return categories, nil
+}
+
+func findTrashedCollections(ctx context.Context, tx pgx.Tx, limit uint) (collections []*Collection, err error) {
+	q := "SELECT * FROM collections WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT $1"
+
+	collections = []*Collection{}
+	if err = pgxscan.Select(ctx, tx, &collections, q, limit); err != nil {
+		log.Err(err).Msg("Failed to find trashed collections")
+		return
+	}
+
+	return collections, nil
+}
+
+func findTrashedArticleById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (article Article, err error) {
+	q := "SELECT * FROM articles WHERE id = $1 AND deleted_at IS NOT NULL"
+
+	if err = pgxscan.Get(ctx, tx, &article, q, id); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return article, ErrTrashedArticleDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find trashed article by id")
+		return article, err
+	}
+
+	return article, nil
+}
+
+func findTrashedArticleTextById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (text ArticleText, err error) {
+	q := "SELECT * FROM article_texts WHERE id = $1 AND deleted_at IS NOT NULL"
+
+	if err = pgxscan.Get(ctx, tx, &text, q, id); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return text, ErrTrashedArticleTextDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find trashed article text by id")
+		return text, err
+	}
+
+	return text, nil
+}
+
+func findTrashedArticleCategoryById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (category ArticleCategory, err error) {
+	q := "SELECT * FROM article_categories WHERE id = $1 AND deleted_at IS NOT NULL"
+
+	if err = pgxscan.Get(ctx, tx, &category, q, id); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return category, ErrTrashedArticleCategoryDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find trashed article category by id")
+		return category, err
+	}
+
+	return category, nil
+}
+
+func findTrashedCollectionById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (collection Collection, err error) {
+	q := "SELECT * FROM collections WHERE id = $1 AND deleted_at IS NOT NULL"
+
+	if err = pgxscan.Get(ctx, tx, &collection, q, id); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return collection, ErrTrashedCollectionDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find trashed collection by id")
+		return collection, err
+	}
+
+	return collection, nil
+}
+
+func restoreArticleById(ctx context.Context, tx pgx.Tx, article Article) (restoredArticle Article, err error) {
+	if _, err = findArticleCategoryById(ctx, tx, article.CategoryId); err != nil {
+		if err == ErrArticleCategoryDoesNotExist {
+			return article, ErrTrashedArticleCategoryGone
+		}
+		return article, err
+	}
+
+	q := `
+	UPDATE articles
+	SET deleted_at = NULL, updated_at = $2
+	WHERE id = $1 AND deleted_at IS NOT NULL
+	RETURNING *
+	`
+
+	if err = pgxscan.Get(ctx, tx, &restoredArticle, q, article.Id, article.UpdatedAt); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return article, ErrTrashedArticleDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to restore article")
+		return article, err
+	}
+
+	return restoredArticle, nil
+}
+
+// Only texts that were trashed together with the article are restored, and
+// only for difficulties that aren't taken by another live text.
+func restoreArticleTextsByArticle(ctx context.Context, tx pgx.Tx, article Article, deletedAt null.Time) (err error) {
+	q := `
+	UPDATE article_texts at
+	SET deleted_at = NULL, updated_at = $3
+	WHERE
+	  at.article_id = $1 AND
+	  at.deleted_at = $2 AND
+	  NOT EXISTS (
+	    SELECT 1
+	    FROM article_texts e
+	    WHERE
+	      e.article_id = at.article_id AND
+	      e.difficulty = at.difficulty AND
+	      e.deleted_at IS NULL
+	  )
+	`
+
+	if _, err = tx.Exec(ctx, q, article.Id, deletedAt, article.UpdatedAt); err != nil {
+		log.Err(err).Msg("Failed to restore article texts")
+		return err
+	}
+
+	return nil
+}
+
+// restoreArticleTextById publishes the text too, restoring a text moderation
+// took down is an admin overriding the rejection.
+func restoreArticleTextById(ctx context.Context, tx pgx.Tx, text ArticleText) (restoredText ArticleText, err error) {
+	if _, err = findArticleById(ctx, tx, text.ArticleId); err != nil {
+		return text, err
+	}
+
+	q := `
+	UPDATE article_texts
+	SET deleted_at = NULL, updated_at = $2, moderation_status = $3
+	WHERE id = $1 AND deleted_at IS NOT NULL
+	RETURNING *
+	`
+
+	if err = pgxscan.Get(ctx, tx, &restoredText, q, text.Id, text.UpdatedAt, TEXT_APPROVED); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				return text, ErrArticleTextDifficultyExist
+			}
+		}
+
+		if err.Error() == "scanning one: no rows in result set" {
+			return text, ErrTrashedArticleTextDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to restore article text")
+		return text, err
+	}
+
+	return restoredText, nil
+}
+
+func restoreArticleCategoryById(ctx context.Context, tx pgx.Tx, category ArticleCategory) (restoredCategory ArticleCategory, err error) {
+	q := `
+	UPDATE article_categories
+	SET deleted_at = NULL, updated_at = $2
+	WHERE id = $1 AND deleted_at IS NOT NULL
+	RETURNING *
+	`
+
+	if err = pgxscan.Get(ctx, tx, &restoredCategory, q, category.Id, category.UpdatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				return category, ErrArticleCategoryNameExists
+			}
+		}
+
+		if err.Error() == "scanning one: no rows in result set" {
+			return category, ErrTrashedArticleCategoryDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to restore article category")
+		return category, err
+	}
+
+	return restoredCategory, nil
+}
+
+func restoreCollectionById(ctx context.Context, tx pgx.Tx, collection Collection) (restoredCollection Collection, err error) {
+	q := `
+	UPDATE collections
+	SET deleted_at = NULL, updated_at = $2
+	WHERE id = $1 AND deleted_at IS NOT NULL
+	RETURNING *
+	`
+
+	if err = pgxscan.Get(ctx, tx, &restoredCollection, q, collection.Id, collection.UpdatedAt); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return collection, ErrTrashedCollectionDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to restore collection")
+		return collection, err
+	}
+
+	return restoredCollection, nil
+}
+
+func purgeArticleById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (err error) {
+	if _, err = purgeArticleReferences(ctx, tx, "$1", id); err != nil {
+		return err
+	}
+	if _, err = detachArticleTextFlags(ctx, tx, "SELECT id FROM article_texts WHERE article_id = $1", id); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM collection_articles WHERE article_id = $1", id); err != nil {
+		log.Err(err).Msg("Failed to purge article")
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM series_progress WHERE article_id = $1", id); err != nil {
+		log.Err(err).Msg("Failed to purge article")
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM series_articles WHERE article_id = $1", id); err != nil {
+		log.Err(err).Msg("Failed to purge article")
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM article_texts WHERE article_id = $1", id); err != nil {
+		log.Err(err).Msg("Failed to purge article")
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, "DELETE FROM articles WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge article")
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTrashedArticleDoesNotExist
+	}
+
+	return nil
+}
+
+// purgeArticleReferences cleans up what other modules keep of the articles
+// articleIds selects, there are no foreign keys to do it. Chats, annotations
+// and preview tokens are meaningless without the article and go with it, while
+// notebook entries and assistant history belong to their users and only lose
+// the link to it.
+func purgeArticleReferences(ctx context.Context, tx pgx.Tx, articleIds string, arg any) (purged PurgedTrash, err error) {
+	q := "DELETE FROM article_preview_tokens WHERE article_id IN (" + articleIds + ")"
+	tag, err := tx.Exec(ctx, q, arg)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge article references")
+		return
+	}
+	purged.PreviewTokens = tag.RowsAffected()
+
+	q = "DELETE FROM assistant_chat_messages WHERE chat_id IN (SELECT id FROM assistant_chats WHERE article_id IN (" + articleIds + "))"
+	if _, err = tx.Exec(ctx, q, arg); err != nil {
+		log.Err(err).Msg("Failed to purge article references")
+		return
+	}
+
+	q = "DELETE FROM assistant_chats WHERE article_id IN (" + articleIds + ")"
+	if tag, err = tx.Exec(ctx, q, arg); err != nil {
+		log.Err(err).Msg("Failed to purge article references")
+		return
+	}
+	purged.Chats = tag.RowsAffected()
+
+	q = "DELETE FROM assistant_annotations WHERE article_id IN (" + articleIds + ")"
+	if tag, err = tx.Exec(ctx, q, arg); err != nil {
+		log.Err(err).Msg("Failed to purge article references")
+		return
+	}
+	purged.Annotations = tag.RowsAffected()
+
+	for _, table := range []string{"notebook_cards", "notebook_notes", "assistant_history"} {
+		q = "UPDATE " + table + " SET article_id = NULL WHERE article_id IN (" + articleIds + ")"
+		if tag, err = tx.Exec(ctx, q, arg); err != nil {
+			log.Err(err).Str("table", table).Msg("Failed to purge article references")
+			return
+		}
+		purged.Detached += tag.RowsAffected()
+	}
+
+	return purged, nil
+}
+
+// detachArticleTextFlags keeps the moderation audit trail of purged texts, the
+// flags hold a copy of the text they were raised on.
+func detachArticleTextFlags(ctx context.Context, tx pgx.Tx, textIds string, arg any) (detached int64, err error) {
+	q := "UPDATE moderation_flags SET subject_id = NULL WHERE subject_kind = 'ARTICLE_TEXT' AND subject_id IN (" + textIds + ")"
+
+	tag, err := tx.Exec(ctx, q, arg)
+	if err != nil {
+		log.Err(err).Msg("Failed to detach moderation flags")
+		return
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func purgeArticleTextById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (err error) {
+	if _, err = detachArticleTextFlags(ctx, tx, "SELECT id FROM article_texts WHERE id = $1 AND deleted_at IS NOT NULL", id); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, "DELETE FROM article_texts WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge article text")
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTrashedArticleTextDoesNotExist
+	}
+
+	return nil
+}
+
+func purgeArticleCategoryById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (err error) {
+	if _, err = findTrashedArticleCategoryById(ctx, tx, id); err != nil {
+		return err
+	}
+
+	var inUse bool
+	if err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM articles WHERE category_id = $1)", id).Scan(&inUse); err != nil {
+		log.Err(err).Msg("Failed to purge article category")
+		return err
+	}
+	if inUse {
+		return ErrArticleCategoryInUse
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM users_interests WHERE category_id = $1", id); err != nil {
+		log.Err(err).Msg("Failed to purge article category")
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM article_categories WHERE id = $1 AND deleted_at IS NOT NULL", id); err != nil {
+		log.Err(err).Msg("Failed to purge article category")
+		return err
+	}
+
+	return nil
+}
+
+func purgeCollectionById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (err error) {
+	if _, err = tx.Exec(ctx, "DELETE FROM collection_articles WHERE collection_id = $1", id); err != nil {
+		log.Err(err).Msg("Failed to purge collection")
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, "DELETE FROM collections WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge collection")
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTrashedCollectionDoesNotExist
+	}
+
+	return nil
+}
+
+func purgeTrashDeletedBefore(ctx context.Context, tx pgx.Tx, cutoff time.Time) (purged PurgedTrash, err error) {
+	purged, err = purgeArticleReferences(ctx, tx, "SELECT id FROM articles WHERE deleted_at < $1", cutoff)
+	if err != nil {
+		return
+	}
+
+	detached, err := detachArticleTextFlags(ctx, tx, "SELECT id FROM article_texts WHERE deleted_at < $1 OR article_id IN (SELECT id FROM articles WHERE deleted_at < $1)", cutoff)
+	if err != nil {
+		return
+	}
+	purged.Detached += detached
+
+	q := `
+	DELETE FROM collection_articles
+	WHERE
+	  deleted_at < $1 OR
+	  article_id IN (SELECT id FROM articles WHERE deleted_at < $1) OR
+	  collection_id IN (SELECT id FROM collections WHERE deleted_at < $1)
+	`
+	if _, err = tx.Exec(ctx, q, cutoff); err != nil {
+		log.Err(err).Msg("Failed to purge trash")
+		return
+	}
+
+	q = "DELETE FROM series_progress WHERE article_id IN (SELECT id FROM articles WHERE deleted_at < $1)"
+	if _, err = tx.Exec(ctx, q, cutoff); err != nil {
+		log.Err(err).Msg("Failed to purge trash")
+		return
+	}
+
+	q = "DELETE FROM series_articles WHERE article_id IN (SELECT id FROM articles WHERE deleted_at < $1)"
+	if _, err = tx.Exec(ctx, q, cutoff); err != nil {
+		log.Err(err).Msg("Failed to purge trash")
+		return
+	}
+
+	q = `
+	DELETE FROM article_texts
+	WHERE
+	  deleted_at < $1 OR
+	  article_id IN (SELECT id FROM articles WHERE deleted_at < $1)
+	`
+	tag, err := tx.Exec(ctx, q, cutoff)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge trash")
+		return
+	}
+	purged.Texts = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, "DELETE FROM articles WHERE deleted_at < $1", cutoff)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge trash")
+		return
+	}
+	purged.Articles = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, "DELETE FROM collections WHERE deleted_at < $1", cutoff)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge trash")
+		return
+	}
+	purged.Collections = tag.RowsAffected()
+
+	q = `
+	DELETE FROM users_interests ui
+	USING article_categories ac
+	WHERE
+	  ui.category_id = ac.id AND
+	  ac.deleted_at < $1 AND
+	  NOT EXISTS (SELECT 1 FROM articles a WHERE a.category_id = ac.id)
+	`
+	if _, err = tx.Exec(ctx, q, cutoff); err != nil {
+		log.Err(err).Msg("Failed to purge trash")
+		return
+	}
+
+	// Categories still referenced by any article are kept until those articles are purged
+	q = `
+	DELETE FROM article_categories ac
+	WHERE
+	  ac.deleted_at < $1 AND
+	  NOT EXISTS (SELECT 1 FROM articles a WHERE a.category_id = ac.id)
+	`
+	tag, err = tx.Exec(ctx, q, cutoff)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge trash")
+		return
+	}
+	purged.Categories = tag.RowsAffected()
+
+	return purged, nil
+}
@@ -0,0 +1,353 @@
+package article
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const trashPurgeInterval = time.Hour
+
+func getTrash(ctx context.Context, limit uint) (trash Trash, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get trash")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	articles, err := findTrashedArticles(ctx, tx, limit)
+	if err != nil {
+		return
+	}
+
+	texts, err := findTrashedArticleTexts(ctx, tx, limit)
+	if err != nil {
+		return
+	}
+
+	categories, err := findTrashedArticleCategories(ctx, tx, limit)
+	if err != nil {
+		return
+	}
+
+	collections, err := findTrashedCollections(ctx, tx, limit)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get trash")
+		return
+	}
+
+	return Trash{
+		RetentionDays: uint(trashRetention / (24 * time.Hour)),
+		Articles:      articles,
+		Texts:         texts,
+		Categories:    categories,
+		Collections:   collections,
+	}, nil
+}
+
+func restoreArticle(ctx context.Context, idStr string) (articleDetail ArticleDetail, err error) {
+	id, err := validateArticleId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to restore article")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	article, err := findTrashedArticleById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	deletedAt := article.DeletedAt
+	article.Restore()
+
+	article, err = restoreArticleById(ctx, tx, article)
+	if err != nil {
+		return
+	}
+
+	if err = restoreArticleTextsByArticle(ctx, tx, article, deletedAt); err != nil {
+		return
+	}
+
+	articleDetail, err = findArticleDetail(ctx, tx, article, true)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to restore article")
+		return
+	}
+
+	return articleDetail, nil
+}
+
+func restoreArticleText(ctx context.Context, idStr string) (text ArticleText, err error) {
+	id, err := validateArticleTextId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to restore article text")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	text, err = findTrashedArticleTextById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	text.Restore()
+
+	text, err = restoreArticleTextById(ctx, tx, text)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to restore article text")
+		return
+	}
+
+	return text, nil
+}
+
+func restoreArticleCategory(ctx context.Context, idStr string) (category ArticleCategory, err error) {
+	id, err := validateArticleCategoryId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to restore article category")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	category, err = findTrashedArticleCategoryById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	category.Restore()
+
+	category, err = restoreArticleCategoryById(ctx, tx, category)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to restore article category")
+		return
+	}
+
+	return category, nil
+}
+
+func restoreCollection(ctx context.Context, idStr string) (collection Collection, err error) {
+	id, err := validateCollectionId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to restore collection")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	collection, err = findTrashedCollectionById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	collection.Restore()
+
+	collection, err = restoreCollectionById(ctx, tx, collection)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to restore collection")
+		return
+	}
+
+	return collection, nil
+}
+
+func purgeArticle(ctx context.Context, idStr string) (err error) {
+	id, err := validateArticleId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge article")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = purgeArticleById(ctx, tx, id); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to purge article")
+		return
+	}
+
+	return nil
+}
+
+func purgeArticleText(ctx context.Context, idStr string) (err error) {
+	id, err := validateArticleTextId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge article text")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = purgeArticleTextById(ctx, tx, id); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to purge article text")
+		return
+	}
+
+	return nil
+}
+
+func purgeArticleCategory(ctx context.Context, idStr string) (err error) {
+	id, err := validateArticleCategoryId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge article category")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = purgeArticleCategoryById(ctx, tx, id); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to purge article category")
+		return
+	}
+
+	return nil
+}
+
+func purgeCollection(ctx context.Context, idStr string) (err error) {
+	id, err := validateCollectionId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge collection")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = purgeCollectionById(ctx, tx, id); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to purge collection")
+		return
+	}
+
+	return nil
+}
+
+func purgeExpiredTrash(ctx context.Context) (purged PurgedTrash, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge expired trash")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	purged, err = purgeTrashDeletedBefore(ctx, tx, time.Now().Add(-trashRetention))
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to purge expired trash")
+		return
+	}
+
+	return purged, nil
+}
+
+// StartTrashPurgeJob blocks, so it should be run in its own goroutine.
+func StartTrashPurgeJob(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := purgeExpiredTrash(ctx)
+		if err == nil {
+			log.Info().Fields(map[string]any{
+				"articles":       purged.Articles,
+				"texts":          purged.Texts,
+				"categories":     purged.Categories,
+				"collections":    purged.Collections,
+				"preview_tokens": purged.PreviewTokens,
+				"chats":          purged.Chats,
+				"annotations":    purged.Annotations,
+				"detached":       purged.Detached,
+			}).Msg("Purged expired trash")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package article
+
+type TrashedArticle struct {
+	Article
+	CategoryName string `json:"category_name"`
+}
+
+type TrashedArticleText struct {
+	ArticleText
+	ArticleTitle string `json:"article_title"`
+}
+
+type Trash struct {
+	RetentionDays uint                  `json:"retention_days"`
+	Articles      []*TrashedArticle     `json:"articles"`
+	Texts         []*TrashedArticleText `json:"texts"`
+	Categories    []*ArticleCategory    `json:"categories"`
+	Collections   []*Collection         `json:"collections"`
+}
+
+type PurgedTrash struct {
+	Articles      int64 `json:"articles"`
+	Texts         int64 `json:"texts"`
+	Categories    int64 `json:"categories"`
+	Collections   int64 `json:"collections"`
+	PreviewTokens int64 `json:"preview_tokens"`
+	Chats         int64 `json:"chats"`
+	Annotations   int64 `json:"annotations"`
+	// Detached counts notebook entries, assistant history and moderation
+	// flags that outlive the article they pointed at
+	Detached int64 `json:"detached"`
+}
@@ -0,0 +1,115 @@
+package assistant
+
+import (
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// ResultKind tells explanations and simplifications apart wherever both are
+// kept
+type ResultKind string
+
+const (
+	EXPLANATION    ResultKind = "EXPLANATION"
+	SIMPLIFICATION ResultKind = "SIMPLIFICATION"
+)
+
+// The paragraph sent with a selection is cut around it when it's longer
+const anchorParagraphLength = 2000
+
+// Annotation is an explanation or simplification of a selection in an article
+// text, kept so the reader can find it again next to the text. Offsets count
+// characters (Unicode code points) of the article text content, the end is
+// exclusive.
+type Annotation struct {
+	Id            ulid.ULID  `json:"id"`
+	UserId        ulid.ULID  `json:"user_id"`
+	ArticleId     ulid.ULID  `json:"article_id"`
+	Difficulty    string     `json:"difficulty"`
+	Kind          ResultKind `json:"kind"`
+	StartOffset   int        `json:"start_offset"`
+	EndOffset     int        `json:"end_offset"`
+	Text          string     `json:"text"`
+	Result        string     `json:"result"`
+	PromptVersion string     `json:"prompt_version"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// anchoredText is a selection resolved against the article text it points
+// at, with the paragraph around it and the article title as context.
+type anchoredText struct {
+	ArticleId  ulid.ULID
+	Difficulty string
+	Start      int
+	End        int
+	Title      string
+	Paragraph  string
+	Text       string
+}
+
+func NewAnnotation(userId ulid.ULID, kind ResultKind, anchor anchoredText, result, promptVersion string) Annotation {
+	return Annotation{
+		Id:            ulid.Make(),
+		UserId:        userId,
+		ArticleId:     anchor.ArticleId,
+		Difficulty:    anchor.Difficulty,
+		Kind:          kind,
+		StartOffset:   anchor.Start,
+		EndOffset:     anchor.End,
+		Text:          anchor.Text,
+		Result:        result,
+		PromptVersion: promptVersion,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// newAnchoredText cuts the selection out of the article text along with the
+// paragraphs it spans.
+func newAnchoredText(articleId ulid.ULID, difficulty string, start, end int, article articleText) (anchor anchoredText, err error) {
+	content := []rune(article.Content)
+	if start < 0 || end <= start || end > len(content) {
+		return anchor, ErrInvalidAnchorRange
+	}
+
+	paragraphStart := start
+	for paragraphStart > 0 && content[paragraphStart-1] != '\n' {
+		paragraphStart--
+	}
+
+	paragraphEnd := end
+	for paragraphEnd < len(content) && content[paragraphEnd] != '\n' {
+		paragraphEnd++
+	}
+
+	// Keep the selection in the middle of what's left of a long paragraph
+	if paragraphEnd-paragraphStart > anchorParagraphLength {
+		margin := (anchorParagraphLength - (end - start)) / 2
+		if margin < 0 {
+			margin = 0
+		}
+		if start-margin > paragraphStart {
+			paragraphStart = start - margin
+		}
+		if end+margin < paragraphEnd {
+			paragraphEnd = end + margin
+		}
+	}
+
+	return anchoredText{
+		ArticleId:  articleId,
+		Difficulty: difficulty,
+		Start:      start,
+		End:        end,
+		Title:      article.Title,
+		Paragraph:  strings.TrimSpace(string(content[paragraphStart:paragraphEnd])),
+		Text:       strings.TrimSpace(string(content[start:end])),
+	}, nil
+}
+
+// cacheInput is what the response cache keys an anchored answer by, the same
+// words mean something else in another paragraph.
+func (a anchoredText) cacheInput() string {
+	return strings.Join([]string{a.Text, a.Title, a.Paragraph}, "\n")
+}
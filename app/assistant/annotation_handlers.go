@@ -0,0 +1,88 @@
+package assistant
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+)
+
+func getAnnotationsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	query := r.URL.Query()
+	limitStr := query.Get("limit")
+
+	// Don't throw error to client just because of misinputs
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = defaultAnnotationsLimit
+	}
+
+	annotations, err := getAnnotations(ctx, user, query.Get("article_id"), query.Get("difficulty"), query.Get("kind"), uint(limit))
+	if err != nil {
+		writeAnnotationError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, annotations)
+}
+
+func getAnnotationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	annotation, err := getAnnotation(ctx, user, chi.URLParam(r, "id"))
+	if err != nil {
+		writeAnnotationError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, annotation)
+}
+
+func deleteAnnotationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	annotation, err := removeAnnotation(ctx, user, chi.URLParam(r, "id"))
+	if err != nil {
+		writeAnnotationError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, annotation)
+}
+
+func writeAnnotationError(w http.ResponseWriter, err error) {
+	switch {
+	case
+		errors.As(err, &ErrInvalidAnnotationId),
+		errors.As(err, &ErrInvalidAnchorArticleId),
+		errors.As(err, &ErrInvalidResultKind):
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+	case errors.Is(err, ErrAnnotationDoesNotExist):
+		app.WriteHttpError(w, http.StatusNotFound, err)
+	default:
+		app.WriteHttpInternalServerError(w)
+	}
+}
@@ -0,0 +1,81 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrAnnotationDoesNotExist = errors.New("Annotation does not exist")
+
+type annotationFilter struct {
+	ArticleId  *ulid.ULID
+	Difficulty string
+	Kind       ResultKind
+	Limit      uint
+}
+
+// findAnnotationsByUserId lists annotations in reading order when filtered by
+// article text, newest first otherwise.
+func findAnnotationsByUserId(ctx context.Context, tx pgx.Tx, userId ulid.ULID, filter annotationFilter) (annotations []*Annotation, err error) {
+	q := `
+	SELECT * FROM assistant_annotations
+	WHERE user_id = $1
+	AND ($2::BYTEA IS NULL OR article_id = $2)
+	AND ($3 = '' OR difficulty = $3)
+	AND ($4 = '' OR kind = $4)
+	ORDER BY
+		CASE WHEN $2::BYTEA IS NULL THEN NULL ELSE start_offset END ASC,
+		id DESC
+	LIMIT $5
+	`
+
+	if err = pgxscan.Select(ctx, tx, &annotations, q, userId, filter.ArticleId, filter.Difficulty, filter.Kind, filter.Limit); err != nil {
+		log.Err(err).Msg("Failed to find annotations by user id")
+		return
+	}
+
+	return annotations, nil
+}
+
+func findAnnotationByIdAndUserId(ctx context.Context, tx pgx.Tx, id, userId ulid.ULID) (annotation Annotation, err error) {
+	q := "SELECT * FROM assistant_annotations WHERE id = $1 AND user_id = $2"
+
+	if err = pgxscan.Get(ctx, tx, &annotation, q, id, userId); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return annotation, ErrAnnotationDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find annotation")
+		return
+	}
+
+	return annotation, nil
+}
+
+func saveAnnotation(ctx context.Context, tx pgx.Tx, a Annotation) (err error) {
+	q := `
+	INSERT INTO assistant_annotations(id, user_id, article_id, difficulty, kind, start_offset, end_offset, text, result, prompt_version, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	if _, err = tx.Exec(ctx, q, a.Id, a.UserId, a.ArticleId, a.Difficulty, a.Kind, a.StartOffset, a.EndOffset, a.Text, a.Result, a.PromptVersion, a.CreatedAt); err != nil {
+		log.Err(err).Msg("Failed to save annotation")
+		return
+	}
+
+	return nil
+}
+
+func deleteAnnotation(ctx context.Context, tx pgx.Tx, a Annotation) (err error) {
+	if _, err = tx.Exec(ctx, "DELETE FROM assistant_annotations WHERE id = $1", a.Id); err != nil {
+		log.Err(err).Msg("Failed to delete annotation")
+		return
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package assistant
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultAnnotationsLimit = 100
+	maxAnnotationsLimit     = 500
+)
+
+// resolveSelection returns the text to work on, either as sent or cut out of
+// the article text the anchor points at.
+func resolveSelection(ctx context.Context, text string, a TextAnchor) (selection string, anchor *anchoredText, err error) {
+	if !a.IsSet() {
+		return text, nil, nil
+	}
+
+	articleId, err := validateAnchorArticleId(a.ArticleId)
+	if err != nil {
+		return
+	}
+
+	difficulty := strings.TrimSpace(a.Difficulty)
+	if err = validateAnchorDifficulty(difficulty); err != nil {
+		return
+	}
+
+	if err = validateAnchorOffsets(a.Start, a.End); err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to resolve text selection")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	article, err := findPublishedArticleText(ctx, tx, articleId, difficulty)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to resolve text selection")
+		return
+	}
+
+	resolved, err := newAnchoredText(articleId, difficulty, int(a.Start.Int64), int(a.End.Int64), article)
+	if err != nil {
+		return
+	}
+
+	return resolved.Text, &resolved, nil
+}
+
+func annotate(ctx context.Context, annotation Annotation) (a Annotation, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to annotate article text")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = saveAnnotation(ctx, tx, annotation); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to annotate article text")
+		return
+	}
+
+	return annotation, nil
+}
+
+func getAnnotations(ctx context.Context, user auth.User, articleIdStr, difficulty, kindStr string, limit uint) (annotations []*Annotation, err error) {
+	filter := annotationFilter{Difficulty: strings.TrimSpace(difficulty), Limit: limit}
+
+	if articleIdStr != "" {
+		var articleId ulid.ULID
+		if articleId, err = validateAnchorArticleId(articleIdStr); err != nil {
+			return
+		}
+
+		filter.ArticleId = &articleId
+	}
+
+	if kindStr != "" {
+		if filter.Kind, err = validateResultKind(kindStr); err != nil {
+			return
+		}
+	}
+
+	if filter.Limit == 0 || filter.Limit > maxAnnotationsLimit {
+		filter.Limit = defaultAnnotationsLimit
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get annotations")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	annotations, err = findAnnotationsByUserId(ctx, tx, user.Id, filter)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get annotations")
+		return
+	}
+
+	return annotations, nil
+}
+
+func getAnnotation(ctx context.Context, user auth.User, idStr string) (annotation Annotation, err error) {
+	id, err := validateAnnotationId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get annotation")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	annotation, err = findAnnotationByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get annotation")
+		return
+	}
+
+	return annotation, nil
+}
+
+func removeAnnotation(ctx context.Context, user auth.User, idStr string) (annotation Annotation, err error) {
+	id, err := validateAnnotationId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to remove annotation")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	annotation, err = findAnnotationByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	if err = deleteAnnotation(ctx, tx, annotation); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to remove annotation")
+		return
+	}
+
+	return annotation, nil
+}
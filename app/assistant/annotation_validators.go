@@ -0,0 +1,68 @@
+package assistant
+
+import (
+	"strings"
+
+	"github.com/jellydator/validation"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrInvalidAnnotationId     = validation.NewError("assistant:invalid_annotation_id", "Invalid annotation id")
+	ErrInvalidAnchorArticleId  = validation.NewError("assistant:invalid_article_id", "Invalid article id")
+	ErrInvalidAnchorDifficulty = validation.NewError("assistant:invalid_difficulty", "Invalid text difficulty")
+	ErrAnchorOffsetsRequired   = validation.NewError("assistant:offsets_required", "Start and end offsets are required with an article id")
+	ErrInvalidAnchorRange      = validation.NewError("assistant:invalid_offsets", "Offsets must select text inside the article text")
+	ErrInvalidResultKind       = validation.NewError("assistant:invalid_kind", "Kind must be EXPLANATION or SIMPLIFICATION")
+)
+
+func validateAnnotationId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidAnnotationId
+	}
+
+	return id, nil
+}
+
+func validateAnchorArticleId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidAnchorArticleId
+	}
+
+	return id, nil
+}
+
+func validateAnchorDifficulty(difficulty string) error {
+	difficulty = strings.TrimSpace(difficulty)
+	return validation.Validate(
+		&difficulty,
+		validation.Required.ErrorObject(ErrInvalidAnchorDifficulty),
+		validation.Length(1, 25).ErrorObject(ErrInvalidAnchorDifficulty),
+	)
+}
+
+// validateAnchorOffsets only checks the offsets against each other, whether
+// they fit the article text is checked once it's loaded.
+func validateAnchorOffsets(start, end null.Int) error {
+	if !start.Valid || !end.Valid {
+		return ErrAnchorOffsetsRequired
+	}
+	if start.Int64 < 0 || end.Int64 <= start.Int64 {
+		return ErrInvalidAnchorRange
+	}
+
+	return nil
+}
+
+func validateResultKind(kindStr string) (kind ResultKind, err error) {
+	kind = ResultKind(strings.ToUpper(strings.TrimSpace(kindStr)))
+	switch kind {
+	case EXPLANATION, SIMPLIFICATION:
+		return kind, nil
+	default:
+		return kind, ErrInvalidResultKind
+	}
+}
@@ -0,0 +1,41 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrArticleTextDoesNotExist = errors.New("Article text does not exist")
+
+// articleText is the text of an article a reader is asking about
+type articleText struct {
+	Title   string `db:"title"`
+	Content string `db:"content"`
+}
+
+// findPublishedArticleText only finds published articles, readers can't ask
+// about drafts or texts held for review.
+func findPublishedArticleText(ctx context.Context, tx pgx.Tx, articleId ulid.ULID, difficulty string) (text articleText, err error) {
+	q := `
+	SELECT a.title, t.content
+	FROM articles a
+	INNER JOIN article_texts t ON t.article_id = a.id
+	WHERE a.id = $1 AND t.difficulty = $2 AND a.is_published = TRUE AND t.moderation_status = 'APPROVED' AND a.deleted_at IS NULL AND t.deleted_at IS NULL
+	`
+
+	if err = pgxscan.Get(ctx, tx, &text, q, articleId, difficulty); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return text, ErrArticleTextDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find published article text")
+		return
+	}
+
+	return text, nil
+}
@@ -0,0 +1,81 @@
+package assistant
+
+import (
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+type ChatRole string
+
+const (
+	CHAT_USER      ChatRole = "user"
+	CHAT_ASSISTANT ChatRole = "assistant"
+)
+
+// Chats are named after the question they started with
+const chatTitleLength = 100
+
+type Chat struct {
+	Id         ulid.ULID `json:"id"`
+	UserId     ulid.ULID `json:"user_id"`
+	ArticleId  ulid.ULID `json:"article_id"`
+	Difficulty string    `json:"difficulty"`
+	Title      string    `json:"title"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  null.Time `json:"updated_at"`
+}
+
+type ChatMessage struct {
+	Id            ulid.ULID   `json:"id"`
+	ChatId        ulid.ULID   `json:"chat_id"`
+	Role          ChatRole    `json:"role"`
+	Content       string      `json:"content"`
+	PromptVersion null.String `json:"prompt_version"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+func NewChat(userId ulid.ULID, articleIdStr, difficulty, question string) (Chat, map[string]error) {
+	errs := make(map[string]error)
+
+	articleId, err := validateChatArticleId(articleIdStr)
+	if err != nil {
+		errs["article_id"] = err
+	}
+	if err = validateChatDifficulty(difficulty); err != nil {
+		errs["difficulty"] = err
+	}
+	if err = validateChatQuestion(question); err != nil {
+		errs["question"] = err
+	}
+	if len(errs) != 0 {
+		return Chat{}, errs
+	}
+
+	title := strings.Join(strings.Fields(question), " ")
+	if runes := []rune(title); len(runes) > chatTitleLength {
+		title = strings.TrimSpace(string(runes[:chatTitleLength-1])) + "…"
+	}
+
+	return Chat{
+		Id:         ulid.Make(),
+		UserId:     userId,
+		ArticleId:  articleId,
+		Difficulty: difficulty,
+		Title:      title,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func NewChatMessage(chatId ulid.ULID, role ChatRole, content string, promptVersion null.String) ChatMessage {
+	return ChatMessage{
+		Id:            ulid.Make(),
+		ChatId:        chatId,
+		Role:          role,
+		Content:       strings.TrimSpace(content),
+		PromptVersion: promptVersion,
+		CreatedAt:     time.Now(),
+	}
+}
@@ -0,0 +1,198 @@
+package assistant
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/lexica-app/lexicapi/app/moderation"
+	"github.com/rs/zerolog/log"
+)
+
+func getChatsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	articleId := r.URL.Query().Get("article_id")
+	limitStr := r.URL.Query().Get("limit")
+
+	// Don't throw error to client just because of misinputs
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = defaultChatsLimit
+	}
+
+	chats, err := getChats(ctx, user, articleId, uint(limit))
+	if err != nil {
+		writeChatError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, chats)
+}
+
+func getChatDetailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	detail, err := getChatDetail(ctx, user, chi.URLParam(r, "id"))
+	if err != nil {
+		writeChatError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, detail)
+}
+
+func startChatHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	var body startChatReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	session, errs, err := startChatSession(ctx, user, body)
+	if errs != nil {
+		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
+		return
+	}
+	if err != nil {
+		writeChatError(w, err)
+		return
+	}
+
+	writeChatReply(w, r, session)
+}
+
+func continueChatHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	var body continueChatReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	session, err := continueChatSession(ctx, user, chi.URLParam(r, "id"), body)
+	if err != nil {
+		writeChatError(w, err)
+		return
+	}
+
+	writeChatReply(w, r, session)
+}
+
+func deleteChatHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	chat, err := removeChat(ctx, user, chi.URLParam(r, "id"))
+	if err != nil {
+		writeChatError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, chat)
+}
+
+// writeChatReply answers with the whole exchange as JSON, or when the client
+// accepts text/event-stream, streams the answer as it's generated. The stream
+// sends a "delta" event per moderated run of sentences and ends with either a
+// "done" event holding the saved exchange or an "error" event.
+func writeChatReply(w http.ResponseWriter, r *http.Request, session chatSession) {
+	ctx := r.Context()
+
+	if !app.AcceptsEventStream(r) {
+		exchange, err := replyToChat(ctx, session, nil)
+		if err != nil {
+			writeChatError(w, err)
+			return
+		}
+
+		app.WriteHttpBodyJson(w, http.StatusOK, exchange)
+		return
+	}
+
+	stream, err := app.NewEventStream(w)
+	if err != nil {
+		log.Err(err).Msg("Failed to stream chat reply")
+		app.WriteHttpInternalServerError(w)
+		return
+	}
+
+	exchange, err := replyToChat(ctx, session, func(delta string) error {
+		return stream.Send("delta", map[string]string{"content": delta})
+	})
+	if err != nil {
+		if !isKnownChatError(err) {
+			err = app.ErrInternalServerError
+		}
+
+		stream.SendError(err)
+		return
+	}
+
+	stream.Send("done", exchange)
+}
+
+func isKnownChatError(err error) bool {
+	if _, ok := adapters.LLMErrorStatus(err); ok {
+		return true
+	}
+
+	return errors.Is(err, ErrChatDoesNotExist) ||
+		errors.Is(err, ErrArticleTextDoesNotExist) ||
+		errors.Is(err, moderation.ErrContentBlocked) ||
+		errors.Is(err, moderation.ErrContentHeldForReview)
+}
+
+func writeChatError(w http.ResponseWriter, err error) {
+	switch {
+	case
+		errors.As(err, &ErrInvalidChatId),
+		errors.As(err, &ErrInvalidChatArticleId),
+		errors.As(err, &ErrChatQuestionEmpty),
+		errors.As(err, &ErrChatQuestionTooLong):
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+	case errors.Is(err, ErrChatDoesNotExist), errors.Is(err, ErrArticleTextDoesNotExist):
+		app.WriteHttpError(w, http.StatusNotFound, err)
+	case errors.Is(err, moderation.ErrContentBlocked), errors.Is(err, moderation.ErrContentHeldForReview):
+		app.WriteHttpError(w, http.StatusUnprocessableEntity, err)
+	default:
+		app.WriteHttpLLMError(w, err)
+	}
+}
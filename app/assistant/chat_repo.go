@@ -0,0 +1,110 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrChatDoesNotExist = errors.New("Chat does not exist")
+
+func findChatsByUserId(ctx context.Context, tx pgx.Tx, userId ulid.ULID, articleId *ulid.ULID, limit uint) (chats []*Chat, err error) {
+	q := `
+	SELECT * FROM assistant_chats
+	WHERE user_id = $1 AND ($2::BYTEA IS NULL OR article_id = $2)
+	ORDER BY COALESCE(updated_at, created_at) DESC
+	LIMIT $3
+	`
+
+	if err = pgxscan.Select(ctx, tx, &chats, q, userId, articleId, limit); err != nil {
+		log.Err(err).Msg("Failed to find chats by user id")
+		return
+	}
+
+	return chats, nil
+}
+
+func findChatByIdAndUserId(ctx context.Context, tx pgx.Tx, id, userId ulid.ULID) (chat Chat, err error) {
+	q := "SELECT * FROM assistant_chats WHERE id = $1 AND user_id = $2"
+
+	if err = pgxscan.Get(ctx, tx, &chat, q, id, userId); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return chat, ErrChatDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find chat")
+		return
+	}
+
+	return chat, nil
+}
+
+func findChatMessages(ctx context.Context, tx pgx.Tx, chatId ulid.ULID) (messages []*ChatMessage, err error) {
+	q := "SELECT * FROM assistant_chat_messages WHERE chat_id = $1 ORDER BY id ASC"
+
+	if err = pgxscan.Select(ctx, tx, &messages, q, chatId); err != nil {
+		log.Err(err).Msg("Failed to find chat messages")
+		return
+	}
+
+	return messages, nil
+}
+
+func saveChat(ctx context.Context, tx pgx.Tx, chat Chat) (err error) {
+	q := `
+	INSERT INTO assistant_chats(id, user_id, article_id, difficulty, title, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if _, err = tx.Exec(ctx, q, chat.Id, chat.UserId, chat.ArticleId, chat.Difficulty, chat.Title, chat.CreatedAt); err != nil {
+		log.Err(err).Msg("Failed to save chat")
+		return
+	}
+
+	return nil
+}
+
+func saveChatMessages(ctx context.Context, tx pgx.Tx, messages ...ChatMessage) (err error) {
+	q := `
+	INSERT INTO assistant_chat_messages(id, chat_id, role, content, prompt_version, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for _, m := range messages {
+		if _, err = tx.Exec(ctx, q, m.Id, m.ChatId, m.Role, m.Content, m.PromptVersion, m.CreatedAt); err != nil {
+			log.Err(err).Msg("Failed to save chat message")
+			return
+		}
+	}
+
+	return nil
+}
+
+func touchChat(ctx context.Context, tx pgx.Tx, chat Chat) (touchedChat Chat, err error) {
+	q := "UPDATE assistant_chats SET updated_at = NOW() WHERE id = $1 RETURNING *"
+
+	if err = pgxscan.Get(ctx, tx, &touchedChat, q, chat.Id); err != nil {
+		log.Err(err).Msg("Failed to touch chat")
+		return
+	}
+
+	return touchedChat, nil
+}
+
+func deleteChat(ctx context.Context, tx pgx.Tx, chat Chat) (err error) {
+	if _, err = tx.Exec(ctx, "DELETE FROM assistant_chat_messages WHERE chat_id = $1", chat.Id); err != nil {
+		log.Err(err).Msg("Failed to delete chat messages")
+		return
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM assistant_chats WHERE id = $1", chat.Id); err != nil {
+		log.Err(err).Msg("Failed to delete chat")
+		return
+	}
+
+	return nil
+}
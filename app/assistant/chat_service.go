@@ -0,0 +1,421 @@
+package assistant
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/lexica-app/lexicapi/app/moderation"
+	"github.com/lexica-app/lexicapi/app/prompt"
+	"github.com/lexica-app/lexicapi/app/usage"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	// Long articles and chats are cut so the prompt stays well inside the
+	// context window, the article keeps its beginning and the history keeps
+	// its latest messages.
+	chatArticleTokens = 6000
+	chatHistoryTokens = 3000
+
+	// A streamed answer is held back until it has at least this many bytes
+	// of whole sentences, which are moderated before they're sent
+	chatModerationChunkSize = 200
+
+	defaultChatsLimit = 20
+	maxChatsLimit     = 100
+)
+
+// chatSession is everything needed to answer the next question of a chat,
+// new chats are only saved once they got their first answer.
+type chatSession struct {
+	chat     Chat
+	isNew    bool
+	article  articleText
+	history  []*ChatMessage
+	question string
+}
+
+func startChatSession(ctx context.Context, user auth.User, body startChatReq) (s chatSession, errs map[string]error, err error) {
+	chat, errs := NewChat(user.Id, body.ArticleId, strings.TrimSpace(body.Difficulty), body.Question)
+	if len(errs) != 0 {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to start chat")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	article, err := findPublishedArticleText(ctx, tx, chat.ArticleId, chat.Difficulty)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to start chat")
+		return
+	}
+
+	return chatSession{
+		chat:     chat,
+		isNew:    true,
+		article:  article,
+		question: strings.TrimSpace(body.Question),
+	}, nil, nil
+}
+
+func continueChatSession(ctx context.Context, user auth.User, idStr string, body continueChatReq) (s chatSession, err error) {
+	id, err := validateChatId(idStr)
+	if err != nil {
+		return
+	}
+
+	if err = validateChatQuestion(body.Question); err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to continue chat")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	chat, err := findChatByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	// The article may have been unpublished since the chat started
+	article, err := findPublishedArticleText(ctx, tx, chat.ArticleId, chat.Difficulty)
+	if err != nil {
+		return
+	}
+
+	history, err := findChatMessages(ctx, tx, chat.Id)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to continue chat")
+		return
+	}
+
+	return chatSession{
+		chat:     chat,
+		article:  article,
+		history:  history,
+		question: strings.TrimSpace(body.Question),
+	}, nil
+}
+
+// replyToChat asks the model for the answer to the session's question, onDelta
+// receives the answer in sentences as they're generated and pass moderation.
+// The question and answer are only saved once the whole answer is there.
+func replyToChat(ctx context.Context, s chatSession, onDelta func(delta string) error) (exchange ChatExchange, err error) {
+	settings := adapters.LLMTaskSettingsFor(adapters.LLMTaskChat)
+
+	rendered, err := prompt.Render(ctx, prompt.ARTICLE_CHAT, map[string]any{
+		"Title":      s.article.Title,
+		"Difficulty": s.chat.Difficulty,
+		"Article":    trimChatArticle(s.article.Content, chatArticleTokens),
+		"Question":   s.question,
+	})
+	if err != nil {
+		return
+	}
+
+	question := NewChatMessage(s.chat.Id, CHAT_USER, s.question, null.String{})
+
+	messages := []adapters.LLMMessage{{Role: adapters.LLMRoleSystem, Content: rendered.System}}
+	messages = append(messages, chatHistoryMessages(s.history, chatHistoryTokens)...)
+	messages = append(messages, adapters.LLMMessage{Role: adapters.LLMRoleUser, Content: rendered.User})
+
+	subject := moderation.Subject{Kind: moderation.CHAT_REPLY}
+	if !s.isNew {
+		subject.Id = &s.chat.Id
+	}
+	stream := &moderatedChatStream{ctx: ctx, subject: subject, onDelta: onDelta}
+
+	res, err := usage.CompleteStream(ctx, llmAdapter, adapters.LLMTaskChat, settings.Request(messages...), stream.write)
+	if err != nil {
+		log.Err(err).Msg("Failed to generate chat reply")
+		return
+	}
+
+	log.Info().Fields(map[string]any{
+		"provider":      llmAdapter.Provider(),
+		"task":          adapters.LLMTaskChat,
+		"prompt":        rendered.Ref,
+		"chat_id":       s.chat.Id.String(),
+		"id":            res.Id,
+		"model":         res.Model,
+		"usage":         res.Usage,
+		"finish_reason": res.FinishReason,
+	}).Msg("LLM - Chat Request")
+
+	recordQuotaTokens(ctx, res.Usage.TotalTokens)
+
+	// A flagged reply is dropped along with its question, a streaming client
+	// gets an error event and has to discard what it already showed. The whole
+	// reply is checked once more since a sentence can be harmless on its own.
+	subject.Text = res.Content
+	if err = moderation.Check(ctx, subject).Err(); err != nil {
+		return
+	}
+	if err = stream.flush(); err != nil {
+		return
+	}
+
+	answer := NewChatMessage(s.chat.Id, CHAT_ASSISTANT, res.Content, null.StringFrom(rendered.Ref))
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to save chat reply")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if s.isNew {
+		if err = saveChat(ctx, tx, s.chat); err != nil {
+			return
+		}
+	}
+
+	if err = saveChatMessages(ctx, tx, question, answer); err != nil {
+		return
+	}
+
+	chat, err := touchChat(ctx, tx, s.chat)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to save chat reply")
+		return
+	}
+
+	return ChatExchange{Chat: chat, Question: question, Answer: answer}, nil
+}
+
+// moderatedChatStream holds back a streamed answer and only passes it on to
+// onDelta in whole sentences that passed moderation, so nothing unchecked
+// reaches the client. onDelta may be nil when the answer isn't streamed.
+type moderatedChatStream struct {
+	ctx     context.Context
+	subject moderation.Subject
+	onDelta func(delta string) error
+	pending string
+}
+
+func (m *moderatedChatStream) write(delta string) error {
+	if m.onDelta == nil {
+		return nil
+	}
+
+	m.pending += delta
+	end := lastSentenceEnd(m.pending)
+	if end < chatModerationChunkSize {
+		return nil
+	}
+
+	chunk := m.pending[:end]
+	subject := m.subject
+	subject.Text = chunk
+	if err := moderation.Check(m.ctx, subject).Err(); err != nil {
+		return err
+	}
+
+	m.pending = m.pending[end:]
+	return m.onDelta(chunk)
+}
+
+// flush sends what's still held back, once the whole answer passed moderation.
+func (m *moderatedChatStream) flush() error {
+	if m.onDelta == nil || m.pending == "" {
+		return nil
+	}
+
+	chunk := m.pending
+	m.pending = ""
+	return m.onDelta(chunk)
+}
+
+// lastSentenceEnd is where the last whole sentence of text ends, -1 when there
+// is none yet.
+func lastSentenceEnd(text string) int {
+	end := -1
+	for _, sep := range []string{". ", "! ", "? ", "\n"} {
+		if i := strings.LastIndex(text, sep); i >= 0 && i+len(sep) > end {
+			end = i + len(sep)
+		}
+	}
+
+	return end
+}
+
+// trimChatArticle keeps the whole paragraphs from the start of the article
+// that fit in maxTokens.
+func trimChatArticle(content string, maxTokens int) string {
+	if adapters.EstimateTokens(content) <= maxTokens {
+		return content
+	}
+
+	var b strings.Builder
+	tokens := 0
+	for _, paragraph := range strings.Split(content, "\n") {
+		paragraphTokens := adapters.EstimateTokens(paragraph) + 1
+		if tokens+paragraphTokens > maxTokens && b.Len() > 0 {
+			break
+		}
+
+		b.WriteString(paragraph)
+		b.WriteString("\n")
+		tokens += paragraphTokens
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// chatHistoryMessages keeps the latest messages of a chat that fit in
+// maxTokens, oldest first.
+func chatHistoryMessages(history []*ChatMessage, maxTokens int) []adapters.LLMMessage {
+	start := len(history)
+	tokens := 0
+	for start > 0 {
+		messageTokens := adapters.EstimateTokens(history[start-1].Content)
+		if tokens+messageTokens > maxTokens {
+			break
+		}
+
+		tokens += messageTokens
+		start--
+	}
+
+	// Never start the history with an answer to a question that was cut
+	if start < len(history) && history[start].Role == CHAT_ASSISTANT {
+		start++
+	}
+
+	messages := make([]adapters.LLMMessage, 0, len(history)-start)
+	for _, m := range history[start:] {
+		role := adapters.LLMRoleUser
+		if m.Role == CHAT_ASSISTANT {
+			role = adapters.LLMRoleAssistant
+		}
+
+		messages = append(messages, adapters.LLMMessage{Role: role, Content: m.Content})
+	}
+
+	return messages
+}
+
+func getChats(ctx context.Context, user auth.User, articleIdStr string, limit uint) (chats []*Chat, err error) {
+	var articleId *ulid.ULID
+	if articleIdStr != "" {
+		id, err := validateChatArticleId(articleIdStr)
+		if err != nil {
+			return chats, err
+		}
+
+		articleId = &id
+	}
+
+	if limit == 0 || limit > maxChatsLimit {
+		limit = defaultChatsLimit
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get chats")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	chats, err = findChatsByUserId(ctx, tx, user.Id, articleId, limit)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get chats")
+		return
+	}
+
+	return chats, nil
+}
+
+func getChatDetail(ctx context.Context, user auth.User, idStr string) (detail ChatDetail, err error) {
+	id, err := validateChatId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get chat detail")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	chat, err := findChatByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	messages, err := findChatMessages(ctx, tx, chat.Id)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get chat detail")
+		return
+	}
+
+	return ChatDetail{Chat: chat, Messages: messages}, nil
+}
+
+func removeChat(ctx context.Context, user auth.User, idStr string) (chat Chat, err error) {
+	id, err := validateChatId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to remove chat")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	chat, err = findChatByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	if err = deleteChat(ctx, tx, chat); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to remove chat")
+		return
+	}
+
+	return chat, nil
+}
@@ -0,0 +1,55 @@
+package assistant
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lexica-app/lexicapi/app/moderation"
+)
+
+func TestModeratedChatStreamSendsWholeSentences(t *testing.T) {
+	sentence := strings.Repeat("kata ", 30) + "selesai. "
+	answer := sentence + sentence + "Kalimat terakhir tanpa"
+
+	var sent []string
+	stream := &moderatedChatStream{
+		ctx:     context.Background(),
+		subject: moderation.Subject{Kind: moderation.CHAT_REPLY},
+		onDelta: func(delta string) error {
+			sent = append(sent, delta)
+			return nil
+		},
+	}
+
+	for _, word := range strings.SplitAfter(answer, " ") {
+		if err := stream.write(word); err != nil {
+			t.Fatalf("write(%q) = %v, want nil", word, err)
+		}
+	}
+	for _, delta := range sent {
+		if end := lastSentenceEnd(delta); end != len(delta) || end < chatModerationChunkSize {
+			t.Errorf("sent %q before moderating a whole run of sentences", delta)
+		}
+	}
+	if strings.Contains(strings.Join(sent, ""), "terakhir") {
+		t.Fatalf("sent %q, want the unfinished sentence held back", sent)
+	}
+
+	if err := stream.flush(); err != nil {
+		t.Fatalf("flush() = %v, want nil", err)
+	}
+	if got := strings.Join(sent, ""); got != answer {
+		t.Errorf("sent %q, want the whole answer %q", got, answer)
+	}
+}
+
+func TestModeratedChatStreamWithoutClient(t *testing.T) {
+	stream := &moderatedChatStream{ctx: context.Background(), subject: moderation.Subject{Kind: moderation.CHAT_REPLY}}
+	if err := stream.write(strings.Repeat("Satu kalimat. ", 30)); err != nil {
+		t.Fatalf("write() = %v, want nil", err)
+	}
+	if err := stream.flush(); err != nil {
+		t.Fatalf("flush() = %v, want nil", err)
+	}
+}
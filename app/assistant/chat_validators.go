@@ -0,0 +1,54 @@
+package assistant
+
+import (
+	"strings"
+
+	"github.com/jellydator/validation"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrInvalidChatId         = validation.NewError("assistant:invalid_chat_id", "Invalid chat id")
+	ErrInvalidChatArticleId  = validation.NewError("assistant:invalid_article_id", "Invalid article id")
+	ErrInvalidChatDifficulty = validation.NewError("assistant:invalid_difficulty", "Invalid text difficulty")
+	ErrChatQuestionEmpty     = validation.NewError("assistant:question_empty", "Question can't be empty")
+	ErrChatQuestionTooLong   = validation.NewError("assistant:question_too_long", "Question can't be longer than 1000 characters")
+)
+
+func validateChatId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidChatId
+	}
+
+	return id, nil
+}
+
+func validateChatArticleId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidChatArticleId
+	}
+
+	return id, nil
+}
+
+// validateChatDifficulty only checks the shape, whether the article has a text
+// of that difficulty is up to the database.
+func validateChatDifficulty(difficulty string) error {
+	difficulty = strings.TrimSpace(difficulty)
+	return validation.Validate(
+		&difficulty,
+		validation.Required.ErrorObject(ErrInvalidChatDifficulty),
+		validation.Length(1, 25).ErrorObject(ErrInvalidChatDifficulty),
+	)
+}
+
+func validateChatQuestion(question string) error {
+	question = strings.TrimSpace(question)
+	return validation.Validate(
+		&question,
+		validation.Required.ErrorObject(ErrChatQuestionEmpty),
+		validation.RuneLength(1, 1000).ErrorObject(ErrChatQuestionTooLong),
+	)
+}
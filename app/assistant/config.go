@@ -1,22 +1,92 @@
 package assistant
 
 import (
+	"container/list"
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lexica-app/lexicapi/adapters"
 	"github.com/rs/zerolog/log"
-	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	defaultResponseCacheTtlHours = 168
+	defaultQuotaTimezone         = "Asia/Jakarta"
 )
 
 var (
-	openAIAdapter *openai.Client
+	pool             *pgxpool.Pool
+	llmAdapter       adapters.LLM
+	responseCacheTtl = defaultResponseCacheTtlHours * time.Hour
+	memoryCache      *responseMemoryCache
+	quotaLimits      = defaultQuotaLimits
+	quotaLocation    = time.FixedZone("WIB", 7*60*60)
 
-	ErrNilOpenAIAdapter = errors.New("OpenAI adapter can't be nil")
+	ErrNilPool       = errors.New("connection pool can't be nil")
+	ErrNilLLMAdapter = errors.New("LLM adapter can't be nil")
 )
 
-func SetOpenAIAdapter(adapter *openai.Client) {
+func SetPool(newPool *pgxpool.Pool) {
+	if newPool == nil {
+		log.Fatal().Err(ErrNilPool).Msg("Failed to set connection pool for assistant module")
+	}
+
+	pool = newPool
+}
+
+func SetLLMAdapter(adapter adapters.LLM) {
 	if adapter == nil {
-		log.Fatal().Err(ErrNilOpenAIAdapter).Msg("Failed to set OpenAI adapter for assistant module")
+		log.Fatal().Err(ErrNilLLMAdapter).Msg("Failed to set LLM adapter for assistant module")
+	}
+
+	llmAdapter = adapter
+}
+
+// ConfigureResponseCache sets how long simplify and explain answers are reused
+// and the size of the in-memory LRU kept in front of the database, a memory
+// size of 0 only uses the database.
+func ConfigureResponseCache(ttlHours, memorySize int) {
+	if ttlHours <= 0 {
+		ttlHours = defaultResponseCacheTtlHours
+	}
+	responseCacheTtl = time.Duration(ttlHours) * time.Hour
+
+	if memorySize <= 0 {
+		memoryCache = nil
+		return
+	}
+
+	memoryCache = &responseMemoryCache{
+		capacity: memorySize,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// ConfigureQuotas sets the limits of each role from its spec, see
+// parseQuotaLimits for the format, and the timezone whose midnight resets the
+// daily and monthly windows.
+func ConfigureQuotas(timezone string, specs map[string]string) {
+	if timezone = strings.TrimSpace(timezone); timezone == "" {
+		timezone = defaultQuotaTimezone
+	}
+	if location, err := time.LoadLocation(timezone); err == nil {
+		quotaLocation = location
+	} else {
+		log.Warn().Err(err).Str("timezone", timezone).Msg("Failed to load assistant quota timezone, using UTC+7")
+	}
+
+	limits := make(map[string]QuotaLimits, len(defaultQuotaLimits))
+	for role, defaults := range defaultQuotaLimits {
+		l, err := parseQuotaLimits(defaults, specs[role])
+		if err != nil {
+			log.Fatal().Err(err).Str("role", role).Msg("Failed to configure assistant quotas")
+		}
+
+		limits[role] = l
 	}
 
-	openAIAdapter = adapter
+	quotaLimits = limits
 }
@@ -0,0 +1,68 @@
+package assistant
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/lexica-app/lexicapi/app/dictionary"
+	"github.com/lexica-app/lexicapi/app/usage"
+)
+
+// defineFallbackHandler explains words the dictionary doesn't have. Only the
+// fallback reaches the model, so only it's held to the budget and quotas.
+var defineFallbackHandler = usage.BudgetMiddleware(quotaMiddleware(http.HandlerFunc(explainWordHandler)))
+
+func defineWordHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if _, ok := ctx.Value(auth.UserInfoCtx).(auth.User); !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	d, err := dictionary.Define(ctx, r.URL.Query().Get("word"))
+	if err != nil {
+		switch {
+		case errors.Is(err, dictionary.ErrWordNotFound):
+			defineFallbackHandler.ServeHTTP(w, r)
+		case errors.As(err, &dictionary.ErrWordEmpty), errors.As(err, &dictionary.ErrInvalidWord):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, WordDefinition{
+		Word:       d.Word,
+		Source:     DICTIONARY,
+		Definition: &d,
+	})
+}
+
+func explainWordHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	word := strings.TrimSpace(r.URL.Query().Get("word"))
+	explained, err := explainText(ctx, &user, ExplainTextReq{Text: word}, nil)
+	if err != nil {
+		writeExplainTextError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, WordDefinition{
+		Word:        word,
+		Source:      ASSISTANT,
+		Explanation: &explained,
+	})
+}
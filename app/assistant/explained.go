@@ -1,8 +1,10 @@
 package assistant
 
 type Explained struct {
-	Text        string `json:"text"`
-	Explanation string `json:"explanation"`
+	Text          string      `json:"text"`
+	Explanation   string      `json:"explanation"`
+	PromptVersion string      `json:"prompt_version"`
+	Annotation    *Annotation `json:"annotation,omitempty"`
 }
 
 func NewExplained(text string) (explained Explained, err error) {
@@ -13,12 +15,13 @@ func NewExplained(text string) (explained Explained, err error) {
 	return Explained{Text: text}, nil
 }
 
-func (e *Explained) Explain(explanation string) (err error) {
+func (e *Explained) Explain(explanation, promptVersion string) (err error) {
 	if err = validateExplainedExplanation(explanation); err != nil {
 		return
 	}
 
 	e.Explanation = explanation
+	e.PromptVersion = promptVersion
 
 	return nil
 }
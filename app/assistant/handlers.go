@@ -4,15 +4,18 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
+	"github.com/lexica-app/lexicapi/adapters"
 	"github.com/lexica-app/lexicapi/app"
 	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/lexica-app/lexicapi/app/moderation"
 )
 
 func simplifyTextHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	_, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
 	if !ok {
 		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
 		return
@@ -24,34 +27,58 @@ func simplifyTextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s, err := simplifyText(ctx, body.Text)
+	s, err := simplifyText(ctx, &user, body, nil)
 	if err != nil {
-		switch {
-		case
-			errors.As(err, &ErrSimplificationOriginalTextEmpty),
-			errors.As(err, &ErrSimplificationOriginalTextTooLong),
-			errors.As(err, &ErrSimplificationSimplifiedTextEmpty):
-			app.WriteHttpError(w, http.StatusBadRequest, err)
-		case errors.Is(err, ErrInvalidOpenAIAPIKey):
-			app.WriteHttpError(w, http.StatusUnauthorized, err)
-		case errors.Is(err, ErrOpenAIRateLimited):
-			app.WriteHttpError(w, http.StatusTooManyRequests, err)
-		case errors.Is(err, ErrOpenAIServiceError):
-			app.WriteHttpError(w, http.StatusServiceUnavailable, err)
-		default:
-			app.WriteHttpInternalServerError(w)
-		}
+		writeSimplifyTextError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, s)
+}
+
+func adminSimplifyTextHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
+	var body adminSimplifyTextReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s, err := simplifyText(ctx, nil, body.SimplifyTextReq, body.ModelSettings)
+	if err != nil {
+		writeSimplifyTextError(w, err)
 		return
 	}
 
 	app.WriteHttpBodyJson(w, http.StatusOK, s)
 }
 
+func writeSimplifyTextError(w http.ResponseWriter, err error) {
+	switch {
+	case
+		errors.As(err, &ErrSimplificationOriginalTextEmpty),
+		errors.As(err, &ErrSimplificationOriginalTextTooLong),
+		errors.As(err, &ErrSimplificationSimplifiedTextEmpty),
+		errors.As(err, &ErrInvalidAnchorArticleId),
+		errors.As(err, &ErrInvalidAnchorDifficulty),
+		errors.As(err, &ErrAnchorOffsetsRequired),
+		errors.As(err, &ErrInvalidAnchorRange),
+		errors.Is(err, adapters.ErrInvalidLLMTaskSettings):
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+	case errors.Is(err, ErrArticleTextDoesNotExist):
+		app.WriteHttpError(w, http.StatusNotFound, err)
+	case errors.Is(err, moderation.ErrContentBlocked), errors.Is(err, moderation.ErrContentHeldForReview):
+		app.WriteHttpError(w, http.StatusUnprocessableEntity, err)
+	default:
+		app.WriteHttpLLMError(w, err)
+	}
+}
+
 func explainTextHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	_, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
 	if !ok {
 		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
 		return
@@ -63,20 +90,95 @@ func explainTextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	explained, err := explainText(ctx, body)
+	explained, err := explainText(ctx, &user, body, nil)
+	if err != nil {
+		writeExplainTextError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, explained)
+}
+
+func adminExplainTextHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body adminExplainTextReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	explained, err := explainText(ctx, nil, body.ExplainTextReq, body.ModelSettings)
+	if err != nil {
+		writeExplainTextError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, explained)
+}
+
+func writeExplainTextError(w http.ResponseWriter, err error) {
+	switch {
+	case
+		errors.As(err, &ErrExplainedTextEmpty),
+		errors.As(err, &ErrExplainedTextTooLong),
+		errors.As(err, &ErrExplainedExplanationEmpty),
+		errors.As(err, &ErrInvalidAnchorArticleId),
+		errors.As(err, &ErrInvalidAnchorDifficulty),
+		errors.As(err, &ErrAnchorOffsetsRequired),
+		errors.As(err, &ErrInvalidAnchorRange),
+		errors.Is(err, adapters.ErrInvalidLLMTaskSettings):
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+	case errors.Is(err, ErrArticleTextDoesNotExist):
+		app.WriteHttpError(w, http.StatusNotFound, err)
+	case errors.Is(err, moderation.ErrContentBlocked), errors.Is(err, moderation.ErrContentHeldForReview):
+		app.WriteHttpError(w, http.StatusUnprocessableEntity, err)
+	default:
+		app.WriteHttpLLMError(w, err)
+	}
+}
+
+func getQuotaStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	status, err := getQuotaStatus(ctx, user, time.Now())
+	if err != nil {
+		app.WriteHttpInternalServerError(w)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, status)
+}
+
+func getResponseCacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	stats, err := getResponseCacheStats(ctx)
+	if err != nil {
+		app.WriteHttpInternalServerError(w)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, stats)
+}
+
+func invalidateResponseCacheHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	task := r.URL.Query().Get("task")
+	promptVersion := r.URL.Query().Get("prompt_version")
+
+	deleted, err := invalidateResponseCache(ctx, task, promptVersion)
 	if err != nil {
 		switch {
-		case
-			errors.As(err, &ErrExplainedTextEmpty),
-			errors.As(err, &ErrExplainedTextTooLong),
-			errors.As(err, &ErrExplainedExplanationEmpty):
+		case errors.As(err, &ErrInvalidResponseCacheTask):
 			app.WriteHttpError(w, http.StatusBadRequest, err)
-		case errors.Is(err, ErrInvalidOpenAIAPIKey):
-			app.WriteHttpError(w, http.StatusUnauthorized, err)
-		case errors.Is(err, ErrOpenAIRateLimited):
-			app.WriteHttpError(w, http.StatusTooManyRequests, err)
-		case errors.Is(err, ErrOpenAIServiceError):
-			app.WriteHttpError(w, http.StatusServiceUnavailable, err)
 		default:
 			app.WriteHttpInternalServerError(w)
 		}
@@ -84,5 +186,5 @@ func explainTextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.WriteHttpBodyJson(w, http.StatusOK, explained)
+	app.WriteHttpBodyJson(w, http.StatusOK, map[string]int64{"deleted": deleted})
 }
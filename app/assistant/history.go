@@ -0,0 +1,69 @@
+package assistant
+
+import (
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Notes made from a history entry are titled after its input
+const historyNoteTitleLength = 100
+
+// HistoryEntry is one explanation or simplification a user asked for, with
+// the article it came from when the text was selected in one.
+type HistoryEntry struct {
+	Id            ulid.ULID   `json:"id"`
+	UserId        ulid.ULID   `json:"user_id"`
+	Kind          ResultKind  `json:"kind"`
+	InputText     string      `json:"input_text"`
+	OutputText    string      `json:"output_text"`
+	PromptVersion string      `json:"prompt_version"`
+	ArticleId     *ulid.ULID  `json:"article_id"`
+	ArticleTitle  null.String `json:"article_title"`
+	Difficulty    null.String `json:"difficulty"`
+	Paragraph     null.String `json:"paragraph"`
+	IsFavourite   bool        `json:"is_favourite"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+func NewHistoryEntry(userId ulid.ULID, kind ResultKind, inputText, outputText, promptVersion string, anchor *anchoredText) HistoryEntry {
+	entry := HistoryEntry{
+		Id:            ulid.Make(),
+		UserId:        userId,
+		Kind:          kind,
+		InputText:     strings.TrimSpace(inputText),
+		OutputText:    strings.TrimSpace(outputText),
+		PromptVersion: promptVersion,
+		CreatedAt:     time.Now(),
+	}
+
+	if anchor != nil {
+		articleId := anchor.ArticleId
+		entry.ArticleId = &articleId
+		entry.ArticleTitle = null.StringFrom(anchor.Title)
+		entry.Difficulty = null.StringFrom(anchor.Difficulty)
+		entry.Paragraph = null.StringFrom(anchor.Paragraph)
+	}
+
+	return entry
+}
+
+// noteTitle is the input cut to fit a note title
+func (e HistoryEntry) noteTitle() string {
+	title := strings.Join(strings.Fields(e.InputText), " ")
+	if runes := []rune(title); len(runes) > historyNoteTitleLength {
+		title = strings.TrimSpace(string(runes[:historyNoteTitleLength-1])) + "…"
+	}
+
+	return title
+}
+
+func (e HistoryEntry) articleIdString() null.String {
+	if e.ArticleId == nil {
+		return null.String{}
+	}
+
+	return null.StringFrom(e.ArticleId.String())
+}
@@ -0,0 +1,164 @@
+package assistant
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+)
+
+func getHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	query := r.URL.Query()
+
+	// Don't throw error to client just because of misinputs
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = defaultHistoryPageSize
+	}
+	favouriteOnly, _ := strconv.ParseBool(query.Get("favourite"))
+
+	history, err := getHistory(ctx, user, getHistoryReq{
+		Query:         query.Get("q"),
+		Kind:          query.Get("kind"),
+		FavouriteOnly: favouriteOnly,
+		Cursor:        query.Get("cursor"),
+		PageSize:      uint(pageSize),
+	})
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, history)
+}
+
+func getHistoryEntryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	entry, err := getHistoryEntry(ctx, user, chi.URLParam(r, "id"))
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, entry)
+}
+
+func favouriteHistoryEntryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	var body favouriteHistoryEntryReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	entry, err := favouriteHistoryEntry(ctx, user, chi.URLParam(r, "id"), body)
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, entry)
+}
+
+func deleteHistoryEntryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	entry, err := removeHistoryEntry(ctx, user, chi.URLParam(r, "id"))
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, entry)
+}
+
+func historyEntryToCardHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	card, errs, err := historyEntryToCard(ctx, user, chi.URLParam(r, "id"))
+	if errs != nil {
+		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
+		return
+	}
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusCreated, card)
+}
+
+func historyEntryToNoteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	note, errs, err := historyEntryToNote(ctx, user, chi.URLParam(r, "id"))
+	if errs != nil {
+		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
+		return
+	}
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusCreated, note)
+}
+
+func writeHistoryError(w http.ResponseWriter, err error) {
+	switch {
+	case
+		errors.As(err, &ErrInvalidHistoryId),
+		errors.As(err, &ErrInvalidHistoryCursor),
+		errors.As(err, &ErrHistoryQueryTooLong),
+		errors.As(err, &ErrInvalidResultKind):
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+	case errors.Is(err, ErrHistoryEntryDoesNotExist):
+		app.WriteHttpError(w, http.StatusNotFound, err)
+	default:
+		app.WriteHttpInternalServerError(w)
+	}
+}
@@ -0,0 +1,94 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrHistoryEntryDoesNotExist = errors.New("History entry does not exist")
+
+type historyFilter struct {
+	Query         string
+	Kind          ResultKind
+	FavouriteOnly bool
+	Cursor        *ulid.ULID
+}
+
+// findHistory lists entries newest first, starting after the cursor
+func findHistory(ctx context.Context, tx pgx.Tx, userId ulid.ULID, filter historyFilter, limit uint) (entries []*HistoryEntry, err error) {
+	q := `
+	SELECT * FROM assistant_history
+	WHERE user_id = $1
+	AND ($2 = '' OR input_text ILIKE '%' || $2 || '%' OR output_text ILIKE '%' || $2 || '%' OR article_title ILIKE '%' || $2 || '%')
+	AND ($3 = '' OR kind = $3)
+	AND ($4 = FALSE OR is_favourite = TRUE)
+	AND ($5::BYTEA IS NULL OR id < $5)
+	ORDER BY id DESC
+	LIMIT $6
+	`
+
+	if err = pgxscan.Select(ctx, tx, &entries, q, userId, filter.Query, filter.Kind, filter.FavouriteOnly, filter.Cursor, limit); err != nil {
+		log.Err(err).Msg("Failed to find assistant history")
+		return
+	}
+
+	return entries, nil
+}
+
+func findHistoryEntryByIdAndUserId(ctx context.Context, tx pgx.Tx, id, userId ulid.ULID) (entry HistoryEntry, err error) {
+	q := "SELECT * FROM assistant_history WHERE id = $1 AND user_id = $2"
+
+	if err = pgxscan.Get(ctx, tx, &entry, q, id, userId); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return entry, ErrHistoryEntryDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find assistant history entry")
+		return
+	}
+
+	return entry, nil
+}
+
+func saveHistoryEntry(ctx context.Context, tx pgx.Tx, e HistoryEntry) (err error) {
+	q := `
+	INSERT INTO assistant_history(id, user_id, kind, input_text, output_text, prompt_version, article_id, article_title, difficulty, paragraph, is_favourite, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	if _, err = tx.Exec(
+		ctx, q,
+		e.Id, e.UserId, e.Kind, e.InputText, e.OutputText, e.PromptVersion,
+		e.ArticleId, e.ArticleTitle, e.Difficulty, e.Paragraph, e.IsFavourite, e.CreatedAt,
+	); err != nil {
+		log.Err(err).Msg("Failed to save assistant history entry")
+		return
+	}
+
+	return nil
+}
+
+func updateHistoryEntryFavourite(ctx context.Context, tx pgx.Tx, e HistoryEntry, isFavourite bool) (updated HistoryEntry, err error) {
+	q := "UPDATE assistant_history SET is_favourite = $2 WHERE id = $1 RETURNING *"
+
+	if err = pgxscan.Get(ctx, tx, &updated, q, e.Id, isFavourite); err != nil {
+		log.Err(err).Msg("Failed to update assistant history favourite")
+		return
+	}
+
+	return updated, nil
+}
+
+func deleteHistoryEntry(ctx context.Context, tx pgx.Tx, e HistoryEntry) (err error) {
+	if _, err = tx.Exec(ctx, "DELETE FROM assistant_history WHERE id = $1", e.Id); err != nil {
+		log.Err(err).Msg("Failed to delete assistant history entry")
+		return
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package assistant
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/lexica-app/lexicapi/app/notebook"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	defaultHistoryPageSize = 20
+	maxHistoryPageSize     = 100
+)
+
+// recordHistory keeps an answer in the user's history. The answer was already
+// paid for, so failing to record it is logged and never fails the request.
+func recordHistory(ctx context.Context, entry HistoryEntry) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to record assistant history")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = saveHistoryEntry(ctx, tx, entry); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to record assistant history")
+	}
+}
+
+func getHistory(ctx context.Context, user auth.User, req getHistoryReq) (history History, err error) {
+	filter := historyFilter{Query: strings.TrimSpace(req.Query), FavouriteOnly: req.FavouriteOnly}
+
+	if err = validateHistoryQuery(filter.Query); err != nil {
+		return
+	}
+
+	if req.Kind != "" {
+		if filter.Kind, err = validateResultKind(req.Kind); err != nil {
+			return
+		}
+	}
+
+	if filter.Cursor, err = validateHistoryCursor(req.Cursor); err != nil {
+		return
+	}
+
+	pageSize := req.PageSize
+	if pageSize == 0 || pageSize > maxHistoryPageSize {
+		pageSize = defaultHistoryPageSize
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get assistant history")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	entries, err := findHistory(ctx, tx, user.Id, filter, pageSize+1)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get assistant history")
+		return
+	}
+
+	// The extra entry tells whether there's another page
+	if len(entries) > int(pageSize) {
+		entries = entries[:pageSize]
+		history.Cursor = null.StringFrom(entries[pageSize-1].Id.String())
+	}
+	history.Entries = entries
+
+	return history, nil
+}
+
+func getHistoryEntry(ctx context.Context, user auth.User, idStr string) (entry HistoryEntry, err error) {
+	id, err := validateHistoryId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get assistant history entry")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	entry, err = findHistoryEntryByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get assistant history entry")
+		return
+	}
+
+	return entry, nil
+}
+
+func favouriteHistoryEntry(ctx context.Context, user auth.User, idStr string, body favouriteHistoryEntryReq) (entry HistoryEntry, err error) {
+	id, err := validateHistoryId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to favourite assistant history entry")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	entry, err = findHistoryEntryByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	entry, err = updateHistoryEntryFavourite(ctx, tx, entry, body.IsFavourite)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to favourite assistant history entry")
+		return
+	}
+
+	return entry, nil
+}
+
+func removeHistoryEntry(ctx context.Context, user auth.User, idStr string) (entry HistoryEntry, err error) {
+	id, err := validateHistoryId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to remove assistant history entry")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	entry, err = findHistoryEntryByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	if err = deleteHistoryEntry(ctx, tx, entry); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to remove assistant history entry")
+		return
+	}
+
+	return entry, nil
+}
+
+// historyEntryToCard puts the input on the front of a word bank card and the
+// answer on the back.
+func historyEntryToCard(ctx context.Context, user auth.User, idStr string) (card notebook.Card, errs map[string]error, err error) {
+	entry, err := getHistoryEntry(ctx, user, idStr)
+	if err != nil {
+		return
+	}
+
+	card, errs = notebook.NewCard(user.Id, entry.InputText, entry.OutputText, entry.articleIdString())
+	if len(errs) != 0 {
+		return
+	}
+
+	card, err = notebook.AddCard(ctx, card)
+	return card, nil, err
+}
+
+// historyEntryToNote titles a note after the input and keeps both the input
+// and the answer in it.
+func historyEntryToNote(ctx context.Context, user auth.User, idStr string) (note notebook.Note, errs map[string]error, err error) {
+	entry, err := getHistoryEntry(ctx, user, idStr)
+	if err != nil {
+		return
+	}
+
+	content := entry.InputText + "\n\n" + entry.OutputText
+	note, errs = notebook.NewNote(user.Id, entry.noteTitle(), content, entry.articleIdString())
+	if len(errs) != 0 {
+		return
+	}
+
+	note, err = notebook.AddNote(ctx, note)
+	return note, nil, err
+}
@@ -0,0 +1,45 @@
+package assistant
+
+import (
+	"strings"
+
+	"github.com/jellydator/validation"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrInvalidHistoryId     = validation.NewError("assistant:invalid_history_id", "Invalid history entry id")
+	ErrInvalidHistoryCursor = validation.NewError("assistant:invalid_cursor", "Invalid history cursor")
+	ErrHistoryQueryTooLong  = validation.NewError("assistant:query_too_long", "Search query can't be longer than 100 characters")
+)
+
+func validateHistoryId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidHistoryId
+	}
+
+	return id, nil
+}
+
+// validateHistoryCursor accepts an empty cursor for the first page
+func validateHistoryCursor(cursorStr string) (cursor *ulid.ULID, err error) {
+	if cursorStr == "" {
+		return nil, nil
+	}
+
+	id, err := ulid.Parse(cursorStr)
+	if err != nil {
+		return nil, ErrInvalidHistoryCursor
+	}
+
+	return &id, nil
+}
+
+func validateHistoryQuery(query string) error {
+	query = strings.TrimSpace(query)
+	return validation.Validate(
+		&query,
+		validation.RuneLength(0, 100).ErrorObject(ErrHistoryQueryTooLong),
+	)
+}
@@ -0,0 +1,190 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+type QuotaPeriod string
+
+const (
+	DAILY   QuotaPeriod = "daily"
+	MONTHLY QuotaPeriod = "monthly"
+)
+
+var (
+	ErrQuotaExceeded      = errors.New("Assistant quota exceeded, please try again after it resets")
+	ErrInvalidQuotaLimits = errors.New("Invalid assistant quota limits")
+)
+
+// QuotaLimits of a role, a limit of 0 means unlimited.
+type QuotaLimits struct {
+	DailyRequests   int64 `json:"daily_requests"`
+	DailyTokens     int64 `json:"daily_tokens"`
+	MonthlyRequests int64 `json:"monthly_requests"`
+	MonthlyTokens   int64 `json:"monthly_tokens"`
+}
+
+type QuotaUsage struct {
+	UserId      ulid.ULID   `json:"user_id"`
+	Period      QuotaPeriod `json:"period"`
+	PeriodStart time.Time   `json:"period_start"`
+	Requests    int64       `json:"requests"`
+	Tokens      int64       `json:"tokens"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type QuotaWindow struct {
+	Period            QuotaPeriod `json:"period"`
+	Requests          int64       `json:"requests"`
+	Tokens            int64       `json:"tokens"`
+	RequestsRemaining null.Int    `json:"requests_remaining"`
+	TokensRemaining   null.Int    `json:"tokens_remaining"`
+	ResetAt           time.Time   `json:"reset_at"`
+}
+
+type QuotaStatus struct {
+	Role    string      `json:"role"`
+	Limits  QuotaLimits `json:"limits"`
+	Daily   QuotaWindow `json:"daily"`
+	Monthly QuotaWindow `json:"monthly"`
+}
+
+var defaultQuotaLimits = map[string]QuotaLimits{
+	auth.STUDENT.String.String:  {DailyRequests: 100, DailyTokens: 100000, MonthlyRequests: 2000, MonthlyTokens: 2000000},
+	auth.EDUCATOR.String.String: {DailyRequests: 200, DailyTokens: 200000, MonthlyRequests: 4000, MonthlyTokens: 4000000},
+	auth.CIVILIAN.String.String: {DailyRequests: 50, DailyTokens: 50000, MonthlyRequests: 1000, MonthlyTokens: 1000000},
+}
+
+// quotaRole falls back to the most restrictive general role for users who
+// haven't picked one during onboarding yet.
+func quotaRole(user auth.User) string {
+	if _, ok := quotaLimits[user.Role.String.String]; ok && user.Role.Valid {
+		return user.Role.String.String
+	}
+
+	return auth.CIVILIAN.String.String
+}
+
+func quotaPeriodStarts(now time.Time) (day, month time.Time) {
+	local := now.In(quotaLocation)
+	day = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	month = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return day, month
+}
+
+func quotaResetAt(period QuotaPeriod, now time.Time) time.Time {
+	local := now.In(quotaLocation)
+	if period == DAILY {
+		return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, quotaLocation)
+	}
+
+	return time.Date(local.Year(), local.Month()+1, 1, 0, 0, 0, 0, quotaLocation)
+}
+
+func newQuotaWindow(period QuotaPeriod, usage QuotaUsage, requestLimit, tokenLimit int64, now time.Time) QuotaWindow {
+	w := QuotaWindow{
+		Period:   period,
+		Requests: usage.Requests,
+		Tokens:   usage.Tokens,
+		ResetAt:  quotaResetAt(period, now),
+	}
+	if requestLimit > 0 {
+		w.RequestsRemaining = null.IntFrom(nonNegative(requestLimit - usage.Requests))
+	}
+	if tokenLimit > 0 {
+		w.TokensRemaining = null.IntFrom(nonNegative(tokenLimit - usage.Tokens))
+	}
+
+	return w
+}
+
+func (w QuotaWindow) IsExhausted() bool {
+	return (w.RequestsRemaining.Valid && w.RequestsRemaining.Int64 == 0) ||
+		(w.TokensRemaining.Valid && w.TokensRemaining.Int64 == 0)
+}
+
+// Exceeded returns the window that blocks the next request, the monthly one
+// wins when both are exhausted since it resets last.
+func (s QuotaStatus) Exceeded() (QuotaWindow, bool) {
+	if s.Monthly.IsExhausted() {
+		return s.Monthly, true
+	}
+	if s.Daily.IsExhausted() {
+		return s.Daily, true
+	}
+
+	return QuotaWindow{}, false
+}
+
+func nonNegative(n int64) int64 {
+	if n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+// parseQuotaLimits applies a comma separated list of key=value pairs, e.g.
+// "daily_requests=50,daily_tokens=50000,monthly_requests=1000,monthly_tokens=1000000",
+// on top of the defaults of a role.
+func parseQuotaLimits(l QuotaLimits, spec string) (QuotaLimits, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return l, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok {
+			return l, fmt.Errorf("%w: %q is not a key=value pair", ErrInvalidQuotaLimits, pair)
+		}
+
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return l, fmt.Errorf("%w: %s must be a non-negative number", ErrInvalidQuotaLimits, key)
+		}
+
+		switch key {
+		case "daily_requests":
+			l.DailyRequests = n
+		case "daily_tokens":
+			l.DailyTokens = n
+		case "monthly_requests":
+			l.MonthlyRequests = n
+		case "monthly_tokens":
+			l.MonthlyTokens = n
+		default:
+			return l, fmt.Errorf("%w: unknown key %q", ErrInvalidQuotaLimits, key)
+		}
+	}
+
+	return l, nil
+}
+
+type quotaContextKey string
+
+const quotaRecorderCtx quotaContextKey = "assistant.quota"
+
+// quotaRecorder collects the tokens spent while serving one request, LLM calls
+// add to it through the request context.
+type quotaRecorder struct {
+	tokens atomic.Int64
+}
+
+func recordQuotaTokens(ctx context.Context, tokens int) {
+	if recorder, ok := ctx.Value(quotaRecorderCtx).(*quotaRecorder); ok {
+		recorder.tokens.Add(int64(tokens))
+	}
+}
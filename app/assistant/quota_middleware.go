@@ -0,0 +1,91 @@
+package assistant
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/rs/zerolog/log"
+)
+
+type quotaExceededRes struct {
+	Message string      `json:"message"`
+	Period  QuotaPeriod `json:"period"`
+	ResetAt time.Time   `json:"reset_at"`
+}
+
+// quotaMiddleware refuses requests once the user has used up a daily or
+// monthly window. The check happens before the request and the usage is added
+// after it, so concurrent requests can overshoot a limit by a little, which is
+// fine for cost control.
+func quotaMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		user, ok := ctx.Value(auth.UserInfoCtx).(auth.User)
+		if !ok {
+			app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+			return
+		}
+
+		startedAt := time.Now()
+		status, err := getQuotaStatus(ctx, user, startedAt)
+		if err != nil {
+			app.WriteHttpInternalServerError(w)
+			return
+		}
+
+		if window, isExceeded := status.Exceeded(); isExceeded {
+			writeQuotaHeaders(w, status, 0)
+
+			retryAfter := int(math.Ceil(time.Until(window.ResetAt).Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			app.WriteHttpBodyJson(w, http.StatusTooManyRequests, quotaExceededRes{
+				Message: ErrQuotaExceeded.Error(),
+				Period:  window.Period,
+				ResetAt: window.ResetAt,
+			})
+			return
+		}
+
+		writeQuotaHeaders(w, status, 1)
+
+		recorder := &quotaRecorder{}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(ctx, quotaRecorderCtx, recorder)))
+
+		// Rejected input costs nothing, anything that reached the model counts.
+		// The usage is written even when the client already hung up.
+		tokens := recorder.tokens.Load()
+		if ww.Status() >= http.StatusBadRequest && tokens == 0 {
+			return
+		}
+		if err := consumeQuota(context.Background(), user.Id, startedAt, tokens); err != nil {
+			log.Err(err).Str("user_id", user.Id.String()).Msg("Failed to record assistant quota usage")
+		}
+	})
+}
+
+// writeQuotaHeaders reports what's left after the current request, unlimited
+// values are left out.
+func writeQuotaHeaders(w http.ResponseWriter, status QuotaStatus, pending int64) {
+	for _, window := range []QuotaWindow{status.Daily, status.Monthly} {
+		prefix := "X-Quota-Daily-"
+		if window.Period == MONTHLY {
+			prefix = "X-Quota-Monthly-"
+		}
+
+		if window.RequestsRemaining.Valid {
+			w.Header().Set(prefix+"Requests-Remaining", strconv.FormatInt(nonNegative(window.RequestsRemaining.Int64-pending), 10))
+		}
+		if window.TokensRemaining.Valid {
+			w.Header().Set(prefix+"Tokens-Remaining", strconv.FormatInt(window.TokensRemaining.Int64, 10))
+		}
+		w.Header().Set(prefix+"Reset", window.ResetAt.UTC().Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,45 @@
+package assistant
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func findQuotaUsage(ctx context.Context, tx pgx.Tx, userId ulid.ULID, period QuotaPeriod, periodStart time.Time) (usage QuotaUsage, err error) {
+	q := "SELECT * FROM assistant_quota_usage WHERE user_id = $1 AND period = $2 AND period_start = $3"
+
+	if err = pgxscan.Get(ctx, tx, &usage, q, userId, period, periodStart); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return QuotaUsage{UserId: userId, Period: period, PeriodStart: periodStart}, nil
+		}
+
+		log.Err(err).Msg("Failed to find assistant quota usage")
+		return
+	}
+
+	return usage, nil
+}
+
+func addQuotaUsage(ctx context.Context, tx pgx.Tx, userId ulid.ULID, period QuotaPeriod, periodStart time.Time, requests, tokens int64) (err error) {
+	q := `
+	INSERT INTO assistant_quota_usage(user_id, period, period_start, requests, tokens)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT(user_id, period, period_start)
+	DO UPDATE SET
+	  requests = assistant_quota_usage.requests + EXCLUDED.requests,
+	  tokens = assistant_quota_usage.tokens + EXCLUDED.tokens,
+	  updated_at = NOW()
+	`
+
+	if _, err = tx.Exec(ctx, q, userId, period, periodStart, requests, tokens); err != nil {
+		log.Err(err).Msg("Failed to add assistant quota usage")
+		return
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package assistant
+
+import (
+	"context"
+	"time"
+
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func getQuotaStatus(ctx context.Context, user auth.User, now time.Time) (status QuotaStatus, err error) {
+	dayStart, monthStart := quotaPeriodStarts(now)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get assistant quota status")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	daily, err := findQuotaUsage(ctx, tx, user.Id, DAILY, dayStart)
+	if err != nil {
+		return
+	}
+
+	monthly, err := findQuotaUsage(ctx, tx, user.Id, MONTHLY, monthStart)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get assistant quota status")
+		return
+	}
+
+	role := quotaRole(user)
+	limits := quotaLimits[role]
+
+	return QuotaStatus{
+		Role:    role,
+		Limits:  limits,
+		Daily:   newQuotaWindow(DAILY, daily, limits.DailyRequests, limits.DailyTokens, now),
+		Monthly: newQuotaWindow(MONTHLY, monthly, limits.MonthlyRequests, limits.MonthlyTokens, now),
+	}, nil
+}
+
+// consumeQuota counts one request and the tokens it spent in both windows of
+// the moment the request started.
+func consumeQuota(ctx context.Context, userId ulid.ULID, startedAt time.Time, tokens int64) (err error) {
+	dayStart, monthStart := quotaPeriodStarts(startedAt)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to consume assistant quota")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = addQuotaUsage(ctx, tx, userId, DAILY, dayStart, 1, tokens); err != nil {
+		return
+	}
+
+	if err = addQuotaUsage(ctx, tx, userId, MONTHLY, monthStart, 1, tokens); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to consume assistant quota")
+		return
+	}
+
+	return nil
+}
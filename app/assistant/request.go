@@ -1,9 +1,66 @@
 package assistant
 
+import (
+	"github.com/lexica-app/lexicapi/adapters"
+	"gopkg.in/guregu/null.v4"
+)
+
+// TextAnchor points at a selection in an article text instead of sending the
+// text itself, offsets count characters of the text content and the end is
+// exclusive.
+type TextAnchor struct {
+	ArticleId  string   `json:"article_id"`
+	Difficulty string   `json:"difficulty"`
+	Start      null.Int `json:"start"`
+	End        null.Int `json:"end"`
+}
+
+func (a TextAnchor) IsSet() bool {
+	return a.ArticleId != ""
+}
+
+// Explain and simplify either take the text or, with an article id, the
+// selection it's in.
 type ExplainTextReq struct {
 	Text string `json:"text"`
+	TextAnchor
 }
 
 type SimplifyTextReq struct {
 	Text string `json:"text"`
+	TextAnchor
+}
+
+// Superadmin variants of the assistant requests, used to try other model
+// settings without touching the configuration.
+type adminExplainTextReq struct {
+	ExplainTextReq
+	ModelSettings *adapters.LLMTaskSettingsOverride `json:"model_settings"`
+}
+
+type adminSimplifyTextReq struct {
+	SimplifyTextReq
+	ModelSettings *adapters.LLMTaskSettingsOverride `json:"model_settings"`
+}
+
+type startChatReq struct {
+	ArticleId  string `json:"article_id"`
+	Difficulty string `json:"difficulty"`
+	Question   string `json:"question"`
+}
+
+type continueChatReq struct {
+	Question string `json:"question"`
+}
+
+type getHistoryReq struct {
+	Query         string
+	Kind          string
+	FavouriteOnly bool
+	Cursor        string
+	PageSize      uint
+}
+
+type favouriteHistoryEntryReq struct {
+	IsFavourite bool `json:"is_favourite"`
 }
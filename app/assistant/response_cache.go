@@ -0,0 +1,117 @@
+package assistant
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lexica-app/lexicapi/adapters"
+)
+
+type CachedResponse struct {
+	Key           []byte           `json:"-"`
+	Task          adapters.LLMTask `json:"task"`
+	PromptVersion string           `json:"prompt_version"`
+	Model         string           `json:"model"`
+	InputText     string           `json:"input_text"`
+	OutputText    string           `json:"output_text"`
+	CreatedAt     time.Time        `json:"created_at"`
+	ExpiresAt     time.Time        `json:"expires_at"`
+}
+
+// Hit and miss counters since the process started, reported by the admin
+// cache endpoint next to what's stored.
+var responseCacheHits, responseCacheMisses atomic.Int64
+
+func NewCachedResponse(task adapters.LLMTask, promptVersion, model, inputText, outputText string) CachedResponse {
+	now := time.Now()
+
+	return CachedResponse{
+		Key:           responseCacheKey(task, promptVersion, model, inputText),
+		Task:          task,
+		PromptVersion: promptVersion,
+		Model:         model,
+		InputText:     normalizeCacheInput(inputText),
+		OutputText:    outputText,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(responseCacheTtl),
+	}
+}
+
+func (c CachedResponse) IsExpired() bool {
+	return !time.Now().Before(c.ExpiresAt)
+}
+
+// responseCacheKey addresses an answer by everything that shapes it, so
+// activating another prompt version or model naturally misses the old entries.
+func responseCacheKey(task adapters.LLMTask, promptVersion, model, inputText string) []byte {
+	h := sha256.New()
+	for _, part := range []string{string(task), promptVersion, model, normalizeCacheInput(inputText)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return h.Sum(nil)
+}
+
+// normalizeCacheInput collapses whitespace so the same sentence highlighted
+// across a line break still hits the cache.
+func normalizeCacheInput(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+type responseMemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+func (c *responseMemoryCache) get(key []byte) (CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, isFound := c.entries[string(key)]
+	if !isFound {
+		return CachedResponse{}, false
+	}
+
+	entry := element.Value.(CachedResponse)
+	if entry.IsExpired() {
+		c.order.Remove(element)
+		delete(c.entries, string(key))
+		return CachedResponse{}, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry, true
+}
+
+func (c *responseMemoryCache) set(entry CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, isFound := c.entries[string(entry.Key)]; isFound {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[string(entry.Key)] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, string(oldest.Value.(CachedResponse).Key))
+	}
+}
+
+func (c *responseMemoryCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
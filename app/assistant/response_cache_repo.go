@@ -0,0 +1,101 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrCachedResponseDoesNotExist = errors.New("Cached response does not exist")
+)
+
+func findCachedResponseByKey(ctx context.Context, tx pgx.Tx, key []byte) (c CachedResponse, err error) {
+	q := "SELECT * FROM assistant_response_cache WHERE key = $1 AND expires_at > NOW()"
+
+	if err = pgxscan.Get(ctx, tx, &c, q, key); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return c, ErrCachedResponseDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find cached response by key")
+		return
+	}
+
+	return c, nil
+}
+
+func saveCachedResponse(ctx context.Context, tx pgx.Tx, c CachedResponse) (err error) {
+	q := `
+	INSERT INTO assistant_response_cache(key, task, prompt_version, model, input_text, output_text, created_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT(key)
+	DO UPDATE SET output_text = $6, created_at = $7, expires_at = $8
+	`
+
+	if _, err = tx.Exec(
+		ctx,
+		q,
+		c.Key,
+		c.Task,
+		c.PromptVersion,
+		c.Model,
+		c.InputText,
+		c.OutputText,
+		c.CreatedAt,
+		c.ExpiresAt,
+	); err != nil {
+		log.Err(err).Msg("Failed to save cached response")
+		return
+	}
+
+	return nil
+}
+
+// deleteCachedResponses removes the entries of a task and prompt version, an
+// empty filter matches everything.
+func deleteCachedResponses(ctx context.Context, tx pgx.Tx, task, promptVersion string) (deleted int64, err error) {
+	q := `
+	DELETE FROM assistant_response_cache
+	WHERE ($1 = '' OR task = $1) AND ($2 = '' OR prompt_version = $2)
+	`
+
+	tag, err := tx.Exec(ctx, q, task, promptVersion)
+	if err != nil {
+		log.Err(err).Msg("Failed to delete cached responses")
+		return
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func deleteExpiredCachedResponses(ctx context.Context, tx pgx.Tx) (deleted int64, err error) {
+	tag, err := tx.Exec(ctx, "DELETE FROM assistant_response_cache WHERE expires_at <= NOW()")
+	if err != nil {
+		log.Err(err).Msg("Failed to delete expired cached responses")
+		return
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func findCachedResponseCounts(ctx context.Context, tx pgx.Tx) (counts []*CachedResponseCount, err error) {
+	q := `
+	SELECT task, prompt_version, COUNT(*) entries, MAX(created_at) last_stored_at
+	FROM assistant_response_cache
+	WHERE expires_at > NOW()
+	GROUP BY task, prompt_version
+	ORDER BY task, prompt_version
+	`
+
+	counts = []*CachedResponseCount{}
+	if err = pgxscan.Select(ctx, tx, &counts, q); err != nil {
+		log.Err(err).Msg("Failed to find cached response counts")
+		return
+	}
+
+	return counts, nil
+}
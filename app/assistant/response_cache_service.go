@@ -0,0 +1,249 @@
+package assistant
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/lexica-app/lexicapi/app/moderation"
+	"github.com/lexica-app/lexicapi/app/prompt"
+	"github.com/lexica-app/lexicapi/app/usage"
+	"github.com/rs/zerolog/log"
+)
+
+const responseCachePurgeInterval = time.Hour
+
+// completeWithCache answers from the response cache when it can and stores
+// fresh answers otherwise. The cache only speeds things up, so failing to read
+// or write it is logged and never fails the request.
+func completeWithCache(ctx context.Context, task adapters.LLMTask, settings adapters.LLMTaskSettings, rendered prompt.RenderedPrompt, inputText string, useCache bool) (content string, err error) {
+	if !useCache {
+		return complete(ctx, task, settings, rendered)
+	}
+
+	key := responseCacheKey(task, rendered.Ref, settings.Model, inputText)
+	logFields := map[string]any{
+		"task":   task,
+		"prompt": rendered.Ref,
+		"model":  settings.Model,
+		"key":    hex.EncodeToString(key[:8]),
+	}
+
+	if cached, source, isFound := getCachedResponse(ctx, key); isFound {
+		responseCacheHits.Add(1)
+		logFields["source"] = source
+		log.Info().Fields(logFields).Msg("Assistant response cache hit")
+		return cached.OutputText, nil
+	}
+
+	responseCacheMisses.Add(1)
+	log.Info().Fields(logFields).Msg("Assistant response cache miss")
+
+	content, err = complete(ctx, task, settings, rendered)
+	if err != nil {
+		return
+	}
+
+	storeCachedResponse(ctx, NewCachedResponse(task, rendered.Ref, settings.Model, inputText, content))
+
+	return content, nil
+}
+
+func complete(ctx context.Context, task adapters.LLMTask, settings adapters.LLMTaskSettings, rendered prompt.RenderedPrompt) (content string, err error) {
+	res, err := usage.Complete(ctx, llmAdapter, task, settings.Request(
+		adapters.LLMMessage{Role: adapters.LLMRoleSystem, Content: rendered.System},
+		adapters.LLMMessage{Role: adapters.LLMRoleUser, Content: rendered.User},
+	))
+	if err != nil {
+		return
+	}
+
+	log.Info().Fields(map[string]any{
+		"provider":      llmAdapter.Provider(),
+		"task":          task,
+		"prompt":        rendered.Ref,
+		"id":            res.Id,
+		"model":         res.Model,
+		"usage":         res.Usage,
+		"finish_reason": res.FinishReason,
+	}).Msg("LLM - Assistant Request")
+
+	recordQuotaTokens(ctx, res.Usage.TotalTokens)
+
+	// Checked before caching so a flagged reply is never served again
+	if err = moderation.Check(ctx, moderation.Subject{Kind: moderation.ASSISTANT_REPLY, Text: res.Content}).Err(); err != nil {
+		return
+	}
+
+	return res.Content, nil
+}
+
+func getCachedResponse(ctx context.Context, key []byte) (c CachedResponse, source string, isFound bool) {
+	if memoryCache != nil {
+		if c, isFound = memoryCache.get(key); isFound {
+			return c, "memory", true
+		}
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get cached response")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	c, err = findCachedResponseByKey(ctx, tx, key)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get cached response")
+		return
+	}
+
+	if memoryCache != nil {
+		memoryCache.set(c)
+	}
+
+	return c, "database", true
+}
+
+func storeCachedResponse(ctx context.Context, c CachedResponse) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to store cached response")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = saveCachedResponse(ctx, tx, c); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to store cached response")
+		return
+	}
+
+	if memoryCache != nil {
+		memoryCache.set(c)
+	}
+}
+
+func getResponseCacheStats(ctx context.Context) (stats ResponseCacheStats, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get response cache stats")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	counts, err := findCachedResponseCounts(ctx, tx)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get response cache stats")
+		return
+	}
+
+	stats = ResponseCacheStats{
+		Hits:     responseCacheHits.Load(),
+		Misses:   responseCacheMisses.Load(),
+		TtlHours: int(responseCacheTtl / time.Hour),
+		Counts:   counts,
+	}
+	if memoryCache != nil {
+		stats.MemorySize = memoryCache.capacity
+	}
+
+	return stats, nil
+}
+
+// invalidateResponseCache drops stored answers of a task and prompt version,
+// both optional. The in-memory LRU is cleared entirely since it's cheap to
+// refill from the database.
+func invalidateResponseCache(ctx context.Context, task, promptVersion string) (deleted int64, err error) {
+	if task != "" {
+		if err = validateResponseCacheTask(task); err != nil {
+			return
+		}
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to invalidate response cache")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	deleted, err = deleteCachedResponses(ctx, tx, task, promptVersion)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to invalidate response cache")
+		return
+	}
+
+	if memoryCache != nil {
+		memoryCache.purge()
+	}
+
+	log.Info().Fields(map[string]any{
+		"task":           task,
+		"prompt_version": promptVersion,
+		"deleted":        deleted,
+	}).Msg("Invalidated assistant response cache")
+
+	return deleted, nil
+}
+
+func purgeExpiredResponseCache(ctx context.Context) (deleted int64, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to purge expired response cache")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	deleted, err = deleteExpiredCachedResponses(ctx, tx)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to purge expired response cache")
+		return
+	}
+
+	return deleted, nil
+}
+
+// StartResponseCachePurgeJob blocks, so it should be run in its own goroutine.
+func StartResponseCachePurgeJob(ctx context.Context) {
+	ticker := time.NewTicker(responseCachePurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := purgeExpiredResponseCache(ctx)
+		if err == nil && deleted > 0 {
+			log.Info().Int64("deleted", deleted).Msg("Purged expired assistant response cache")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package assistant
+
+import (
+	"github.com/jellydator/validation"
+	"github.com/lexica-app/lexicapi/adapters"
+)
+
+var (
+	ErrInvalidResponseCacheTask = validation.NewError("assistant:invalid_cache_task", "Invalid response cache task")
+)
+
+func validateResponseCacheTask(task string) error {
+	switch adapters.LLMTask(task) {
+	case adapters.LLMTaskSimplify, adapters.LLMTaskExplain:
+		return nil
+	}
+
+	return ErrInvalidResponseCacheTask
+}
@@ -3,6 +3,7 @@ package assistant
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/lexica-app/lexicapi/app/usage"
 )
 
 func Router() *chi.Mux {
@@ -10,8 +11,44 @@ func Router() *chi.Mux {
 
 	r.Use(auth.UserAuthMiddleware)
 
-	r.Post("/simplify", simplifyTextHandler)
-	r.Post("/explain", explainTextHandler)
+	r.Get("/quota", getQuotaStatusHandler)
+	r.Get("/define", defineWordHandler)
+	r.Get("/chat", getChatsHandler)
+	r.Get("/chat/{id}", getChatDetailHandler)
+	r.Delete("/chat/{id}", deleteChatHandler)
+	r.Get("/annotations", getAnnotationsHandler)
+	r.Get("/annotations/{id}", getAnnotationHandler)
+	r.Delete("/annotations/{id}", deleteAnnotationHandler)
+	r.Get("/history", getHistoryHandler)
+	r.Get("/history/{id}", getHistoryEntryHandler)
+	r.Patch("/history/{id}/favourite", favouriteHistoryEntryHandler)
+	r.Delete("/history/{id}", deleteHistoryEntryHandler)
+	r.Post("/history/{id}/card", historyEntryToCardHandler)
+	r.Post("/history/{id}/note", historyEntryToNoteHandler)
+
+	r.Group(func(r chi.Router) {
+		r.Use(usage.BudgetMiddleware)
+		r.Use(quotaMiddleware)
+
+		r.Post("/simplify", simplifyTextHandler)
+		r.Post("/explain", explainTextHandler)
+		r.Post("/chat", startChatHandler)
+		r.Post("/chat/{id}", continueChatHandler)
+	})
+
+	return r
+}
+
+func AdminRouter() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(auth.SuperadminAuthMiddleware)
+
+	r.With(usage.BudgetMiddleware).Post("/simplify", adminSimplifyTextHandler)
+	r.With(usage.BudgetMiddleware).Post("/explain", adminExplainTextHandler)
+
+	r.Get("/cache", getResponseCacheStatsHandler)
+	r.Delete("/cache", invalidateResponseCacheHandler)
 
 	return r
 }
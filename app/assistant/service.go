@@ -1,39 +1,99 @@
 package assistant
 
-import "context"
+import (
+	"context"
+
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/lexica-app/lexicapi/app/auth"
+)
+
+// simplifyText and explainText keep every answer in the user's history and
+// save anchored selections as annotations, superadmin experiments pass no
+// user and save nothing.
+func simplifyText(ctx context.Context, user *auth.User, body SimplifyTextReq, override *adapters.LLMTaskSettingsOverride) (s Simplication, err error) {
+	originalText, anchor, err := resolveSelection(ctx, body.Text, body.TextAnchor)
+	if err != nil {
+		return
+	}
 
-func simplifyText(ctx context.Context, originalText string) (s Simplication, err error) {
 	s, err = NewSimplification(originalText)
 	if err != nil {
 		return
 	}
 
-	simplifiedText, err := generateSimplifiedText(ctx, originalText)
+	settings, err := adapters.LLMTaskSettingsFor(adapters.LLMTaskSimplify).Override(override)
 	if err != nil {
 		return
 	}
 
-	if err = s.Simplify(simplifiedText); err != nil {
+	// Superadmin experiments skip the response cache so they always reach the model
+	simplifiedText, promptVersion, err := generateSimplifiedText(ctx, settings, originalText, anchor, override == nil)
+	if err != nil {
 		return
 	}
 
+	if err = s.Simplify(simplifiedText, promptVersion); err != nil {
+		return
+	}
+
+	if user == nil {
+		return s, nil
+	}
+
+	recordHistory(ctx, NewHistoryEntry(user.Id, SIMPLIFICATION, s.OriginalText, s.SimplifiedText, s.PromptVersion, anchor))
+
+	if anchor != nil {
+		annotation, err := annotate(ctx, NewAnnotation(user.Id, SIMPLIFICATION, *anchor, s.SimplifiedText, s.PromptVersion))
+		if err != nil {
+			return s, err
+		}
+
+		s.Annotation = &annotation
+	}
+
 	return s, nil
 }
 
-func explainText(ctx context.Context, body ExplainTextReq) (explained Explained, err error) {
-	explained, err = NewExplained(body.Text)
+func explainText(ctx context.Context, user *auth.User, body ExplainTextReq, override *adapters.LLMTaskSettingsOverride) (explained Explained, err error) {
+	text, anchor, err := resolveSelection(ctx, body.Text, body.TextAnchor)
 	if err != nil {
 		return
 	}
 
-	explanation, err := generateTextExplanation(ctx, body.Text)
+	explained, err = NewExplained(text)
 	if err != nil {
 		return
 	}
 
-	if err = explained.Explain(explanation); err != nil {
+	settings, err := adapters.LLMTaskSettingsFor(adapters.LLMTaskExplain).Override(override)
+	if err != nil {
+		return
+	}
+
+	// Superadmin experiments skip the response cache so they always reach the model
+	explanation, promptVersion, err := generateTextExplanation(ctx, settings, text, anchor, override == nil)
+	if err != nil {
+		return
+	}
+
+	if err = explained.Explain(explanation, promptVersion); err != nil {
 		return
 	}
 
+	if user == nil {
+		return explained, nil
+	}
+
+	recordHistory(ctx, NewHistoryEntry(user.Id, EXPLANATION, explained.Text, explained.Explanation, explained.PromptVersion, anchor))
+
+	if anchor != nil {
+		annotation, err := annotate(ctx, NewAnnotation(user.Id, EXPLANATION, *anchor, explained.Explanation, explained.PromptVersion))
+		if err != nil {
+			return explained, err
+		}
+
+		explained.Annotation = &annotation
+	}
+
 	return explained, nil
 }
@@ -1,8 +1,10 @@
 package assistant
 
 type Simplication struct {
-	OriginalText   string `json:"original_text"`
-	SimplifiedText string `json:"simplified_text"`
+	OriginalText   string      `json:"original_text"`
+	SimplifiedText string      `json:"simplified_text"`
+	PromptVersion  string      `json:"prompt_version"`
+	Annotation     *Annotation `json:"annotation,omitempty"`
 }
 
 func NewSimplification(originalText string) (s Simplication, err error) {
@@ -13,11 +15,12 @@ func NewSimplification(originalText string) (s Simplication, err error) {
 	return Simplication{OriginalText: originalText}, nil
 }
 
-func (s *Simplication) Simplify(simplifiedText string) (err error) {
+func (s *Simplication) Simplify(simplifiedText, promptVersion string) (err error) {
 	if err = validateSimplificationSimplifiedText(simplifiedText); err != nil {
 		return
 	}
 
 	s.SimplifiedText = simplifiedText
+	s.PromptVersion = promptVersion
 	return nil
 }
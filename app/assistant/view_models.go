@@ -0,0 +1,58 @@
+package assistant
+
+import (
+	"time"
+
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/lexica-app/lexicapi/app/dictionary"
+	"gopkg.in/guregu/null.v4"
+)
+
+type CachedResponseCount struct {
+	Task          adapters.LLMTask `json:"task"`
+	PromptVersion string           `json:"prompt_version"`
+	Entries       int64            `json:"entries"`
+	LastStoredAt  time.Time        `json:"last_stored_at"`
+}
+
+type ResponseCacheStats struct {
+	Hits       int64                  `json:"hits"`
+	Misses     int64                  `json:"misses"`
+	TtlHours   int                    `json:"ttl_hours"`
+	MemorySize int                    `json:"memory_size"`
+	Counts     []*CachedResponseCount `json:"counts"`
+}
+
+type ChatDetail struct {
+	Chat
+	Messages []*ChatMessage `json:"messages"`
+}
+
+// ChatExchange is one question and its answer, as added to a chat
+type ChatExchange struct {
+	Chat     Chat        `json:"chat"`
+	Question ChatMessage `json:"question"`
+	Answer   ChatMessage `json:"answer"`
+}
+
+type DefinitionSource string
+
+const (
+	DICTIONARY DefinitionSource = "DICTIONARY"
+	ASSISTANT  DefinitionSource = "ASSISTANT"
+)
+
+// WordDefinition holds either the dictionary entries of a word or, when the
+// dictionary doesn't have it, the assistant's explanation.
+type WordDefinition struct {
+	Word        string                 `json:"word"`
+	Source      DefinitionSource       `json:"source"`
+	Definition  *dictionary.Definition `json:"definition,omitempty"`
+	Explanation *Explained             `json:"explanation,omitempty"`
+}
+
+// History is a page of assistant history, the cursor points at the next page
+type History struct {
+	Cursor  null.String     `json:"cursor"`
+	Entries []*HistoryEntry `json:"entries"`
+}
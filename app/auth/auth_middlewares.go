@@ -12,7 +12,8 @@ import (
 type contextkey string
 
 const (
-	UserInfoCtx contextkey = "auth.userinfo"
+	UserInfoCtx       contextkey = "auth.userinfo"
+	SuperadminInfoCtx contextkey = "auth.superadmininfo"
 )
 
 var (
@@ -116,13 +117,16 @@ func SuperadminAuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		_, _, err = validateSuperadminAccessToken(tokenStr)
+		_, claims, err := validateSuperadminAccessToken(tokenStr)
 		if err != nil {
 			log.Debug().Err(err).Msg("Failed to validate superadmin access token")
 			app.WriteHttpError(w, http.StatusUnauthorized, ErrInvalidAccessToken)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		// The superadmin is identified by the email in the token subject
+		ctx := context.WithValue(r.Context(), SuperadminInfoCtx, claims.Subject)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -24,9 +24,75 @@ type Config struct {
 	ClientApplicationUrl string `mapstructure:"CLIENT_APPLICATION_URL"`
 	CMSApplicationUrl    string `mapstructure:"CMS_APPLICATION_URL"`
 
+	LLMProvider string `mapstructure:"LLM_PROVIDER"`
+
 	OpenAIOrganizationId string `mapstructure:"OPENAI_ORGANIZATION_ID"`
 	OpenAIAPIKey         string `mapstructure:"OPENAI_API_KEY"`
 
+	AzureOpenAIEndpoint   string `mapstructure:"AZURE_OPENAI_ENDPOINT"`
+	AzureOpenAIAPIKey     string `mapstructure:"AZURE_OPENAI_API_KEY"`
+	AzureOpenAIAPIVersion string `mapstructure:"AZURE_OPENAI_API_VERSION"`
+	AzureOpenAIDeployment string `mapstructure:"AZURE_OPENAI_DEPLOYMENT"`
+
+	LLMBaseUrl string `mapstructure:"LLM_BASE_URL"`
+	LLMAPIKey  string `mapstructure:"LLM_API_KEY"`
+
+	// Retries and circuit breaking of LLM calls, 0 uses the default
+	LLMMaxAttempts            int `mapstructure:"LLM_MAX_ATTEMPTS"`
+	LLMRetryBaseDelayMs       int `mapstructure:"LLM_RETRY_BASE_DELAY_MS"`
+	LLMRetryMaxDelayMs        int `mapstructure:"LLM_RETRY_MAX_DELAY_MS"`
+	LLMBreakerThreshold       int `mapstructure:"LLM_BREAKER_THRESHOLD"`
+	LLMBreakerCooldownSeconds int `mapstructure:"LLM_BREAKER_COOLDOWN_SECONDS"`
+
+	// Per task model settings, see adapters.ConfigureLLMTaskSettings for the format
+	LLMModel                  string `mapstructure:"LLM_MODEL"`
+	LLMArticleTextSettings    string `mapstructure:"LLM_ARTICLE_TEXT_SETTINGS"`
+	LLMSimplifySettings       string `mapstructure:"LLM_SIMPLIFY_SETTINGS"`
+	LLMExplainSettings        string `mapstructure:"LLM_EXPLAIN_SETTINGS"`
+	LLMArticleSummarySettings string `mapstructure:"LLM_ARTICLE_SUMMARY_SETTINGS"`
+	LLMChatSettings           string `mapstructure:"LLM_CHAT_SETTINGS"`
+
+	// LLM prices in USD per 1K tokens, see usage.ConfigurePrices for the format.
+	// A monthly budget of 0 means generation is never refused
+	LLMPrices           string  `mapstructure:"LLM_PRICES"`
+	LLMMonthlyBudgetUsd float64 `mapstructure:"LLM_MONTHLY_BUDGET_USD"`
+
+	// Moderation of generated text, the openai provider uses the OpenAI
+	// credentials above. Terms are comma separated, patterns are regular
+	// expressions
+	ModerationProvider        string `mapstructure:"MODERATION_PROVIDER"`
+	ModerationModel           string `mapstructure:"MODERATION_MODEL"`
+	ModerationBlockTerms      string `mapstructure:"MODERATION_BLOCK_TERMS"`
+	ModerationReviewTerms     string `mapstructure:"MODERATION_REVIEW_TERMS"`
+	ModerationBlockPattern    string `mapstructure:"MODERATION_BLOCK_PATTERN"`
+	ModerationReviewPattern   string `mapstructure:"MODERATION_REVIEW_PATTERN"`
+	ModerationBlockCategories string `mapstructure:"MODERATION_BLOCK_CATEGORIES"`
+	ModerationFailOpen        bool   `mapstructure:"MODERATION_FAIL_OPEN"`
+
+	TrashRetentionDays int `mapstructure:"TRASH_RETENTION_DAYS"`
+
+	ArticlePreviewSecret string `mapstructure:"ARTICLE_PREVIEW_SECRET"`
+
+	// Long article texts are adapted in parts of about this many tokens
+	ArticleChunkTokens      int `mapstructure:"ARTICLE_CHUNK_TOKENS"`
+	ArticleChunkConcurrency int `mapstructure:"ARTICLE_CHUNK_CONCURRENCY"`
+
+	// Generated texts failing the quality checks are regenerated up to this
+	// many attempts in total
+	ArticleQualityMaxAttempts int `mapstructure:"ARTICLE_QUALITY_MAX_ATTEMPTS"`
+
+	ResponseCacheSize       int `mapstructure:"RESPONSE_CACHE_SIZE"`
+	ResponseCacheTtlSeconds int `mapstructure:"RESPONSE_CACHE_TTL_SECONDS"`
+
+	AssistantCacheTtlHours   int `mapstructure:"ASSISTANT_CACHE_TTL_HOURS"`
+	AssistantCacheMemorySize int `mapstructure:"ASSISTANT_CACHE_MEMORY_SIZE"`
+
+	// Per role assistant quotas, see assistant.ConfigureQuotas for the format
+	AssistantQuotaTimezone string `mapstructure:"ASSISTANT_QUOTA_TIMEZONE"`
+	AssistantQuotaPelajar  string `mapstructure:"ASSISTANT_QUOTA_PELAJAR"`
+	AssistantQuotaPengajar string `mapstructure:"ASSISTANT_QUOTA_PENGAJAR"`
+	AssistantQuotaUmum     string `mapstructure:"ASSISTANT_QUOTA_UMUM"`
+
 	DbUrl  string `mapstructure:"DB_URL"`
 	DbHost string `mapstructure:"DB_HOST"`
 	DbPort string `mapstructure:"DB_PORT"`
@@ -9,6 +9,7 @@ import (
 var allowedOrigins []string
 var allowedMethods []string
 var allowedHeaders []string
+var exposedHeaders []string
 
 func ConfigureCors(c Config) {
 	allowedOrigins = []string{c.ClientApplicationUrl, c.CMSApplicationUrl}
@@ -25,6 +26,19 @@ func ConfigureCors(c Config) {
 		"Content-Type",
 		"X-Lexica-Api-Key",
 		"X-Google-Id-Token",
+		"If-Match",
+	}
+
+	// The CMS reads the ETag of an edited resource to send it back in If-Match
+	exposedHeaders = []string{
+		"ETag",
+		"Retry-After",
+		"X-Quota-Daily-Requests-Remaining",
+		"X-Quota-Daily-Tokens-Remaining",
+		"X-Quota-Daily-Reset",
+		"X-Quota-Monthly-Requests-Remaining",
+		"X-Quota-Monthly-Tokens-Remaining",
+		"X-Quota-Monthly-Reset",
 	}
 }
 
@@ -33,5 +47,6 @@ func CorsMiddleware(h http.Handler) http.Handler {
 		AllowedOrigins: allowedOrigins,
 		AllowedMethods: allowedMethods,
 		AllowedHeaders: allowedHeaders,
+		ExposedHeaders: exposedHeaders,
 	}).Handler(h)
 }
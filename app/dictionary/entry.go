@@ -0,0 +1,71 @@
+package dictionary
+
+import (
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Entry is one sense group of a lemma, a lemma has an entry per part of
+// speech.
+type Entry struct {
+	Id           ulid.ULID `json:"id"`
+	Lemma        string    `json:"lemma"`
+	PartOfSpeech string    `json:"part_of_speech"`
+	Definitions  []string  `json:"definitions"`
+	Examples     []string  `json:"examples"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    null.Time `json:"updated_at"`
+}
+
+// Definition is what a looked up word resolved to
+type Definition struct {
+	Word    string   `json:"word"`
+	Lemma   string   `json:"lemma"`
+	Entries []*Entry `json:"entries"`
+}
+
+func NewEntry(lemma, partOfSpeech string, definitions, examples []string) (Entry, map[string]error) {
+	errs := make(map[string]error)
+
+	lemma = normalizeWord(lemma)
+	partOfSpeech = strings.ToLower(strings.TrimSpace(partOfSpeech))
+	definitions = compactTexts(definitions)
+	examples = compactTexts(examples)
+
+	if err := validateLemma(lemma); err != nil {
+		errs["lemma"] = err
+	}
+	if err := validatePartOfSpeech(partOfSpeech); err != nil {
+		errs["part_of_speech"] = err
+	}
+	if err := validateDefinitions(definitions); err != nil {
+		errs["definitions"] = err
+	}
+	if len(errs) != 0 {
+		return Entry{}, errs
+	}
+
+	return Entry{
+		Id:           ulid.Make(),
+		Lemma:        lemma,
+		PartOfSpeech: partOfSpeech,
+		Definitions:  definitions,
+		Examples:     examples,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// compactTexts trims every text and drops the empty ones
+func compactTexts(texts []string) []string {
+	compacted := make([]string, 0, len(texts))
+	for _, t := range texts {
+		if t = strings.TrimSpace(t); t != "" {
+			compacted = append(compacted, t)
+		}
+	}
+
+	return compacted
+}
@@ -0,0 +1,61 @@
+package dictionary
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/jellydator/validation"
+)
+
+var (
+	ErrLemmaEmpty          = validation.NewError("dictionary:lemma_empty", "Lemma can't be empty")
+	ErrLemmaTooLong        = validation.NewError("dictionary:lemma_too_long", "Lemma can't be longer than 100 characters")
+	ErrPartOfSpeechTooLong = validation.NewError("dictionary:part_of_speech_too_long", "Part of speech can't be longer than 30 characters")
+	ErrDefinitionsEmpty    = validation.NewError("dictionary:definitions_empty", "An entry needs at least one definition")
+	ErrWordEmpty           = validation.NewError("dictionary:word_empty", "Word can't be empty")
+	ErrInvalidWord         = validation.NewError("dictionary:invalid_word", "Only a single word of up to 50 letters can be looked up")
+)
+
+func validateLemma(lemma string) error {
+	return validation.Validate(
+		&lemma,
+		validation.Required.ErrorObject(ErrLemmaEmpty),
+		validation.RuneLength(1, 100).ErrorObject(ErrLemmaTooLong),
+	)
+}
+
+func validatePartOfSpeech(partOfSpeech string) error {
+	return validation.Validate(
+		&partOfSpeech,
+		validation.RuneLength(0, 30).ErrorObject(ErrPartOfSpeechTooLong),
+	)
+}
+
+func validateDefinitions(definitions []string) error {
+	if len(definitions) == 0 {
+		return ErrDefinitionsEmpty
+	}
+
+	return nil
+}
+
+// validateWord accepts a single word, hyphenated reduplications included
+func validateWord(word string) error {
+	if word == "" {
+		return ErrWordEmpty
+	}
+	if len([]rune(word)) > 50 {
+		return ErrInvalidWord
+	}
+
+	for _, r := range word {
+		if !unicode.IsLetter(r) && r != '-' {
+			return ErrInvalidWord
+		}
+	}
+	if strings.Trim(word, "-") == "" {
+		return ErrInvalidWord
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package dictionary
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Stripping never leaves a root shorter than this
+const minRootLength = 3
+
+// Suffixes come off from the outside in: particles, then possessive pronouns,
+// then derivational suffixes.
+var (
+	particleSuffixes     = []string{"lah", "kah", "tah", "pun"}
+	possessiveSuffixes   = []string{"nya", "ku", "mu"}
+	derivationalSuffixes = []string{"kan", "an", "i"}
+)
+
+// prefixRule strips prefix when the rest starts with one of next, an empty
+// next matches anything. The nasal prefixes swallow the first consonant of
+// some roots (menulis from tulis), restore puts it back.
+type prefixRule struct {
+	prefix  string
+	next    string
+	restore string
+}
+
+const (
+	vowels = "aeiou"
+	// Roots that keep their first letter after me- and pe-, pe- before r is
+	// handled as per-
+	sonorants = "lmnrwy"
+)
+
+// Rules are tried in order and all of them that match are kept, so the
+// unchanged root (memakan from makan) is preferred over the restored one
+// (memukul from pukul).
+var prefixRules = []prefixRule{
+	{prefix: "me", next: sonorants},
+	{prefix: "meny", next: vowels, restore: "s"},
+	{prefix: "menge", next: ""},
+	{prefix: "meng", next: vowels + "gh"},
+	{prefix: "meng", next: vowels, restore: "k"},
+	{prefix: "mem", next: "bfpv"},
+	{prefix: "mem", next: vowels, restore: "p"},
+	{prefix: "men", next: "cdjsz"},
+	{prefix: "men", next: vowels, restore: "t"},
+	{prefix: "pe", next: "lmnwy"},
+	{prefix: "peny", next: vowels, restore: "s"},
+	{prefix: "penge", next: ""},
+	{prefix: "peng", next: vowels + "gh"},
+	{prefix: "peng", next: vowels, restore: "k"},
+	{prefix: "pem", next: "bfpv"},
+	{prefix: "pem", next: vowels, restore: "p"},
+	{prefix: "pen", next: "cdjsz"},
+	{prefix: "pen", next: vowels, restore: "t"},
+	{prefix: "per", next: ""},
+	{prefix: "ber", next: ""},
+	{prefix: "bel", next: "a"},
+	{prefix: "be", next: "bcdfghjkmnpstvz"},
+	{prefix: "ter", next: ""},
+	{prefix: "di", next: ""},
+	{prefix: "ke", next: ""},
+	{prefix: "se", next: ""},
+}
+
+// normalizeWord lowercases a word and drops the punctuation around it
+func normalizeWord(word string) string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	return strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// lemmaCandidates lists the forms a word may come from, most likely first,
+// starting with the word itself. It overgenerates on purpose, the dictionary
+// decides which candidates are real lemmas.
+func lemmaCandidates(word string) []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(c string) {
+		if c != "" && !seen[c] {
+			seen[c] = true
+			candidates = append(candidates, c)
+		}
+	}
+
+	words := []string{word}
+	if base, isReduplicated := reduplicationBase(word); isReduplicated {
+		words = append(words, base)
+	}
+
+	for _, w := range words {
+		for _, s := range stripSuffixes(w) {
+			add(s)
+			for _, p := range stripPrefixes(s) {
+				add(p)
+				// Prefixes stack once, as in memper- and diper-
+				for _, pp := range stripPrefixes(p) {
+					add(pp)
+				}
+			}
+		}
+	}
+
+	return candidates
+}
+
+// reduplicationBase turns buku-buku into buku and sayur-mayur into sayur
+func reduplicationBase(word string) (string, bool) {
+	first, second, isFound := strings.Cut(word, "-")
+	if !isFound || first == "" || second == "" {
+		return "", false
+	}
+
+	return first, true
+}
+
+// stripSuffixes returns the word followed by what's left after taking off
+// each layer of suffixes in turn.
+func stripSuffixes(word string) []string {
+	forms := []string{word}
+	for _, layer := range [][]string{particleSuffixes, possessiveSuffixes, derivationalSuffixes} {
+		for _, suffix := range layer {
+			if root := strings.TrimSuffix(word, suffix); root != word && len([]rune(root)) >= minRootLength {
+				word = root
+				forms = append(forms, root)
+				break
+			}
+		}
+	}
+
+	return forms
+}
+
+func stripPrefixes(word string) []string {
+	var roots []string
+	for _, rule := range prefixRules {
+		rest := strings.TrimPrefix(word, rule.prefix)
+		if rest == word || rest == "" {
+			continue
+		}
+		if rule.next != "" && !strings.ContainsRune(rule.next, []rune(rest)[0]) {
+			continue
+		}
+
+		if root := rule.restore + rest; len([]rune(root)) >= minRootLength {
+			roots = append(roots, root)
+		}
+	}
+
+	return roots
+}
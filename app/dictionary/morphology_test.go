@@ -0,0 +1,61 @@
+package dictionary
+
+import "testing"
+
+func TestLemmaCandidates(t *testing.T) {
+	tests := []struct {
+		word string
+		// lemma has to be among the candidates, after preferred when it's set
+		lemma     string
+		preferred string
+	}{
+		{word: "buku", lemma: "buku"},
+		{word: "bukunya", lemma: "buku"},
+		{word: "buku-buku", lemma: "buku"},
+		{word: "bermain", lemma: "main"},
+		{word: "menulis", lemma: "tulis"},
+		{word: "menyapu", lemma: "sapu"},
+		{word: "memukul", lemma: "pukul"},
+		{word: "memakan", lemma: "pakan", preferred: "makan"},
+		{word: "pengajar", lemma: "ajar"},
+		{word: "dimakannya", lemma: "makan"},
+		{word: "mempermainkan", lemma: "main"},
+		{word: "bacalah", lemma: "baca"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			candidates := lemmaCandidates(tt.word)
+			if len(candidates) == 0 || candidates[0] != tt.word {
+				t.Fatalf("lemmaCandidates(%q) = %q, want the word itself first", tt.word, candidates)
+			}
+
+			position := make(map[string]int)
+			for i, c := range candidates {
+				if _, isSeen := position[c]; isSeen {
+					t.Fatalf("lemmaCandidates(%q) = %q, %q is listed twice", tt.word, candidates, c)
+				}
+				if len([]rune(c)) < minRootLength {
+					t.Errorf("lemmaCandidates(%q) = %q, %q is shorter than a root", tt.word, candidates, c)
+				}
+				position[c] = i
+			}
+
+			lemmaAt, isFound := position[tt.lemma]
+			if !isFound {
+				t.Fatalf("lemmaCandidates(%q) = %q, want %q among them", tt.word, candidates, tt.lemma)
+			}
+			if tt.preferred != "" {
+				if preferredAt, isFound := position[tt.preferred]; !isFound || preferredAt > lemmaAt {
+					t.Errorf("lemmaCandidates(%q) = %q, want %q before %q", tt.word, candidates, tt.preferred, tt.lemma)
+				}
+			}
+		})
+	}
+}
+
+func TestLemmaCandidatesKeepShortWords(t *testing.T) {
+	if got := lemmaCandidates("buku"); len(got) != 1 {
+		t.Errorf("lemmaCandidates(%q) = %q, want only the word since -ku would leave too short a root", "buku", got)
+	}
+}
@@ -0,0 +1,51 @@
+package dictionary
+
+import (
+	"context"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// findEntriesByLemmas returns the entries of the first lemma in lemmas that
+// the dictionary has.
+func findEntriesByLemmas(ctx context.Context, tx pgx.Tx, lemmas []string) (entries []*Entry, err error) {
+	q := `
+	WITH found AS (
+		SELECT lemma FROM dictionary_entries
+		WHERE lemma = ANY($1::TEXT[])
+		ORDER BY array_position($1::TEXT[], lemma::TEXT)
+		LIMIT 1
+	)
+	SELECT e.* FROM dictionary_entries e
+	INNER JOIN found f ON f.lemma = e.lemma
+	ORDER BY e.part_of_speech ASC, e.id ASC
+	`
+
+	if err = pgxscan.Select(ctx, tx, &entries, q, lemmas); err != nil {
+		log.Err(err).Msg("Failed to find dictionary entries by lemmas")
+		return
+	}
+
+	return entries, nil
+}
+
+// upsertEntry replaces the definitions and examples of a lemma and part of
+// speech that was imported before.
+func upsertEntry(ctx context.Context, tx pgx.Tx, e Entry) (isInserted bool, err error) {
+	q := `
+	INSERT INTO dictionary_entries(id, lemma, part_of_speech, definitions, examples, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (lemma, part_of_speech) DO UPDATE
+	SET definitions = EXCLUDED.definitions, examples = EXCLUDED.examples, updated_at = NOW()
+	RETURNING (xmax = 0)
+	`
+
+	if err = tx.QueryRow(ctx, q, e.Id, e.Lemma, e.PartOfSpeech, e.Definitions, e.Examples, e.CreatedAt).Scan(&isInserted); err != nil {
+		log.Err(err).Str("lemma", e.Lemma).Msg("Failed to upsert dictionary entry")
+		return
+	}
+
+	return isInserted, nil
+}
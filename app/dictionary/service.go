@@ -0,0 +1,163 @@
+package dictionary
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrWordNotFound = errors.New("Word is not in the dictionary")
+
+// Define looks the word up by its lemma, trying the word as written first and
+// then with its affixes stripped.
+func Define(ctx context.Context, word string) (d Definition, err error) {
+	word = normalizeWord(word)
+	if err = validateWord(word); err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to define word")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	entries, err := findEntriesByLemmas(ctx, tx, lemmaCandidates(word))
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to define word")
+		return
+	}
+
+	if len(entries) == 0 {
+		return d, ErrWordNotFound
+	}
+
+	return Definition{Word: word, Lemma: entries[0].Lemma, Entries: entries}, nil
+}
+
+// importLine is one line of a dictionary dataset in JSON Lines
+type importLine struct {
+	Lemma        string   `json:"lemma"`
+	PartOfSpeech string   `json:"pos"`
+	Definitions  []string `json:"definitions"`
+	Examples     []string `json:"examples"`
+}
+
+type ImportLineError struct {
+	Line   int
+	Errors map[string]error
+}
+
+func (e ImportLineError) Error() string {
+	fields := make([]string, 0, len(e.Errors))
+	for field, err := range e.Errors {
+		fields = append(fields, field+": "+err.Error())
+	}
+	sort.Strings(fields)
+
+	return fmt.Sprintf("line %d: %s", e.Line, strings.Join(fields, ", "))
+}
+
+type ImportResult struct {
+	Valid    int
+	Inserted int
+	Updated  int
+	Invalid  []ImportLineError
+}
+
+// Import reads a dataset with one JSON object per line:
+//
+//	{"lemma": "makan", "pos": "v", "definitions": ["memasukkan makanan ke dalam mulut"], "examples": ["ia makan nasi"]}
+//
+// Entries are keyed by lemma and part of speech, importing one again replaces
+// its definitions and examples. Invalid lines are reported and skipped, a dry
+// run only checks the dataset and doesn't touch the database.
+func Import(ctx context.Context, r io.Reader, isDryRun bool) (result ImportResult, err error) {
+	entries, invalid, err := parseDataset(r)
+	if err != nil {
+		return
+	}
+
+	result.Valid = len(entries)
+	result.Invalid = invalid
+	if isDryRun {
+		return result, nil
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to import dictionary")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	for _, entry := range entries {
+		isInserted, err := upsertEntry(ctx, tx, entry)
+		if err != nil {
+			return result, err
+		}
+
+		if isInserted {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to import dictionary")
+		return
+	}
+
+	return result, nil
+}
+
+func parseDataset(r io.Reader) (entries []Entry, invalid []ImportLineError, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var line importLine
+		if err := json.Unmarshal([]byte(text), &line); err != nil {
+			invalid = append(invalid, ImportLineError{Line: lineNumber, Errors: map[string]error{"json": err}})
+			continue
+		}
+
+		entry, errs := NewEntry(line.Lemma, line.PartOfSpeech, line.Definitions, line.Examples)
+		if len(errs) != 0 {
+			invalid = append(invalid, ImportLineError{Line: lineNumber, Errors: errs})
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = scanner.Err(); err != nil {
+		log.Err(err).Msg("Failed to read dictionary dataset")
+		return
+	}
+
+	return entries, invalid, nil
+}
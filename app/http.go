@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+
+	"github.com/lexica-app/lexicapi/adapters"
 )
 
 var (
@@ -41,6 +43,18 @@ func WriteHttpInternalServerError(w http.ResponseWriter) {
 	WriteHttpError(w, http.StatusInternalServerError, ErrInternalServerError)
 }
 
+// WriteHttpLLMError answers with the status of a failed LLM call, or with an
+// internal server error when err is anything else.
+func WriteHttpLLMError(w http.ResponseWriter, err error) {
+	status, ok := adapters.LLMErrorStatus(err)
+	if !ok {
+		WriteHttpInternalServerError(w)
+		return
+	}
+
+	WriteHttpError(w, status, err)
+}
+
 func Heartbeat(w http.ResponseWriter, r *http.Request) {
 	WriteHttpBodyJson(w, http.StatusOK, map[string]string{"message": "OK!"})
 }
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrIfMatchRequired = errors.New("If-Match header with the version being edited is required")
+	ErrInvalidIfMatch  = errors.New("If-Match header must hold a single resource version")
+)
+
+// VersionETag formats a resource version the way clients send it back in
+// If-Match.
+func VersionETag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// IfMatchVersion reads the resource version a client based its edit on.
+// Both the quoted ETag form and a bare number are accepted.
+func IfMatchVersion(r *http.Request) (uint, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		return 0, ErrIfMatchRequired
+	}
+
+	version, err := strconv.ParseUint(strings.Trim(ifMatch, `"`), 10, 32)
+	if err != nil || version == 0 {
+		return 0, ErrInvalidIfMatch
+	}
+
+	return uint(version), nil
+}
+
+// WriteHttpIfMatchError answers requests whose If-Match header is missing or
+// malformed.
+func WriteHttpIfMatchError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrIfMatchRequired) {
+		WriteHttpError(w, http.StatusPreconditionRequired, err)
+		return
+	}
+
+	WriteHttpError(w, http.StatusBadRequest, err)
+}
+
+// WriteHttpBodyJsonVersioned writes body with the ETag of its version so the
+// next edit can be sent with a matching If-Match.
+func WriteHttpBodyJsonVersioned(w http.ResponseWriter, status int, body any, version uint) {
+	w.Header().Set("ETag", VersionETag(version))
+	WriteHttpBodyJson(w, status, body)
+}
+
+// WriteHttpPreconditionFailed rejects an edit based on a stale version and
+// hands back the current state so the client can merge and retry.
+func WriteHttpPreconditionFailed(w http.ResponseWriter, err error, current any, version uint) {
+	w.Header().Set("ETag", VersionETag(version))
+	WriteHttpBodyJson(w, http.StatusPreconditionFailed, map[string]any{
+		"message": err.Error(),
+		"current": current,
+	})
+}
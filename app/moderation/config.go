@@ -0,0 +1,113 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// Provider categories that block outright, anything else the provider flags
+// is held for review.
+const defaultBlockCategories = "sexual,sexual/minors,hate/threatening,self-harm,violence/graphic"
+
+var (
+	pool            *pgxpool.Pool
+	moderator       adapters.Moderator
+	rules           = defaultRules()
+	blockCategories = parseList(defaultBlockCategories)
+	isFailOpen      bool
+	approveHooks    = make(map[SubjectKind]ReviewHook)
+	rejectHooks     = make(map[SubjectKind]ReviewHook)
+
+	ErrNilPool      = errors.New("connection pool can't be nil")
+	ErrNilModerator = errors.New("moderator can't be nil")
+)
+
+// ReviewHook acts on the subject of a reviewed flag inside the transaction the
+// review is saved in. It's given the subject id and the hash of the text the
+// flag was raised on, see ContentHash.
+type ReviewHook func(ctx context.Context, tx pgx.Tx, subjectId ulid.ULID, subjectHash string) error
+
+func SetPool(newPool *pgxpool.Pool) {
+	if newPool == nil {
+		log.Fatal().Err(ErrNilPool).Msg("Failed to set connection pool for moderation module")
+	}
+
+	pool = newPool
+}
+
+// SetModerator is optional, without a provider only the local rules apply.
+func SetModerator(newModerator adapters.Moderator) {
+	if newModerator == nil {
+		log.Fatal().Err(ErrNilModerator).Msg("Failed to set moderator for moderation module")
+	}
+
+	moderator = newModerator
+}
+
+// ConfigureRules adds comma separated terms and a regular expression for each
+// decision on top of the built-in rules. Empty block categories keep the
+// defaults. Fail open lets text through when the provider can't be reached,
+// otherwise it's held for review.
+func ConfigureRules(blockTerms, reviewTerms, blockPattern, reviewPattern, categories string, failOpen bool) {
+	configured := defaultRules()
+
+	for _, r := range []struct {
+		name     string
+		source   ReasonSource
+		decision Decision
+		expr     string
+	}{
+		{"block_terms", KEYWORD, BLOCK, termsPattern(parseList(blockTerms))},
+		{"review_terms", KEYWORD, REVIEW, termsPattern(parseList(reviewTerms))},
+		{"block_pattern", PATTERN, BLOCK, strings.TrimSpace(blockPattern)},
+		{"review_pattern", PATTERN, REVIEW, strings.TrimSpace(reviewPattern)},
+	} {
+		if r.expr == "" {
+			continue
+		}
+
+		pattern, err := regexp.Compile(r.expr)
+		if err != nil {
+			log.Fatal().Err(err).Str("rule", r.name).Msg("Failed to configure moderation rules")
+		}
+
+		configured = append(configured, rule{Name: r.name, Source: r.source, Decision: r.decision, Pattern: pattern})
+	}
+
+	rules = configured
+	if categories = strings.TrimSpace(categories); categories != "" {
+		blockCategories = parseList(categories)
+	}
+	isFailOpen = failOpen
+}
+
+// OnApprove registers what happens to a kind of subject once its flag is
+// approved, kinds without a hook are only marked.
+func OnApprove(kind SubjectKind, hook ReviewHook) {
+	approveHooks[kind] = hook
+}
+
+// OnReject registers what happens to a kind of subject once its flag is
+// rejected, kinds without a hook are only marked.
+func OnReject(kind SubjectKind, hook ReviewHook) {
+	rejectHooks[kind] = hook
+}
+
+func parseList(s string) map[string]bool {
+	items := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items[item] = true
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,108 @@
+package moderation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+type SubjectKind string
+
+const (
+	ARTICLE_TEXT    SubjectKind = "ARTICLE_TEXT"
+	ASSISTANT_REPLY SubjectKind = "ASSISTANT_REPLY"
+	CHAT_REPLY      SubjectKind = "CHAT_REPLY"
+)
+
+type FlagStatus string
+
+const (
+	// Blocked text never got out, the flag is only the record of it
+	BLOCKED  FlagStatus = "BLOCKED"
+	PENDING  FlagStatus = "PENDING"
+	APPROVED FlagStatus = "APPROVED"
+	REJECTED FlagStatus = "REJECTED"
+)
+
+// Subject is the text being moderated and what it belongs to, Id is nil for
+// text that isn't saved anywhere.
+type Subject struct {
+	Kind SubjectKind
+	Id   *ulid.ULID
+	Text string
+}
+
+// Flag is the audit record of a text moderation didn't allow, and when held
+// for review, the review of it.
+type Flag struct {
+	Id          ulid.ULID   `json:"id"`
+	SubjectKind SubjectKind `json:"subject_kind"`
+	SubjectId   *ulid.ULID  `json:"subject_id"`
+	UserId      *ulid.ULID  `json:"user_id"`
+	Admin       null.String `json:"admin"`
+	Provider    null.String `json:"provider"`
+	Text        string      `json:"text"`
+	SubjectHash string      `json:"subject_hash"`
+	Decision    Decision    `json:"decision"`
+	Reasons     []Reason    `json:"reasons"`
+	Status      FlagStatus  `json:"status"`
+	ReviewedBy  null.String `json:"reviewed_by"`
+	ReviewNote  null.String `json:"review_note"`
+	ReviewedAt  null.Time   `json:"reviewed_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+func NewFlag(subject Subject, verdict Verdict, provider string) Flag {
+	status := PENDING
+	if verdict.Decision == BLOCK {
+		status = BLOCKED
+	}
+
+	return Flag{
+		Id:          ulid.Make(),
+		SubjectKind: subject.Kind,
+		SubjectId:   subject.Id,
+		Provider:    null.NewString(provider, provider != ""),
+		Text:        subject.Text,
+		SubjectHash: ContentHash(subject.Text),
+		Decision:    verdict.Decision,
+		Reasons:     verdict.Reasons,
+		Status:      status,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// ContentHash identifies the text a flag was raised on. Subjects keep their id
+// when their text is replaced, so hooks compare it before acting on a review.
+func ContentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Review settles a pending flag, only flags held for review can be reviewed
+// and only once.
+func (f *Flag) Review(status FlagStatus, reviewer string, note null.String) map[string]error {
+	errs := make(map[string]error)
+
+	if f.Status != PENDING {
+		errs["status"] = ErrFlagAlreadySettled
+	} else if err := validateReviewStatus(status); err != nil {
+		errs["status"] = err
+	}
+	if err := validateReviewNote(note); err != nil {
+		errs["note"] = err
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+
+	f.Status = status
+	f.ReviewedBy = null.StringFrom(reviewer)
+	f.ReviewNote = null.NewString(note.String, note.Valid && note.String != "")
+	f.ReviewedAt = null.TimeFrom(time.Now())
+
+	return nil
+}
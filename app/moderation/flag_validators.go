@@ -0,0 +1,59 @@
+package moderation
+
+import (
+	"github.com/jellydator/validation"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrInvalidFlagId       = validation.NewError("moderation:invalid_flag_id", "Invalid flag id")
+	ErrInvalidFlagStatus   = validation.NewError("moderation:invalid_status", "Flag status can only be BLOCKED, PENDING, APPROVED or REJECTED")
+	ErrInvalidSubjectKind  = validation.NewError("moderation:invalid_subject_kind", "Subject kind can only be ARTICLE_TEXT, ASSISTANT_REPLY or CHAT_REPLY")
+	ErrInvalidReviewStatus = validation.NewError("moderation:invalid_review_status", "A flag can only be reviewed as APPROVED or REJECTED")
+	ErrFlagAlreadySettled  = validation.NewError("moderation:already_settled", "Only flags pending review can be reviewed")
+	ErrReviewNoteTooLong   = validation.NewError("moderation:note_too_long", "Review note can't be longer than 1000 characters")
+)
+
+func validateFlagId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidFlagId
+	}
+
+	return id, nil
+}
+
+func validateFlagStatus(status FlagStatus) error {
+	switch status {
+	case BLOCKED, PENDING, APPROVED, REJECTED:
+		return nil
+	}
+
+	return ErrInvalidFlagStatus
+}
+
+func validateSubjectKind(kind SubjectKind) error {
+	switch kind {
+	case ARTICLE_TEXT, ASSISTANT_REPLY, CHAT_REPLY:
+		return nil
+	}
+
+	return ErrInvalidSubjectKind
+}
+
+func validateReviewStatus(status FlagStatus) error {
+	switch status {
+	case APPROVED, REJECTED:
+		return nil
+	}
+
+	return ErrInvalidReviewStatus
+}
+
+func validateReviewNote(note null.String) error {
+	return validation.Validate(
+		&note.String,
+		validation.RuneLength(0, 1000).ErrorObject(ErrReviewNoteTooLong),
+	)
+}
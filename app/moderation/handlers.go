@@ -0,0 +1,92 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+)
+
+func getFlagsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	status := r.URL.Query().Get("status")
+	kind := r.URL.Query().Get("subject_kind")
+	limit := r.URL.Query().Get("limit")
+
+	flags, err := getFlags(ctx, status, kind, limit)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidFlagStatus), errors.As(err, &ErrInvalidSubjectKind):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, flags)
+}
+
+func getFlagHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	flag, err := getFlag(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		writeFlagError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, flag)
+}
+
+func reviewFlagHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	admin, ok := ctx.Value(auth.SuperadminInfoCtx).(string)
+	if !ok {
+		app.WriteHttpError(w, http.StatusUnauthorized, auth.ErrInvalidAccessToken)
+		return
+	}
+
+	var body reviewFlagReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	flag, errs, err := reviewFlag(ctx, admin, chi.URLParam(r, "id"), body)
+	if errs != nil {
+		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
+		return
+	}
+	if err != nil {
+		writeFlagError(w, err)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, flag)
+}
+
+func writeFlagError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.As(err, &ErrInvalidFlagId):
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+	case errors.Is(err, ErrFlagDoesNotExist):
+		app.WriteHttpError(w, http.StatusNotFound, err)
+	default:
+		app.WriteHttpInternalServerError(w)
+	}
+}
+
+// WriteFlaggedError answers with the verdict for admins to act on
+func WriteFlaggedError(w http.ResponseWriter, err *FlaggedError) {
+	app.WriteHttpBodyJson(w, http.StatusUnprocessableEntity, map[string]any{
+		"message":    err.Error(),
+		"moderation": err.Verdict,
+	})
+}
@@ -0,0 +1,187 @@
+package moderation
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/oklog/ulid/v2"
+)
+
+type Decision string
+
+const (
+	ALLOW  Decision = "ALLOW"
+	REVIEW Decision = "REVIEW"
+	BLOCK  Decision = "BLOCK"
+)
+
+type ReasonSource string
+
+const (
+	PROVIDER ReasonSource = "PROVIDER"
+	KEYWORD  ReasonSource = "KEYWORD"
+	PATTERN  ReasonSource = "PATTERN"
+)
+
+// Matches kept on a reason are cut to this many runes
+const maxMatchLength = 100
+
+var (
+	ErrContentBlocked       = errors.New("Content was blocked by moderation")
+	ErrContentHeldForReview = errors.New("Content was held for moderation review")
+)
+
+// Reason is one rule or provider category that flagged a text, Rule is the
+// provider category for provider reasons.
+type Reason struct {
+	Source   ReasonSource `json:"source"`
+	Rule     string       `json:"rule"`
+	Decision Decision     `json:"decision"`
+	Match    string       `json:"match,omitempty"`
+}
+
+// Verdict is the strictest decision among its reasons, FlagId is set when
+// the verdict was recorded.
+type Verdict struct {
+	Decision Decision   `json:"decision"`
+	Reasons  []Reason   `json:"reasons"`
+	FlagId   *ulid.ULID `json:"flag_id"`
+}
+
+func newVerdict(reasons []Reason) Verdict {
+	v := Verdict{Decision: ALLOW, Reasons: reasons}
+	if v.Reasons == nil {
+		v.Reasons = []Reason{}
+	}
+
+	for _, r := range reasons {
+		if r.Decision == BLOCK {
+			v.Decision = BLOCK
+			break
+		}
+		v.Decision = REVIEW
+	}
+
+	return v
+}
+
+// Err is nil for allowed text and a *FlaggedError otherwise
+func (v Verdict) Err() error {
+	if v.Decision == ALLOW {
+		return nil
+	}
+
+	return &FlaggedError{Verdict: v}
+}
+
+// FlaggedError is errors.Is ErrContentBlocked or ErrContentHeldForReview
+// depending on the decision. Its reasons are meant for admins, users only
+// get the message.
+type FlaggedError struct {
+	Verdict
+}
+
+func (e *FlaggedError) Error() string {
+	return e.sentinel().Error()
+}
+
+func (e *FlaggedError) Is(target error) bool {
+	return target == e.sentinel()
+}
+
+func (e *FlaggedError) sentinel() error {
+	if e.Decision == BLOCK {
+		return ErrContentBlocked
+	}
+
+	return ErrContentHeldForReview
+}
+
+// rule flags text its pattern matches
+type rule struct {
+	Name     string
+	Source   ReasonSource
+	Decision Decision
+	Pattern  *regexp.Regexp
+}
+
+// Texts for readers shouldn't point them at people outside the app, contact
+// details are held for review.
+func defaultRules() []rule {
+	return []rule{
+		{
+			Name:     "email",
+			Source:   PATTERN,
+			Decision: REVIEW,
+			Pattern:  regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`),
+		},
+		{
+			Name:     "phone_number",
+			Source:   PATTERN,
+			Decision: REVIEW,
+			Pattern:  regexp.MustCompile(`(?:\+62|\b0)8[1-9][0-9 -]{7,12}[0-9]\b`),
+		},
+		{
+			Name:     "url",
+			Source:   PATTERN,
+			Decision: REVIEW,
+			Pattern:  regexp.MustCompile(`(?i)\bhttps?://\S+|\bwww\.\S+`),
+		},
+	}
+}
+
+// termsPattern matches any of the terms as whole words, ignoring case
+func termsPattern(terms map[string]bool) string {
+	if len(terms) == 0 {
+		return ""
+	}
+
+	quoted := make([]string, 0, len(terms))
+	for term := range terms {
+		quoted = append(quoted, regexp.QuoteMeta(term))
+	}
+	// Keeps the compiled rule the same between runs
+	sort.Strings(quoted)
+
+	return `(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`
+}
+
+func evaluateRules(rules []rule, text string) []Reason {
+	var reasons []Reason
+	for _, r := range rules {
+		if match := r.Pattern.FindString(text); match != "" {
+			reasons = append(reasons, Reason{Source: r.Source, Rule: r.Name, Decision: r.Decision, Match: truncateMatch(match)})
+		}
+	}
+
+	return reasons
+}
+
+func evaluateProviderResult(res adapters.ModerationResult, blockCategories map[string]bool) []Reason {
+	var reasons []Reason
+	for _, category := range res.Categories {
+		decision := REVIEW
+		if blockCategories[strings.ToLower(category)] {
+			decision = BLOCK
+		}
+		reasons = append(reasons, Reason{Source: PROVIDER, Rule: category, Decision: decision})
+	}
+
+	// Flagged without a category we know of is still worth a look
+	if res.Flagged && len(reasons) == 0 {
+		reasons = append(reasons, Reason{Source: PROVIDER, Rule: "flagged", Decision: REVIEW})
+	}
+
+	return reasons
+}
+
+func truncateMatch(match string) string {
+	if runes := []rune(match); len(runes) > maxMatchLength {
+		return string(runes[:maxMatchLength])
+	}
+
+	return match
+}
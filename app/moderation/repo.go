@@ -0,0 +1,89 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrFlagDoesNotExist = errors.New("Moderation flag does not exist")
+
+type flagFilter struct {
+	Status      FlagStatus
+	SubjectKind SubjectKind
+	Limit       uint
+}
+
+func findFlags(ctx context.Context, tx pgx.Tx, filter flagFilter) (flags []*Flag, err error) {
+	q := `
+	SELECT * FROM moderation_flags
+	WHERE ($1 = '' OR status = $1)
+	AND ($2 = '' OR subject_kind = $2)
+	ORDER BY id DESC
+	LIMIT $3
+	`
+
+	if err = pgxscan.Select(ctx, tx, &flags, q, filter.Status, filter.SubjectKind, filter.Limit); err != nil {
+		log.Err(err).Msg("Failed to find moderation flags")
+		return
+	}
+
+	return flags, nil
+}
+
+func findFlagById(ctx context.Context, tx pgx.Tx, id ulid.ULID) (flag Flag, err error) {
+	q := "SELECT * FROM moderation_flags WHERE id = $1"
+
+	if err = pgxscan.Get(ctx, tx, &flag, q, id); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return flag, ErrFlagDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find moderation flag")
+		return
+	}
+
+	return flag, nil
+}
+
+func saveFlag(ctx context.Context, tx pgx.Tx, f Flag) (err error) {
+	q := `
+	INSERT INTO moderation_flags(id, subject_kind, subject_id, user_id, admin, provider, text, subject_hash, decision, reasons, status, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	if _, err = tx.Exec(
+		ctx, q,
+		f.Id, f.SubjectKind, f.SubjectId, f.UserId, f.Admin, f.Provider, f.Text, f.SubjectHash, f.Decision, f.Reasons, f.Status, f.CreatedAt,
+	); err != nil {
+		log.Err(err).Msg("Failed to save moderation flag")
+		return
+	}
+
+	return nil
+}
+
+// updateFlagReview only settles flags still pending, a concurrent review
+// makes it look like the flag doesn't exist.
+func updateFlagReview(ctx context.Context, tx pgx.Tx, f Flag) (err error) {
+	q := `
+	UPDATE moderation_flags
+	SET status = $1, reviewed_by = $2, review_note = $3, reviewed_at = $4
+	WHERE id = $5 AND status = $6
+	`
+
+	tag, err := tx.Exec(ctx, q, f.Status, f.ReviewedBy, f.ReviewNote, f.ReviewedAt, f.Id, PENDING)
+	if err != nil {
+		log.Err(err).Msg("Failed to update moderation flag review")
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrFlagDoesNotExist
+	}
+
+	return nil
+}
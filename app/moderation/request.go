@@ -0,0 +1,8 @@
+package moderation
+
+import "gopkg.in/guregu/null.v4"
+
+type reviewFlagReq struct {
+	Status string      `json:"status"`
+	Note   null.String `json:"note"`
+}
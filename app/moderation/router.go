@@ -0,0 +1,21 @@
+package moderation
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+)
+
+func AdminRouter() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(auth.SuperadminAuthMiddleware)
+	r.Use(app.CacheControl(0))
+	r.Use(app.InvalidateResponseCacheMiddleware)
+
+	r.Get("/", getFlagsHandler)
+	r.Get("/{id}", getFlagHandler)
+	r.Patch("/{id}/review", reviewFlagHandler)
+
+	return r
+}
@@ -0,0 +1,208 @@
+package moderation
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	defaultFlagsLimit = 50
+	maxFlagsLimit     = 200
+)
+
+// Check runs the local rules and the provider on a text and records a flag
+// for anything that isn't allowed, against whoever is on the request context.
+// It never fails, what to do with a verdict is up to the caller.
+func Check(ctx context.Context, subject Subject) Verdict {
+	reasons := evaluateRules(rules, subject.Text)
+
+	provider := ""
+	if moderator != nil {
+		provider = moderator.Provider()
+
+		res, err := moderator.Moderate(ctx, subject.Text)
+		switch {
+		case err == nil:
+			reasons = append(reasons, evaluateProviderResult(res, blockCategories)...)
+		case isFailOpen:
+			log.Err(err).Str("subject_kind", string(subject.Kind)).Msg("Failed to moderate text, letting it through")
+		default:
+			log.Err(err).Str("subject_kind", string(subject.Kind)).Msg("Failed to moderate text, holding it for review")
+			reasons = append(reasons, Reason{Source: PROVIDER, Rule: "unavailable", Decision: REVIEW})
+		}
+	}
+
+	verdict := newVerdict(reasons)
+	if verdict.Decision == ALLOW {
+		return verdict
+	}
+
+	f := NewFlag(subject, verdict, provider)
+	if user, ok := ctx.Value(auth.UserInfoCtx).(auth.User); ok {
+		f.UserId = &user.Id
+	}
+	if admin, ok := ctx.Value(auth.SuperadminInfoCtx).(string); ok {
+		f.Admin = null.StringFrom(admin)
+	}
+
+	log.Warn().Fields(map[string]any{
+		"flag_id":      f.Id,
+		"subject_kind": f.SubjectKind,
+		"decision":     f.Decision,
+		"reasons":      f.Reasons,
+	}).Msg("Moderation flagged text")
+
+	// The audit trail is kept even when the client already hung up
+	if recordFlag(context.Background(), f) {
+		verdict.FlagId = &f.Id
+	}
+
+	return verdict
+}
+
+func recordFlag(ctx context.Context, f Flag) bool {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to record moderation flag")
+		return false
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = saveFlag(ctx, tx, f); err != nil {
+		return false
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to record moderation flag")
+		return false
+	}
+
+	return true
+}
+
+func getFlags(ctx context.Context, statusStr, kindStr, limitStr string) (flags []*Flag, err error) {
+	filter := flagFilter{Status: FlagStatus(statusStr), SubjectKind: SubjectKind(kindStr), Limit: defaultFlagsLimit}
+	if filter.Status != "" {
+		if err = validateFlagStatus(filter.Status); err != nil {
+			return
+		}
+	}
+	if filter.SubjectKind != "" {
+		if err = validateSubjectKind(filter.SubjectKind); err != nil {
+			return
+		}
+	}
+
+	// Don't throw error to client just because of misinputs
+	if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+		filter.Limit = uint(limit)
+		if filter.Limit > maxFlagsLimit {
+			filter.Limit = maxFlagsLimit
+		}
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get moderation flags")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	flags, err = findFlags(ctx, tx, filter)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get moderation flags")
+		return
+	}
+
+	if flags == nil {
+		flags = []*Flag{}
+	}
+
+	return flags, nil
+}
+
+func getFlag(ctx context.Context, idStr string) (flag Flag, err error) {
+	id, err := validateFlagId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get moderation flag")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	flag, err = findFlagById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get moderation flag")
+		return
+	}
+
+	return flag, nil
+}
+
+// reviewFlag settles a pending flag and lets the hook of its kind publish or
+// take down the subject in the same transaction, so a failing hook leaves the
+// flag pending to be reviewed again.
+func reviewFlag(ctx context.Context, admin, idStr string, body reviewFlagReq) (flag Flag, errs map[string]error, err error) {
+	id, err := validateFlagId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to review moderation flag")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	flag, err = findFlagById(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	if errs = flag.Review(FlagStatus(body.Status), admin, body.Note); errs != nil {
+		return flag, errs, nil
+	}
+
+	hooks := approveHooks
+	if flag.Status == REJECTED {
+		hooks = rejectHooks
+	}
+	if hook, ok := hooks[flag.SubjectKind]; ok && flag.SubjectId != nil {
+		if err = hook(ctx, tx, *flag.SubjectId, flag.SubjectHash); err != nil {
+			log.Err(err).Str("status", string(flag.Status)).Msg("Failed to apply moderation review to subject")
+			return flag, nil, err
+		}
+	}
+
+	if err = updateFlagReview(ctx, tx, flag); err != nil {
+		return flag, nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to review moderation flag")
+		return flag, nil, err
+	}
+
+	return flag, nil, nil
+}
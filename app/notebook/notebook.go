@@ -0,0 +1,89 @@
+package notebook
+
+import (
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Card is a word bank card, a word with its meaning the user wants to learn
+type Card struct {
+	Id        ulid.ULID  `json:"id"`
+	UserId    ulid.ULID  `json:"user_id"`
+	Word      string     `json:"word"`
+	Meaning   string     `json:"meaning"`
+	ArticleId *ulid.ULID `json:"article_id"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type Note struct {
+	Id        ulid.ULID  `json:"id"`
+	UserId    ulid.ULID  `json:"user_id"`
+	ArticleId *ulid.ULID `json:"article_id"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NewCard and NewNote take an optional article the card or note came from
+func NewCard(userId ulid.ULID, word, meaning string, articleIdStr null.String) (Card, map[string]error) {
+	errs := make(map[string]error)
+
+	word = strings.TrimSpace(word)
+	meaning = strings.TrimSpace(meaning)
+
+	if err := validateCardWord(word); err != nil {
+		errs["word"] = err
+	}
+	if err := validateCardMeaning(meaning); err != nil {
+		errs["meaning"] = err
+	}
+	articleId, err := validateArticleId(articleIdStr)
+	if err != nil {
+		errs["article_id"] = err
+	}
+	if len(errs) != 0 {
+		return Card{}, errs
+	}
+
+	return Card{
+		Id:        ulid.Make(),
+		UserId:    userId,
+		Word:      word,
+		Meaning:   meaning,
+		ArticleId: articleId,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func NewNote(userId ulid.ULID, title, content string, articleIdStr null.String) (Note, map[string]error) {
+	errs := make(map[string]error)
+
+	title = strings.TrimSpace(title)
+	content = strings.TrimSpace(content)
+
+	if err := validateNoteTitle(title); err != nil {
+		errs["title"] = err
+	}
+	if err := validateNoteContent(content); err != nil {
+		errs["content"] = err
+	}
+	articleId, err := validateArticleId(articleIdStr)
+	if err != nil {
+		errs["article_id"] = err
+	}
+	if len(errs) != 0 {
+		return Note{}, errs
+	}
+
+	return Note{
+		Id:        ulid.Make(),
+		UserId:    userId,
+		ArticleId: articleId,
+		Title:     title,
+		Content:   content,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,84 @@
+package notebook
+
+import (
+	"github.com/jellydator/validation"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrInvalidCardId      = validation.NewError("notebook:invalid_card_id", "Invalid card id")
+	ErrInvalidNoteId      = validation.NewError("notebook:invalid_note_id", "Invalid note id")
+	ErrInvalidArticleId   = validation.NewError("notebook:invalid_article_id", "Invalid article id")
+	ErrCardWordEmpty      = validation.NewError("notebook:word_empty", "Word can't be empty")
+	ErrCardWordTooLong    = validation.NewError("notebook:word_too_long", "Word can't be longer than 255 characters")
+	ErrCardMeaningEmpty   = validation.NewError("notebook:meaning_empty", "Meaning can't be empty")
+	ErrCardMeaningTooLong = validation.NewError("notebook:meaning_too_long", "Meaning can't be longer than 10000 characters")
+	ErrNoteTitleEmpty     = validation.NewError("notebook:title_empty", "Title can't be empty")
+	ErrNoteTitleTooLong   = validation.NewError("notebook:title_too_long", "Title can't be longer than 255 characters")
+	ErrNoteContentEmpty   = validation.NewError("notebook:content_empty", "Content can't be empty")
+	ErrNoteContentTooLong = validation.NewError("notebook:content_too_long", "Content can't be longer than 10000 characters")
+)
+
+func validateCardId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidCardId
+	}
+
+	return id, nil
+}
+
+func validateNoteId(idStr string) (id ulid.ULID, err error) {
+	id, err = ulid.Parse(idStr)
+	if err != nil {
+		return id, ErrInvalidNoteId
+	}
+
+	return id, nil
+}
+
+func validateArticleId(idStr null.String) (*ulid.ULID, error) {
+	if !idStr.Valid || idStr.String == "" {
+		return nil, nil
+	}
+
+	id, err := ulid.Parse(idStr.String)
+	if err != nil {
+		return nil, ErrInvalidArticleId
+	}
+
+	return &id, nil
+}
+
+func validateCardWord(word string) error {
+	return validation.Validate(
+		&word,
+		validation.Required.ErrorObject(ErrCardWordEmpty),
+		validation.RuneLength(1, 255).ErrorObject(ErrCardWordTooLong),
+	)
+}
+
+func validateCardMeaning(meaning string) error {
+	return validation.Validate(
+		&meaning,
+		validation.Required.ErrorObject(ErrCardMeaningEmpty),
+		validation.RuneLength(1, 10000).ErrorObject(ErrCardMeaningTooLong),
+	)
+}
+
+func validateNoteTitle(title string) error {
+	return validation.Validate(
+		&title,
+		validation.Required.ErrorObject(ErrNoteTitleEmpty),
+		validation.RuneLength(1, 255).ErrorObject(ErrNoteTitleTooLong),
+	)
+}
+
+func validateNoteContent(content string) error {
+	return validation.Validate(
+		&content,
+		validation.Required.ErrorObject(ErrNoteContentEmpty),
+		validation.RuneLength(1, 10000).ErrorObject(ErrNoteContentTooLong),
+	)
+}
@@ -0,0 +1,114 @@
+package notebook
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrCardDoesNotExist = errors.New("Card does not exist")
+	ErrNoteDoesNotExist = errors.New("Note does not exist")
+)
+
+func findCardsByUserId(ctx context.Context, tx pgx.Tx, userId ulid.ULID, limit uint) (cards []*Card, err error) {
+	q := "SELECT * FROM notebook_cards WHERE user_id = $1 ORDER BY id DESC LIMIT $2"
+
+	if err = pgxscan.Select(ctx, tx, &cards, q, userId, limit); err != nil {
+		log.Err(err).Msg("Failed to find cards by user id")
+		return
+	}
+
+	return cards, nil
+}
+
+func findCardByIdAndUserId(ctx context.Context, tx pgx.Tx, id, userId ulid.ULID) (card Card, err error) {
+	q := "SELECT * FROM notebook_cards WHERE id = $1 AND user_id = $2"
+
+	if err = pgxscan.Get(ctx, tx, &card, q, id, userId); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return card, ErrCardDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find card")
+		return
+	}
+
+	return card, nil
+}
+
+func saveCard(ctx context.Context, tx pgx.Tx, c Card) (err error) {
+	q := `
+	INSERT INTO notebook_cards(id, user_id, word, meaning, article_id, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if _, err = tx.Exec(ctx, q, c.Id, c.UserId, c.Word, c.Meaning, c.ArticleId, c.CreatedAt); err != nil {
+		log.Err(err).Msg("Failed to save card")
+		return
+	}
+
+	return nil
+}
+
+func deleteCard(ctx context.Context, tx pgx.Tx, c Card) (err error) {
+	if _, err = tx.Exec(ctx, "DELETE FROM notebook_cards WHERE id = $1", c.Id); err != nil {
+		log.Err(err).Msg("Failed to delete card")
+		return
+	}
+
+	return nil
+}
+
+func findNotesByUserId(ctx context.Context, tx pgx.Tx, userId ulid.ULID, limit uint) (notes []*Note, err error) {
+	q := "SELECT * FROM notebook_notes WHERE user_id = $1 ORDER BY id DESC LIMIT $2"
+
+	if err = pgxscan.Select(ctx, tx, &notes, q, userId, limit); err != nil {
+		log.Err(err).Msg("Failed to find notes by user id")
+		return
+	}
+
+	return notes, nil
+}
+
+func findNoteByIdAndUserId(ctx context.Context, tx pgx.Tx, id, userId ulid.ULID) (note Note, err error) {
+	q := "SELECT * FROM notebook_notes WHERE id = $1 AND user_id = $2"
+
+	if err = pgxscan.Get(ctx, tx, &note, q, id, userId); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return note, ErrNoteDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find note")
+		return
+	}
+
+	return note, nil
+}
+
+func saveNote(ctx context.Context, tx pgx.Tx, n Note) (err error) {
+	q := `
+	INSERT INTO notebook_notes(id, user_id, article_id, title, content, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if _, err = tx.Exec(ctx, q, n.Id, n.UserId, n.ArticleId, n.Title, n.Content, n.CreatedAt); err != nil {
+		log.Err(err).Msg("Failed to save note")
+		return
+	}
+
+	return nil
+}
+
+func deleteNote(ctx context.Context, tx pgx.Tx, n Note) (err error) {
+	if _, err = tx.Exec(ctx, "DELETE FROM notebook_notes WHERE id = $1", n.Id); err != nil {
+		log.Err(err).Msg("Failed to delete note")
+		return
+	}
+
+	return nil
+}
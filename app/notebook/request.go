@@ -0,0 +1,15 @@
+package notebook
+
+import "gopkg.in/guregu/null.v4"
+
+type createCardReq struct {
+	Word      string      `json:"word"`
+	Meaning   string      `json:"meaning"`
+	ArticleId null.String `json:"article_id"`
+}
+
+type createNoteReq struct {
+	Title     string      `json:"title"`
+	Content   string      `json:"content"`
+	ArticleId null.String `json:"article_id"`
+}
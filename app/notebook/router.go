@@ -0,0 +1,22 @@
+package notebook
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app/auth"
+)
+
+func Router() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(auth.UserAuthMiddleware)
+
+	r.Get("/cards", getCardsHandler)
+	r.Post("/cards", createCardHandler)
+	r.Delete("/cards/{id}", deleteCardHandler)
+
+	r.Get("/notes", getNotesHandler)
+	r.Post("/notes", createNoteHandler)
+	r.Delete("/notes/{id}", deleteNoteHandler)
+
+	return r
+}
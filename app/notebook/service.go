@@ -0,0 +1,192 @@
+package notebook
+
+import (
+	"context"
+
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 500
+)
+
+func listLimit(limit uint) uint {
+	if limit == 0 || limit > maxListLimit {
+		return defaultListLimit
+	}
+
+	return limit
+}
+
+// AddCard saves a card made with NewCard, other modules use it to turn what
+// they produced into cards.
+func AddCard(ctx context.Context, card Card) (c Card, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to add card")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = saveCard(ctx, tx, card); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to add card")
+		return
+	}
+
+	return card, nil
+}
+
+// AddNote saves a note made with NewNote
+func AddNote(ctx context.Context, note Note) (n Note, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to add note")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = saveNote(ctx, tx, note); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to add note")
+		return
+	}
+
+	return note, nil
+}
+
+func createCard(ctx context.Context, user auth.User, body createCardReq) (card Card, errs map[string]error, err error) {
+	card, errs = NewCard(user.Id, body.Word, body.Meaning, body.ArticleId)
+	if len(errs) != 0 {
+		return
+	}
+
+	card, err = AddCard(ctx, card)
+	return card, nil, err
+}
+
+func createNote(ctx context.Context, user auth.User, body createNoteReq) (note Note, errs map[string]error, err error) {
+	note, errs = NewNote(user.Id, body.Title, body.Content, body.ArticleId)
+	if len(errs) != 0 {
+		return
+	}
+
+	note, err = AddNote(ctx, note)
+	return note, nil, err
+}
+
+func getCards(ctx context.Context, user auth.User, limit uint) (cards []*Card, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get cards")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	cards, err = findCardsByUserId(ctx, tx, user.Id, listLimit(limit))
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get cards")
+		return
+	}
+
+	return cards, nil
+}
+
+func getNotes(ctx context.Context, user auth.User, limit uint) (notes []*Note, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get notes")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	notes, err = findNotesByUserId(ctx, tx, user.Id, listLimit(limit))
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get notes")
+		return
+	}
+
+	return notes, nil
+}
+
+func removeCard(ctx context.Context, user auth.User, idStr string) (card Card, err error) {
+	id, err := validateCardId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to remove card")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	card, err = findCardByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	if err = deleteCard(ctx, tx, card); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to remove card")
+		return
+	}
+
+	return card, nil
+}
+
+func removeNote(ctx context.Context, user auth.User, idStr string) (note Note, err error) {
+	id, err := validateNoteId(idStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to remove note")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	note, err = findNoteByIdAndUserId(ctx, tx, id, user.Id)
+	if err != nil {
+		return
+	}
+
+	if err = deleteNote(ctx, tx, note); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to remove note")
+		return
+	}
+
+	return note, nil
+}
@@ -0,0 +1,142 @@
+package prompt
+
+type PromptName string
+
+const (
+	ARTICLE_TEXT    PromptName = "article_text"
+	ARTICLE_SUMMARY PromptName = "article_summary"
+	ARTICLE_HEADING PromptName = "article_heading"
+	SIMPLIFY        PromptName = "simplify"
+	EXPLAIN         PromptName = "explain"
+	ARTICLE_CHAT    PromptName = "article_chat"
+)
+
+// definition is the built-in version 0 of a prompt. Sample variables are used
+// to check edited templates and as the base for preview renders.
+type definition struct {
+	description    string
+	systemTemplate string
+	userTemplate   string
+	sample         map[string]any
+}
+
+var definitions = map[PromptName]definition{
+	ARTICLE_TEXT: {
+		description: "Adapts an article text from one reading difficulty to another",
+		systemTemplate: `Kamu bertugas untuk menyederhanakan bacaan sesuai dengan level pemahaman baca yang diinginkan. Ada tiga level pemahaman baca:
+
+1. ADVANCED, ditujukan untuk teks yang butuh pemahaman baca tinggi. Seperti untuk orang-orang di dunia kerja dan mahasiswa.
+2. INTERMEDIATE, ditujukan untuk teks yang butuh pemahaman baca menengah. Seperti siswa-siswa SMP kelas 7 di Indonesia sampai SMA kelas 12.
+3. BEGINNER, ditujukan untuk teks yang butuh pemahaman baca pemula. Seperti siswa-siswa SD di Indonesia kelas 1 sampai 6.
+
+  User akan memberi tahu kamu apa level pemahaman baca dari bacaan yang diberi serta level pemahaman baca yang user inginkan. Lalu di bawahnya, user akan memberikan bacaan yang akan kamu sederhanakan ke level pemahaman baca yang user inginkan
+`,
+		userTemplate: `Teks di bawah ini dalam level pemahaman baca {{.OriginalDifficulty}}. Saya ingin kamu menyederhanakan teks berikut ke level pemahaman baca {{.TargetDifficulty}}:
+
+{{if .Feedback}}Hasil penyederhanaan sebelumnya ditolak karena masalah berikut, pastikan tidak terulang:
+
+{{.Feedback}}
+
+{{end}}{{if .Context}}Teks ini adalah lanjutan dari artikel yang lebih panjang. Ringkasan bagian sebelumnya di bawah ini hanya sebagai konteks, jangan ditulis ulang:
+
+{{.Context}}
+
+Teks yang perlu disederhanakan:
+
+{{end}}{{.Text}}`,
+		sample: map[string]any{
+			"OriginalDifficulty": "ADVANCED",
+			"TargetDifficulty":   "BEGINNER",
+			"Text":               "Fotosintesis merupakan proses biokimia pembentukan zat makanan yang dilakukan oleh tumbuhan.",
+			"Context":            "",
+			"Feedback":           "",
+		},
+	},
+	ARTICLE_SUMMARY: {
+		description:    "Keeps a rolling summary of a long article while it's adapted part by part",
+		systemTemplate: `Kamu bertugas membuat ringkasan singkat dari sebuah artikel yang dibaca bagian demi bagian. Ringkasan ini dipakai sebagai konteks saat bagian berikutnya disederhanakan, jadi pertahankan tokoh, istilah, dan alur cerita yang penting. Tulis paling banyak lima kalimat dalam bahasa Indonesia tanpa kalimat pembuka.`,
+		userTemplate: `{{if .Summary}}Ringkasan bagian sebelumnya:
+
+{{.Summary}}
+
+{{end}}Perbarui ringkasan dengan bagian artikel berikut:
+
+{{.Text}}`,
+		sample: map[string]any{
+			"Summary": "",
+			"Text":    "Fotosintesis merupakan proses biokimia pembentukan zat makanan yang dilakukan oleh tumbuhan.",
+		},
+	},
+	ARTICLE_HEADING: {
+		description:    "Adapts a heading of an article document to another reading difficulty",
+		systemTemplate: `Kamu bertugas menyederhanakan judul bagian sebuah artikel sesuai dengan level pemahaman baca yang diinginkan: ADVANCED untuk pembaca dewasa, INTERMEDIATE untuk siswa SMP sampai SMA, dan BEGINNER untuk siswa SD. Jawab hanya dengan judul yang sudah disederhanakan dalam satu baris, paling banyak 15 kata, tanpa tanda kutip dan tanpa kalimat pembuka.`,
+		userTemplate: `Sederhanakan judul berikut dari level {{.OriginalDifficulty}} ke level {{.TargetDifficulty}}:
+
+{{.Text}}`,
+		sample: map[string]any{
+			"OriginalDifficulty": "ADVANCED",
+			"TargetDifficulty":   "BEGINNER",
+			"Text":               "Mekanisme Biokimia Fotosintesis pada Tumbuhan Hijau",
+		},
+	},
+	SIMPLIFY: {
+		description:    "Simplifies a passage highlighted by a reader",
+		systemTemplate: `Kamu bisa menjelaskan suatu topik yang kompleks dengan baik dan dapat membentuk penjelasan yang mudah dipahami orang. Tugasmu adalah untuk menyederhanakan teks yang akan diberikan menjadi bentuk yang lebih sederhana dan mudah dipahami. Kamu bebas mengurangi kata dan menggunakan bahasa yang lebih mudah jika perlu selama inti dari teksnya tetap tersampaikan.`,
+		userTemplate: `{{if .Paragraph}}Saya sedang membaca artikel berjudul "{{.Title}}". Teks yang ingin saya pahami ada di dalam paragraf ini:
+
+{{.Paragraph}}
+
+{{end}}Saya kurang mengerti mengenai teks di bawah ini. Tolong disederhanakan agar saya bisa memahaminya dengan lebih mudah:
+
+{{.Text}}`,
+		sample: map[string]any{
+			"Text":      "Inflasi adalah kecenderungan kenaikan harga barang dan jasa secara umum dan terus-menerus.",
+			"Title":     "",
+			"Paragraph": "",
+		},
+	},
+	EXPLAIN: {
+		description:    "Explains a sentence, paragraph or term highlighted by a reader",
+		systemTemplate: `Kamu adalah seorang pakar yang ahli dalam berbagai macam bidang dan pengetahuan yang kamu miliki luas. Tugas kamu adalah menjelaskan kalimat, paragraf, atau teks yang akan diberikan`,
+		userTemplate: `{{if .Paragraph}}Saya sedang membaca artikel berjudul "{{.Title}}". Teks yang ingin saya pahami ada di dalam paragraf ini, jelaskan sesuai maknanya di paragraf tersebut:
+
+{{.Paragraph}}
+
+{{end}}Tolong berikan penjelasan yang mudah dipahami mengenai teks berikut:
+
+"{{.Text}}"`,
+		sample: map[string]any{
+			"Text":      "Fotosintesis",
+			"Title":     "",
+			"Paragraph": "",
+		},
+	},
+	ARTICLE_CHAT: {
+		description: "Answers a reader's questions about the article they're reading, the earlier messages of the chat are sent between the system and user message",
+		systemTemplate: `Kamu adalah asisten membaca di Lexica. Pengguna sedang membaca artikel berjudul "{{.Title}}" dalam level pemahaman baca {{.Difficulty}}. Jawab pertanyaan pengguna tentang artikel ini dengan bahasa yang sesuai dengan level tersebut. Jadikan isi artikel sebagai sumber utama jawabanmu. Jika jawabannya tidak ada di dalam artikel, katakan dengan jujur lalu berikan penjelasan umum yang singkat.
+
+Isi artikel:
+
+{{.Article}}`,
+		userTemplate: `{{.Question}}`,
+		sample: map[string]any{
+			"Title":      "Mengenal Fotosintesis",
+			"Difficulty": "BEGINNER",
+			"Article":    "Fotosintesis merupakan proses biokimia pembentukan zat makanan yang dilakukan oleh tumbuhan.",
+			"Question":   "Kenapa tumbuhan butuh cahaya matahari?",
+		},
+	},
+}
+
+// defaultPromptTemplate is version 0 of a prompt, used whenever no stored
+// version is active.
+func defaultPromptTemplate(name PromptName) PromptTemplate {
+	def := definitions[name]
+
+	return PromptTemplate{
+		Name:           name,
+		Version:        0,
+		SystemTemplate: def.systemTemplate,
+		UserTemplate:   def.userTemplate,
+	}
+}
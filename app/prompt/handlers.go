@@ -0,0 +1,127 @@
+package prompt
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+)
+
+func getPromptDefinitionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	defs, err := getPromptDefinitions(ctx)
+	if err != nil {
+		app.WriteHttpInternalServerError(w)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, defs)
+}
+
+func getPromptDetailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	name := chi.URLParam(r, "name")
+
+	detail, err := getPromptDetail(ctx, name)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrUnknownPromptName):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, detail)
+}
+
+func createPromptTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	name := chi.URLParam(r, "name")
+
+	var body createPromptTemplateReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p, errs, err := createPromptTemplate(ctx, name, body)
+	if errs != nil {
+		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrUnknownPromptName):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrPromptTemplateVersionExist):
+			app.WriteHttpError(w, http.StatusConflict, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusCreated, p)
+}
+
+func activatePromptTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	name := chi.URLParam(r, "name")
+	version := chi.URLParam(r, "version")
+
+	p, err := activatePromptTemplateVersion(ctx, name, version)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrUnknownPromptName), errors.As(err, &ErrInvalidPromptVersion):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		case errors.Is(err, ErrPromptTemplateDoesNotExist):
+			app.WriteHttpError(w, http.StatusNotFound, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, p)
+}
+
+func previewPromptTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	name := chi.URLParam(r, "name")
+
+	var body previewPromptTemplateReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		app.WriteHttpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rendered, errs, err := previewPromptTemplate(ctx, name, body)
+	if errs != nil {
+		app.WriteHttpErrors(w, http.StatusBadRequest, errs)
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrUnknownPromptName):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, rendered)
+}
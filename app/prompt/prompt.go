@@ -0,0 +1,104 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+type PromptTemplate struct {
+	Id             ulid.ULID   `json:"id"`
+	Name           PromptName  `json:"name"`
+	Version        uint        `json:"version"`
+	SystemTemplate string      `json:"system_template"`
+	UserTemplate   string      `json:"user_template"`
+	Note           null.String `json:"note"`
+	IsActive       bool        `json:"is_active"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// RenderedPrompt is what gets sent to the model, Ref identifies the template
+// version that produced it so generated content can be traced back.
+type RenderedPrompt struct {
+	Name    PromptName `json:"name"`
+	Version uint       `json:"version"`
+	Ref     string     `json:"ref"`
+	System  string     `json:"system"`
+	User    string     `json:"user"`
+}
+
+func NewPromptTemplate(nameStr, systemTemplate, userTemplate string, note null.String, version uint) (PromptTemplate, map[string]error) {
+	errs := make(map[string]error)
+
+	name, err := validatePromptName(nameStr)
+	if err != nil {
+		errs["name"] = err
+		return PromptTemplate{}, errs
+	}
+	if err = validatePromptTemplate(name, systemTemplate); err != nil {
+		errs["system_template"] = err
+	}
+	if err = validatePromptTemplate(name, userTemplate); err != nil {
+		errs["user_template"] = err
+	}
+	if err = validatePromptNote(note); err != nil {
+		errs["note"] = err
+	}
+	if len(errs) != 0 {
+		return PromptTemplate{}, errs
+	}
+
+	return PromptTemplate{
+		Id:             ulid.Make(),
+		Name:           name,
+		Version:        version,
+		SystemTemplate: systemTemplate,
+		UserTemplate:   userTemplate,
+		Note:           note,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+func (p PromptTemplate) Render(vars map[string]any) (rendered RenderedPrompt, err error) {
+	system, err := renderTemplate(string(p.Name)+".system", p.SystemTemplate, vars)
+	if err != nil {
+		return
+	}
+
+	user, err := renderTemplate(string(p.Name)+".user", p.UserTemplate, vars)
+	if err != nil {
+		return
+	}
+
+	return RenderedPrompt{
+		Name:    p.Name,
+		Version: p.Version,
+		Ref:     p.Ref(),
+		System:  system,
+		User:    user,
+	}, nil
+}
+
+func (p PromptTemplate) Ref() string {
+	return fmt.Sprintf("%s@v%d", p.Name, p.Version)
+}
+
+// renderTemplate fails on variables the caller didn't provide instead of
+// silently sending "<no value>" to the model.
+func renderTemplate(name, text string, vars map[string]any) (string, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,58 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jellydator/validation"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrUnknownPromptName        = validation.NewError("prompt:unknown_name", "Unknown prompt name")
+	ErrInvalidPromptVersion     = validation.NewError("prompt:invalid_version", "Invalid prompt version")
+	ErrPromptTemplateEmpty      = validation.NewError("prompt:template_empty", "Prompt template can't be empty")
+	ErrPromptTemplateTooLong    = validation.NewError("prompt:template_too_long", "Prompt template can't be longer than 20000 characters")
+	ErrInvalidPromptTemplate    = validation.NewError("prompt:invalid_template", "Invalid prompt template")
+	ErrPromptNoteTooLong        = validation.NewError("prompt:note_too_long", "Prompt note can't be longer than 255 characters")
+	ErrInvalidPromptPreviewVars = validation.NewError("prompt:invalid_preview_variables", "Prompt preview variables don't match the template")
+)
+
+func validatePromptName(nameStr string) (name PromptName, err error) {
+	name = PromptName(strings.TrimSpace(nameStr))
+	if _, ok := definitions[name]; !ok {
+		return name, ErrUnknownPromptName
+	}
+
+	return name, nil
+}
+
+// validatePromptTemplate parses the template and renders it with the sample
+// variables of the prompt, so a typo in a variable name is caught on save
+// rather than on the next generation.
+func validatePromptTemplate(name PromptName, text string) error {
+	if err := validation.Validate(
+		strings.TrimSpace(text),
+		validation.Required.ErrorObject(ErrPromptTemplateEmpty),
+		validation.RuneLength(1, 20000).ErrorObject(ErrPromptTemplateTooLong),
+	); err != nil {
+		return err
+	}
+
+	if _, err := renderTemplate(string(name), text, definitions[name].sample); err != nil {
+		return ErrInvalidPromptTemplate.SetMessage(fmt.Sprintf("Invalid prompt template: %v", err))
+	}
+
+	return nil
+}
+
+func validatePromptNote(note null.String) error {
+	if !note.Valid {
+		return nil
+	}
+
+	return validation.Validate(
+		strings.TrimSpace(note.String),
+		validation.RuneLength(0, 255).ErrorObject(ErrPromptNoteTooLong),
+	)
+}
@@ -0,0 +1,140 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrPromptTemplateDoesNotExist = errors.New("Prompt template version does not exist")
+	ErrPromptTemplateVersionExist = errors.New("Another version of this prompt was saved at the same time, please try again")
+)
+
+func findActivePromptTemplate(ctx context.Context, tx pgx.Tx, name PromptName) (p PromptTemplate, err error) {
+	q := "SELECT * FROM prompt_templates WHERE name = $1 AND is_active = TRUE"
+
+	if err = pgxscan.Get(ctx, tx, &p, q, name); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return p, ErrPromptTemplateDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find active prompt template")
+		return
+	}
+
+	return p, nil
+}
+
+func findActivePromptTemplates(ctx context.Context, tx pgx.Tx) (templates []*PromptTemplate, err error) {
+	q := "SELECT * FROM prompt_templates WHERE is_active = TRUE"
+
+	templates = []*PromptTemplate{}
+	if err = pgxscan.Select(ctx, tx, &templates, q); err != nil {
+		log.Err(err).Msg("Failed to find active prompt templates")
+		return
+	}
+
+	return templates, nil
+}
+
+func findPromptTemplatesByName(ctx context.Context, tx pgx.Tx, name PromptName) (templates []*PromptTemplate, err error) {
+	q := "SELECT * FROM prompt_templates WHERE name = $1 ORDER BY version DESC"
+
+	templates = []*PromptTemplate{}
+	if err = pgxscan.Select(ctx, tx, &templates, q, name); err != nil {
+		log.Err(err).Msg("Failed to find prompt templates by name")
+		return
+	}
+
+	return templates, nil
+}
+
+func findPromptTemplateByNameAndVersion(ctx context.Context, tx pgx.Tx, name PromptName, version uint) (p PromptTemplate, err error) {
+	q := "SELECT * FROM prompt_templates WHERE name = $1 AND version = $2"
+
+	if err = pgxscan.Get(ctx, tx, &p, q, name, version); err != nil {
+		if err.Error() == "scanning one: no rows in result set" {
+			return p, ErrPromptTemplateDoesNotExist
+		}
+
+		log.Err(err).Msg("Failed to find prompt template by version")
+		return
+	}
+
+	return p, nil
+}
+
+func findLatestPromptTemplateVersion(ctx context.Context, tx pgx.Tx, name PromptName) (version uint, err error) {
+	q := "SELECT COALESCE(MAX(version), 0) FROM prompt_templates WHERE name = $1"
+
+	if err = tx.QueryRow(ctx, q, name).Scan(&version); err != nil {
+		log.Err(err).Msg("Failed to find latest prompt template version")
+		return
+	}
+
+	return version, nil
+}
+
+func savePromptTemplate(ctx context.Context, tx pgx.Tx, p PromptTemplate) (newTemplate PromptTemplate, err error) {
+	q := `
+	INSERT INTO prompt_templates(id, name, version, system_template, user_template, note, is_active, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING *
+	`
+
+	if err = pgxscan.Get(
+		ctx,
+		tx,
+		&newTemplate,
+		q,
+		p.Id,
+		p.Name,
+		p.Version,
+		p.SystemTemplate,
+		p.UserTemplate,
+		p.Note,
+		p.IsActive,
+		p.CreatedAt,
+	); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return p, ErrPromptTemplateVersionExist
+		}
+
+		log.Err(err).Msg("Failed to save prompt template")
+		return
+	}
+
+	return newTemplate, nil
+}
+
+// activatePromptTemplate makes the given version the only active one, version 0
+// deactivates every stored version so the built-in default is used again.
+func activatePromptTemplate(ctx context.Context, tx pgx.Tx, name PromptName, version uint) (err error) {
+	q := "UPDATE prompt_templates SET is_active = FALSE WHERE name = $1 AND is_active = TRUE"
+	if _, err = tx.Exec(ctx, q, name); err != nil {
+		log.Err(err).Msg("Failed to deactivate prompt templates")
+		return
+	}
+
+	if version == 0 {
+		return nil
+	}
+
+	q = "UPDATE prompt_templates SET is_active = TRUE WHERE name = $1 AND version = $2"
+	tag, err := tx.Exec(ctx, q, name, version)
+	if err != nil {
+		log.Err(err).Msg("Failed to activate prompt template")
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPromptTemplateDoesNotExist
+	}
+
+	return nil
+}
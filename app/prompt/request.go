@@ -0,0 +1,16 @@
+package prompt
+
+import "gopkg.in/guregu/null.v4"
+
+type createPromptTemplateReq struct {
+	SystemTemplate string      `json:"system_template"`
+	UserTemplate   string      `json:"user_template"`
+	Note           null.String `json:"note"`
+	Activate       null.Bool   `json:"activate"`
+}
+
+type previewPromptTemplateReq struct {
+	SystemTemplate null.String    `json:"system_template"`
+	UserTemplate   null.String    `json:"user_template"`
+	Variables      map[string]any `json:"variables"`
+}
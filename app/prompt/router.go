@@ -0,0 +1,22 @@
+package prompt
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+)
+
+func AdminRouter() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(auth.SuperadminAuthMiddleware)
+	r.Use(app.CacheControl(0))
+
+	r.Get("/", getPromptDefinitionsHandler)
+	r.Get("/{name}", getPromptDetailHandler)
+	r.Post("/{name}", createPromptTemplateHandler)
+	r.Patch("/{name}/{version}/activate", activatePromptTemplateHandler)
+	r.Post("/{name}/preview", previewPromptTemplateHandler)
+
+	return r
+}
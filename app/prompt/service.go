@@ -0,0 +1,289 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Render fills the active version of the named prompt, falling back to the
+// built-in default when no stored version is active.
+func Render(ctx context.Context, name PromptName, vars map[string]any) (rendered RenderedPrompt, err error) {
+	// Without a database, like in tests of the modules rendering prompts,
+	// there are only the built-in templates
+	if pool == nil {
+		return renderPromptTemplate(defaultPromptTemplate(name), vars)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to render prompt")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	p, err := findActivePromptTemplate(ctx, tx, name)
+	if err == ErrPromptTemplateDoesNotExist {
+		p = defaultPromptTemplate(name)
+	} else if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to render prompt")
+		return
+	}
+
+	return renderPromptTemplate(p, vars)
+}
+
+func renderPromptTemplate(p PromptTemplate, vars map[string]any) (rendered RenderedPrompt, err error) {
+	rendered, err = p.Render(vars)
+	if err != nil {
+		log.Err(err).Str("prompt", p.Ref()).Msg("Failed to render prompt")
+		return
+	}
+
+	return rendered, nil
+}
+
+func getPromptDefinitions(ctx context.Context) (defs []*PromptDefinition, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get prompt definitions")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	active, err := findActivePromptTemplates(ctx, tx)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get prompt definitions")
+		return
+	}
+
+	activeVersions := make(map[PromptName]uint)
+	for _, p := range active {
+		activeVersions[p.Name] = p.Version
+	}
+
+	defs = make([]*PromptDefinition, 0, len(definitions))
+	for name, def := range definitions {
+		defs = append(defs, &PromptDefinition{
+			Name:          name,
+			Description:   def.description,
+			Variables:     def.sample,
+			ActiveVersion: activeVersions[name],
+		})
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+
+	return defs, nil
+}
+
+func getPromptDetail(ctx context.Context, nameStr string) (detail PromptDetail, err error) {
+	name, err := validatePromptName(nameStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get prompt detail")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	versions, err := findPromptTemplatesByName(ctx, tx, name)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get prompt detail")
+		return
+	}
+
+	def := definitions[name]
+	detail = PromptDetail{
+		PromptDefinition: PromptDefinition{Name: name, Description: def.description, Variables: def.sample},
+		Versions:         versions,
+	}
+
+	defaultTemplate := defaultPromptTemplate(name)
+	defaultTemplate.IsActive = true
+	for _, p := range versions {
+		if p.IsActive {
+			detail.ActiveVersion = p.Version
+			defaultTemplate.IsActive = false
+		}
+	}
+	detail.Versions = append(detail.Versions, &defaultTemplate)
+
+	return detail, nil
+}
+
+// createPromptTemplate stores the templates as the next version of the prompt,
+// it becomes the active version unless activate is explicitly false.
+func createPromptTemplate(ctx context.Context, nameStr string, body createPromptTemplateReq) (p PromptTemplate, errs map[string]error, err error) {
+	name, err := validatePromptName(nameStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to create prompt template")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	latest, err := findLatestPromptTemplateVersion(ctx, tx, name)
+	if err != nil {
+		return
+	}
+
+	p, errs = NewPromptTemplate(nameStr, body.SystemTemplate, body.UserTemplate, body.Note, latest+1)
+	if errs != nil {
+		return
+	}
+
+	p, err = savePromptTemplate(ctx, tx, p)
+	if err != nil {
+		return
+	}
+
+	if !body.Activate.Valid || body.Activate.Bool {
+		if err = activatePromptTemplate(ctx, tx, p.Name, p.Version); err != nil {
+			return
+		}
+		p.IsActive = true
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to create prompt template")
+		return
+	}
+
+	log.Info().Str("prompt", p.Ref()).Bool("active", p.IsActive).Msg("Prompt template version created")
+
+	return p, nil, nil
+}
+
+func activatePromptTemplateVersion(ctx context.Context, nameStr, versionStr string) (p PromptTemplate, err error) {
+	name, err := validatePromptName(nameStr)
+	if err != nil {
+		return
+	}
+
+	version, err := strconv.ParseUint(versionStr, 10, 32)
+	if err != nil {
+		return p, ErrInvalidPromptVersion
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to activate prompt template")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = activatePromptTemplate(ctx, tx, name, uint(version)); err != nil {
+		return
+	}
+
+	if version == 0 {
+		p = defaultPromptTemplate(name)
+	} else if p, err = findPromptTemplateByNameAndVersion(ctx, tx, name, uint(version)); err != nil {
+		return
+	}
+	p.IsActive = true
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to activate prompt template")
+		return
+	}
+
+	log.Info().Str("prompt", p.Ref()).Msg("Prompt template version activated")
+
+	return p, nil
+}
+
+// previewPromptTemplate renders either the given templates or the active
+// version, with the given variables laid over the sample ones. Nothing is
+// stored and no model is called.
+func previewPromptTemplate(ctx context.Context, nameStr string, body previewPromptTemplateReq) (rendered RenderedPrompt, errs map[string]error, err error) {
+	name, err := validatePromptName(nameStr)
+	if err != nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to preview prompt template")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	p, err := findActivePromptTemplate(ctx, tx, name)
+	if err == ErrPromptTemplateDoesNotExist {
+		p = defaultPromptTemplate(name)
+	} else if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to preview prompt template")
+		return
+	}
+
+	isDraft := body.SystemTemplate.Valid || body.UserTemplate.Valid
+	if isDraft {
+		systemTemplate, userTemplate := p.SystemTemplate, p.UserTemplate
+		if body.SystemTemplate.Valid {
+			systemTemplate = body.SystemTemplate.String
+		}
+		if body.UserTemplate.Valid {
+			userTemplate = body.UserTemplate.String
+		}
+
+		p, errs = NewPromptTemplate(nameStr, systemTemplate, userTemplate, null.String{}, 0)
+		if errs != nil {
+			return
+		}
+	}
+
+	vars := make(map[string]any, len(definitions[name].sample))
+	for k, v := range definitions[name].sample {
+		vars[k] = v
+	}
+	for k, v := range body.Variables {
+		vars[k] = v
+	}
+
+	rendered, err = p.Render(vars)
+	if err != nil {
+		return rendered, map[string]error{
+			"variables": ErrInvalidPromptPreviewVars.SetMessage(fmt.Sprintf("Prompt preview variables don't match the template: %v", err)),
+		}, nil
+	}
+
+	if isDraft {
+		rendered.Ref = string(name) + "@draft"
+	}
+
+	return rendered, nil, nil
+}
@@ -0,0 +1,13 @@
+package prompt
+
+type PromptDefinition struct {
+	Name          PromptName     `json:"name"`
+	Description   string         `json:"description"`
+	Variables     map[string]any `json:"variables"`
+	ActiveVersion uint           `json:"active_version"`
+}
+
+type PromptDetail struct {
+	PromptDefinition
+	Versions []*PromptTemplate `json:"versions"`
+}
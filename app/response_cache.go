@@ -0,0 +1,184 @@
+package app
+
+import (
+	"bytes"
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultResponseCacheTtlSeconds = 60
+
+var responses *responseCache
+
+type cachedResponse struct {
+	key          string
+	status       int
+	header       http.Header
+	body         []byte
+	lastModified time.Time
+	storedAt     time.Time
+}
+
+// responseCache is a fixed size LRU of rendered responses keyed by path and
+// query. Entries also expire after ttl so writes that don't go through the
+// admin API, like collections changing popularity, are eventually picked up.
+type responseCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+func ConfigureResponseCache(c Config) {
+	if c.ResponseCacheSize <= 0 {
+		responses = nil
+		return
+	}
+
+	ttlSeconds := c.ResponseCacheTtlSeconds
+	if ttlSeconds <= 0 {
+		ttlSeconds = defaultResponseCacheTtlSeconds
+	}
+
+	responses = &responseCache{
+		capacity: c.ResponseCacheSize,
+		ttl:      time.Duration(ttlSeconds) * time.Second,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *responseCache) get(key string) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, isFound := c.entries[key]
+	if !isFound {
+		return nil, false
+	}
+
+	entry := element.Value.(*cachedResponse)
+	if time.Since(entry.storedAt) > c.ttl {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry, true
+}
+
+func (c *responseCache) set(entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, isFound := c.entries[entry.key]; isFound {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[entry.key] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+func (c *responseCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// ResponseCacheMiddleware serves anonymous GET requests from the in-process
+// response cache when it's configured. Only complete 200 responses carrying an
+// ETag are stored, so conditional requests keep working on cache hits.
+func ResponseCacheMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if responses == nil || r.Method != http.MethodGet || r.Header.Get("Authorization") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := r.URL.Path + "?" + r.URL.Query().Encode()
+		if entry, isFound := responses.get(key); isFound {
+			for name, values := range entry.header {
+				w.Header()[name] = append([]string(nil), values...)
+			}
+			w.Header().Set("X-Cache", "HIT")
+
+			if isNotModified(r, entry.header.Get("ETag"), entry.lastModified) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.WriteHeader(entry.status)
+			w.Write(entry.body)
+			return
+		}
+
+		w.Header().Set("X-Cache", "MISS")
+
+		var body bytes.Buffer
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(&body)
+
+		next.ServeHTTP(ww, r)
+
+		if ww.Status() != http.StatusOK || ww.Header().Get("ETag") == "" {
+			return
+		}
+
+		header := ww.Header().Clone()
+		header.Del("X-Cache")
+		lastModified, _ := http.ParseTime(header.Get("Last-Modified"))
+		responses.set(&cachedResponse{
+			key:          key,
+			status:       http.StatusOK,
+			header:       header,
+			body:         body.Bytes(),
+			lastModified: lastModified,
+			storedAt:     time.Now(),
+		})
+	})
+}
+
+// PurgeResponseCache drops every cached response. Writes that don't go through
+// InvalidateResponseCacheMiddleware, like moderation hooks, call it directly.
+func PurgeResponseCache() {
+	if responses == nil {
+		return
+	}
+
+	responses.purge()
+}
+
+// InvalidateResponseCacheMiddleware drops every cached response after a
+// successful write. Admin edits touch listings, details and categories at once
+// so there's little to gain from tracking which entries they affect.
+func InvalidateResponseCacheMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if responses == nil || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		if ww.Status() < http.StatusBadRequest {
+			responses.purge()
+			log.Debug().Str("path", r.URL.Path).Msg("Purged response cache")
+		}
+	})
+}
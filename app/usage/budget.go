@@ -0,0 +1,119 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const budgetRefreshInterval = time.Minute
+
+var ErrMonthlyBudgetExhausted = errors.New("The monthly LLM budget has been used up. Please try again next month")
+
+// monthSpend keeps the spend of the current month in memory so checking the
+// budget doesn't sum the month on every request. It's reloaded every minute to
+// pick up calls recorded by other instances.
+type monthSpend struct {
+	mu          sync.Mutex
+	monthStart  time.Time
+	amount      float64
+	refreshedAt time.Time
+}
+
+var spend = &monthSpend{}
+
+func (s *monthSpend) get(ctx context.Context, now time.Time) (amount float64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := budgetMonthStart(now)
+	if s.monthStart.Equal(start) && now.Sub(s.refreshedAt) < budgetRefreshInterval {
+		return s.amount, nil
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get monthly LLM spend")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	amount, err = findCostSince(ctx, tx, start)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get monthly LLM spend")
+		return
+	}
+
+	s.monthStart, s.amount, s.refreshedAt = start, amount, now
+
+	return amount, nil
+}
+
+func (s *monthSpend) add(cost float64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.monthStart.Equal(budgetMonthStart(at)) {
+		s.amount += cost
+	}
+}
+
+// Budgets follow the UTC calendar month like the provider invoices do.
+func budgetMonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// CheckBudget fails with ErrMonthlyBudgetExhausted once the spend of the month
+// reached the configured budget. The check happens before a call and its cost
+// is only known after, so the last calls of a month can overshoot a little.
+func CheckBudget(ctx context.Context) error {
+	if monthlyBudget <= 0 {
+		return nil
+	}
+
+	spent, err := spend.get(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if spent >= monthlyBudget {
+		return ErrMonthlyBudgetExhausted
+	}
+
+	return nil
+}
+
+func getBudgetStatus(ctx context.Context) (status BudgetStatus, err error) {
+	now := time.Now()
+
+	spent, err := spend.get(ctx, now)
+	if err != nil {
+		return
+	}
+
+	status = BudgetStatus{
+		MonthlyBudgetUsd: monthlyBudget,
+		SpentUsd:         spent,
+		ResetAt:          budgetMonthStart(now).AddDate(0, 1, 0),
+		Prices:           prices,
+	}
+	if monthlyBudget > 0 {
+		remaining := monthlyBudget - spent
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		status.RemainingUsd.SetValid(remaining)
+		status.IsExhausted = spent >= monthlyBudget
+	}
+
+	return status, nil
+}
@@ -0,0 +1,33 @@
+package usage
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lexica-app/lexicapi/app"
+)
+
+// BudgetMiddleware guards the endpoints that call the LLM, it refuses them
+// with a 503 until the next month once the monthly budget is used up.
+func BudgetMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := CheckBudget(r.Context()); err != nil {
+			switch {
+			case errors.Is(err, ErrMonthlyBudgetExhausted):
+				now := time.Now()
+				retryAfter := int(math.Ceil(budgetMonthStart(now).AddDate(0, 1, 0).Sub(now).Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				app.WriteHttpError(w, http.StatusServiceUnavailable, err)
+			default:
+				app.WriteHttpInternalServerError(w)
+			}
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,46 @@
+package usage
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	pool          *pgxpool.Pool
+	prices        = defaultPrices
+	monthlyBudget float64
+
+	ErrNilPool              = errors.New("connection pool can't be nil")
+	ErrInvalidMonthlyBudget = errors.New("Monthly LLM budget can't be negative")
+)
+
+func SetPool(newPool *pgxpool.Pool) {
+	if newPool == nil {
+		log.Fatal().Err(ErrNilPool).Msg("Failed to set connection pool for usage module")
+	}
+
+	pool = newPool
+}
+
+// ConfigurePrices applies the configured spec on top of the built-in price
+// table, see parsePrices for the format.
+func ConfigurePrices(spec string) {
+	p, err := parsePrices(defaultPrices, spec)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure LLM prices")
+	}
+
+	prices = p
+}
+
+// ConfigureBudget sets the global monthly spend in USD after which generation
+// endpoints refuse work, 0 means there's no budget.
+func ConfigureBudget(monthlyBudgetUsd float64) {
+	if monthlyBudgetUsd < 0 {
+		log.Fatal().Err(ErrInvalidMonthlyBudget).Msg("Failed to configure LLM budget")
+	}
+
+	monthlyBudget = monthlyBudgetUsd
+}
@@ -0,0 +1,42 @@
+package usage
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lexica-app/lexicapi/app"
+)
+
+func getUsageReportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	groupBy := r.URL.Query().Get("group_by")
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+
+	report, err := getUsageReport(ctx, groupBy, from, to)
+	if err != nil {
+		switch {
+		case errors.As(err, &ErrInvalidUsageGroupBy):
+			app.WriteHttpError(w, http.StatusBadRequest, err)
+		default:
+			app.WriteHttpInternalServerError(w)
+		}
+
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, report)
+}
+
+func getBudgetStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	status, err := getBudgetStatus(ctx)
+	if err != nil {
+		app.WriteHttpInternalServerError(w)
+		return
+	}
+
+	app.WriteHttpBodyJson(w, http.StatusOK, status)
+}
@@ -0,0 +1,103 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// usageGroupKeys are the only expressions interpolated into the aggregate
+// query, the group is validated before it gets here.
+var usageGroupKeys = map[UsageGroupBy]string{
+	BY_DAY:   "to_char(c.created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD')",
+	BY_TASK:  "c.task",
+	BY_MODEL: "c.model",
+	BY_USER:  "COALESCE(u.email, c.admin, '')",
+}
+
+const usageAggregateColumns = `
+  COUNT(*) AS calls,
+  COUNT(*) FILTER (WHERE c.outcome = 'failed') AS failed_calls,
+  COALESCE(SUM(c.prompt_tokens), 0) AS prompt_tokens,
+  COALESCE(SUM(c.completion_tokens), 0) AS completion_tokens,
+  COALESCE(SUM(c.total_tokens), 0) AS total_tokens,
+  COALESCE(SUM(c.cost_usd), 0) AS cost_usd,
+  COALESCE(AVG(c.latency_ms), 0)::DOUBLE PRECISION AS avg_latency_ms`
+
+func saveLLMCall(ctx context.Context, tx pgx.Tx, c LLMCall) (err error) {
+	q := `
+	INSERT INTO llm_calls(id, task, provider, model, prompt_tokens, completion_tokens, total_tokens, cost_usd, latency_ms, outcome, finish_reason, error, user_id, admin, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	if _, err = tx.Exec(
+		ctx, q,
+		c.Id, c.Task, c.Provider, c.Model, c.PromptTokens, c.CompletionTokens, c.TotalTokens, c.CostUsd,
+		c.LatencyMs, c.Outcome, c.FinishReason, c.Error, c.UserId, c.Admin, c.CreatedAt,
+	); err != nil {
+		log.Err(err).Msg("Failed to save LLM call")
+		return
+	}
+
+	return nil
+}
+
+func findUsageTotal(ctx context.Context, tx pgx.Tx, from, to time.Time) (total UsageAggregate, err error) {
+	q := fmt.Sprintf(`
+	SELECT %s
+	FROM llm_calls c
+	WHERE c.created_at >= $1 AND c.created_at < $2
+	`, usageAggregateColumns)
+
+	if err = pgxscan.Get(ctx, tx, &total, q, from, to); err != nil {
+		log.Err(err).Msg("Failed to find LLM usage total")
+		return
+	}
+
+	return total, nil
+}
+
+func findUsageAggregates(ctx context.Context, tx pgx.Tx, groupBy UsageGroupBy, from, to time.Time) (aggregates []*UsageAggregate, err error) {
+	key := usageGroupKeys[groupBy]
+	userColumn, join, groupColumns, order := "", "", key, "cost_usd DESC, key ASC"
+
+	switch groupBy {
+	case BY_DAY:
+		order = "key ASC"
+	case BY_USER:
+		userColumn = "c.user_id,"
+		join = "LEFT JOIN users u ON u.id = c.user_id"
+		groupColumns = "c.user_id, " + key
+	}
+
+	q := fmt.Sprintf(`
+	SELECT %s AS key, %s %s
+	FROM llm_calls c
+	%s
+	WHERE c.created_at >= $1 AND c.created_at < $2
+	GROUP BY %s
+	ORDER BY %s
+	`, key, userColumn, usageAggregateColumns, join, groupColumns, order)
+
+	if err = pgxscan.Select(ctx, tx, &aggregates, q, from, to); err != nil {
+		log.Err(err).Msg("Failed to find LLM usage aggregates")
+		return
+	}
+
+	return aggregates, nil
+}
+
+func findCostSince(ctx context.Context, tx pgx.Tx, since time.Time) (cost float64, err error) {
+	q := "SELECT COALESCE(SUM(cost_usd), 0) FROM llm_calls WHERE created_at >= $1"
+
+	if err = tx.QueryRow(ctx, q, since).Scan(&cost); err != nil {
+		log.Err(err).Msg("Failed to find LLM cost")
+		return
+	}
+
+	return cost, nil
+}
@@ -0,0 +1,19 @@
+package usage
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/auth"
+)
+
+func AdminRouter() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(auth.SuperadminAuthMiddleware)
+	r.Use(app.CacheControl(0))
+
+	r.Get("/", getUsageReportHandler)
+	r.Get("/budget", getBudgetStatusHandler)
+
+	return r
+}
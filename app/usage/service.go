@@ -0,0 +1,134 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Complete calls the LLM and records the call with its tokens, cost and
+// latency against whoever is on the request context, a user or the
+// superadmin. Recording never fails the call, it's only logged.
+func Complete(ctx context.Context, llm adapters.LLM, task adapters.LLMTask, req adapters.LLMRequest) (res adapters.LLMResponse, err error) {
+	startedAt := time.Now()
+	res, err = llm.Complete(ctx, req)
+
+	recordCompletion(ctx, llm, task, req, res, err, startedAt)
+
+	return res, err
+}
+
+// CompleteStream is Complete for streamed completions, see
+// adapters.CompleteStream.
+func CompleteStream(ctx context.Context, llm adapters.LLM, task adapters.LLMTask, req adapters.LLMRequest, onDelta func(delta string) error) (res adapters.LLMResponse, err error) {
+	startedAt := time.Now()
+	res, err = adapters.CompleteStream(ctx, llm, req, onDelta)
+
+	recordCompletion(ctx, llm, task, req, res, err, startedAt)
+
+	return res, err
+}
+
+func recordCompletion(ctx context.Context, llm adapters.LLM, task adapters.LLMTask, req adapters.LLMRequest, res adapters.LLMResponse, err error, startedAt time.Time) {
+	c := NewLLMCall(task, llm.Provider(), req, res, err, time.Since(startedAt))
+	if user, ok := ctx.Value(auth.UserInfoCtx).(auth.User); ok {
+		c.UserId = &user.Id
+	}
+	if admin, ok := ctx.Value(auth.SuperadminInfoCtx).(string); ok {
+		c.Admin = null.StringFrom(admin)
+	}
+
+	// The call was paid for even when the client already hung up
+	recordLLMCall(context.Background(), c)
+}
+
+func recordLLMCall(ctx context.Context, c LLMCall) {
+	// Nothing to record into, like in tests of the modules calling LLMs
+	if pool == nil {
+		return
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to record LLM call")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = saveLLMCall(ctx, tx, c); err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to record LLM call")
+		return
+	}
+
+	spend.add(c.CostUsd, c.CreatedAt)
+}
+
+// getUsageReport defaults to the current UTC month, to is inclusive.
+func getUsageReport(ctx context.Context, groupByStr, fromStr, toStr string) (report UsageReport, err error) {
+	groupBy := BY_DAY
+	if groupByStr != "" {
+		groupBy = UsageGroupBy(groupByStr)
+	}
+	if err = validateUsageGroupBy(groupBy); err != nil {
+		return
+	}
+
+	now := time.Now()
+	from, to := budgetMonthStart(now), budgetMonthStart(now).AddDate(0, 1, 0)
+	if fromStr != "" {
+		if from, err = time.Parse("2006-01-02", fromStr); err != nil {
+			return report, ErrInvalidUsageRange
+		}
+	}
+	if toStr != "" {
+		if to, err = time.Parse("2006-01-02", toStr); err != nil {
+			return report, ErrInvalidUsageRange
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		return report, ErrInvalidUsageRange
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("Failed to get usage report")
+		return
+	}
+
+	defer tx.Rollback(ctx)
+
+	total, err := findUsageTotal(ctx, tx, from, to)
+	if err != nil {
+		return
+	}
+
+	groups, err := findUsageAggregates(ctx, tx, groupBy, from, to)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Err(err).Msg("Failed to get usage report")
+		return
+	}
+
+	report = UsageReport{
+		GroupBy: groupBy,
+		From:    from,
+		To:      to,
+		Total:   total,
+		Groups:  groups,
+	}
+
+	return report, nil
+}
@@ -0,0 +1,158 @@
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lexica-app/lexicapi/adapters"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+type LLMCallOutcome string
+
+const (
+	SUCCESS LLMCallOutcome = "success"
+	FAILED  LLMCallOutcome = "failed"
+)
+
+var ErrInvalidPrices = errors.New("Invalid LLM prices")
+
+type LLMCall struct {
+	Id               ulid.ULID        `json:"id"`
+	Task             adapters.LLMTask `json:"task"`
+	Provider         string           `json:"provider"`
+	Model            string           `json:"model"`
+	PromptTokens     int              `json:"prompt_tokens"`
+	CompletionTokens int              `json:"completion_tokens"`
+	TotalTokens      int              `json:"total_tokens"`
+	CostUsd          float64          `json:"cost_usd"`
+	LatencyMs        int64            `json:"latency_ms"`
+	Outcome          LLMCallOutcome   `json:"outcome"`
+	FinishReason     null.String      `json:"finish_reason"`
+	Error            null.String      `json:"error"`
+	UserId           *ulid.ULID       `json:"user_id"`
+	Admin            null.String      `json:"admin"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+// NewLLMCall describes a finished call, res is ignored when the call failed.
+// The model the provider answered with is preferred over the requested one
+// since it's what gets billed.
+func NewLLMCall(task adapters.LLMTask, provider string, req adapters.LLMRequest, res adapters.LLMResponse, callErr error, latency time.Duration) LLMCall {
+	c := LLMCall{
+		Id:        ulid.Make(),
+		Task:      task,
+		Provider:  provider,
+		Model:     req.Model,
+		LatencyMs: latency.Milliseconds(),
+		Outcome:   SUCCESS,
+		CreatedAt: time.Now(),
+	}
+
+	if callErr != nil {
+		c.Outcome = FAILED
+		c.Error = null.StringFrom(callErr.Error())
+
+		var llmErr *adapters.LLMError
+		if errors.As(callErr, &llmErr) {
+			c.Error = null.StringFrom(llmErr.Detail())
+		}
+
+		return c
+	}
+
+	if res.Model != "" {
+		c.Model = res.Model
+	}
+	c.PromptTokens = res.Usage.PromptTokens
+	c.CompletionTokens = res.Usage.CompletionTokens
+	c.TotalTokens = res.Usage.TotalTokens
+	c.FinishReason = null.NewString(string(res.FinishReason), res.FinishReason != "")
+	c.CostUsd = priceFor(c.Model).Cost(c.PromptTokens, c.CompletionTokens)
+
+	return c
+}
+
+// Price is in USD per 1K tokens.
+type Price struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+func (p Price) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.Prompt + float64(completionTokens)*p.Completion) / 1000
+}
+
+var defaultPrices = map[string]Price{
+	"gpt-3.5-turbo":     {Prompt: 0.0015, Completion: 0.002},
+	"gpt-3.5-turbo-16k": {Prompt: 0.003, Completion: 0.004},
+	"gpt-4":             {Prompt: 0.03, Completion: 0.06},
+	"gpt-4-32k":         {Prompt: 0.06, Completion: 0.12},
+	"gpt-4o":            {Prompt: 0.005, Completion: 0.015},
+	"gpt-4o-mini":       {Prompt: 0.00015, Completion: 0.0006},
+}
+
+// priceFor matches the model exactly first and then by the longest known
+// prefix, so dated snapshots like "gpt-3.5-turbo-16k-0613" use the price of
+// their family. Unknown models cost nothing rather than failing the call.
+func priceFor(model string) Price {
+	if p, ok := prices[model]; ok {
+		return p
+	}
+
+	var (
+		price   Price
+		longest int
+	)
+	for name, p := range prices {
+		if strings.HasPrefix(model, name) && len(name) > longest {
+			price, longest = p, len(name)
+		}
+	}
+
+	return price
+}
+
+// parsePrices reads a comma separated list of model=prompt/completion pairs in
+// USD per 1K tokens, e.g. "gpt-4o-mini=0.00015/0.0006,gpt-4o=0.005/0.015".
+func parsePrices(defaults map[string]Price, spec string) (map[string]Price, error) {
+	p := make(map[string]Price, len(defaults))
+	for model, price := range defaults {
+		p[model] = price
+	}
+
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return p, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		model, value, ok := strings.Cut(pair, "=")
+		model, value = strings.TrimSpace(model), strings.TrimSpace(value)
+		if !ok || model == "" {
+			return p, fmt.Errorf("%w: %q is not a model=prompt/completion pair", ErrInvalidPrices, pair)
+		}
+
+		promptStr, completionStr, ok := strings.Cut(value, "/")
+		if !ok {
+			return p, fmt.Errorf("%w: %s needs a prompt/completion price", ErrInvalidPrices, model)
+		}
+
+		prompt, err := strconv.ParseFloat(strings.TrimSpace(promptStr), 64)
+		if err != nil || prompt < 0 {
+			return p, fmt.Errorf("%w: prompt price of %s must be a non-negative number", ErrInvalidPrices, model)
+		}
+		completion, err := strconv.ParseFloat(strings.TrimSpace(completionStr), 64)
+		if err != nil || completion < 0 {
+			return p, fmt.Errorf("%w: completion price of %s must be a non-negative number", ErrInvalidPrices, model)
+		}
+
+		p[model] = Price{Prompt: prompt, Completion: completion}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,19 @@
+package usage
+
+import (
+	"github.com/jellydator/validation"
+)
+
+var (
+	ErrInvalidUsageGroupBy = validation.NewError("usage:invalid_group_by", "Usage can only be grouped by day, task, model or user")
+	ErrInvalidUsageRange   = validation.NewError("usage:invalid_range", "Usage range must be dates formatted as YYYY-MM-DD with from not after to")
+)
+
+func validateUsageGroupBy(groupBy UsageGroupBy) error {
+	switch groupBy {
+	case BY_DAY, BY_TASK, BY_MODEL, BY_USER:
+		return nil
+	}
+
+	return ErrInvalidUsageGroupBy
+}
@@ -0,0 +1,49 @@
+package usage
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+type UsageGroupBy string
+
+const (
+	BY_DAY   UsageGroupBy = "day"
+	BY_TASK  UsageGroupBy = "task"
+	BY_MODEL UsageGroupBy = "model"
+	BY_USER  UsageGroupBy = "user"
+)
+
+// UsageAggregate sums the calls of one group, Key is the day, task, model or
+// the email of the user or admin depending on how the report is grouped.
+type UsageAggregate struct {
+	Key              string     `json:"key"`
+	UserId           *ulid.ULID `json:"user_id,omitempty"`
+	Calls            int64      `json:"calls"`
+	FailedCalls      int64      `json:"failed_calls"`
+	PromptTokens     int64      `json:"prompt_tokens"`
+	CompletionTokens int64      `json:"completion_tokens"`
+	TotalTokens      int64      `json:"total_tokens"`
+	CostUsd          float64    `json:"cost_usd"`
+	AvgLatencyMs     float64    `json:"avg_latency_ms"`
+}
+
+type UsageReport struct {
+	GroupBy UsageGroupBy      `json:"group_by"`
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Total   UsageAggregate    `json:"total"`
+	Groups  []*UsageAggregate `json:"groups"`
+}
+
+// BudgetStatus has a null remaining amount when no budget is configured.
+type BudgetStatus struct {
+	MonthlyBudgetUsd float64          `json:"monthly_budget_usd"`
+	SpentUsd         float64          `json:"spent_usd"`
+	RemainingUsd     null.Float       `json:"remaining_usd"`
+	IsExhausted      bool             `json:"is_exhausted"`
+	ResetAt          time.Time        `json:"reset_at"`
+	Prices           map[string]Price `json:"prices"`
+}
@@ -0,0 +1,2 @@
+ALTER TABLE article_texts
+DROP COLUMN IF EXISTS document;
@@ -0,0 +1,2 @@
+ALTER TABLE article_texts
+ADD COLUMN IF NOT EXISTS document JSONB;
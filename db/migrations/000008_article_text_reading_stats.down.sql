@@ -0,0 +1,2 @@
+ALTER TABLE article_texts DROP COLUMN IF EXISTS reading_time_minutes;
+ALTER TABLE article_texts DROP COLUMN IF EXISTS word_count;
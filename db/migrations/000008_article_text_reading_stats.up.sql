@@ -0,0 +1,8 @@
+ALTER TABLE article_texts ADD COLUMN IF NOT EXISTS word_count INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE article_texts ADD COLUMN IF NOT EXISTS reading_time_minutes INTEGER DEFAULT 0 NOT NULL;
+
+UPDATE article_texts
+SET word_count = (CASE WHEN BTRIM(content) = '' THEN 0 ELSE ARRAY_LENGTH(REGEXP_SPLIT_TO_ARRAY(BTRIM(content), '\s+'), 1) END);
+
+UPDATE article_texts
+SET reading_time_minutes = CEIL(word_count / 200.0);
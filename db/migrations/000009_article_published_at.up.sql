@@ -0,0 +1,3 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+UPDATE articles SET published_at = created_at WHERE is_published IS TRUE AND published_at IS NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS collection_articles_article_id_idx;
+DROP INDEX IF EXISTS article_texts_listing_idx;
+DROP INDEX IF EXISTS articles_author_idx;
+DROP INDEX IF EXISTS articles_source_idx;
+DROP INDEX IF EXISTS articles_published_at_idx;
+DROP INDEX IF EXISTS articles_category_id_idx;
+DROP INDEX IF EXISTS articles_listing_title_idx;
+DROP INDEX IF EXISTS articles_listing_date_idx;
@@ -0,0 +1,10 @@
+CREATE INDEX IF NOT EXISTS articles_listing_date_idx ON articles((COALESCE(published_at, created_at)), id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS articles_listing_title_idx ON articles(title, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS articles_category_id_idx ON articles(category_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS articles_published_at_idx ON articles(published_at) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS articles_source_idx ON articles(LOWER(source)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS articles_author_idx ON articles(LOWER(author)) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS article_texts_listing_idx ON article_texts(article_id, difficulty) INCLUDE (word_count, reading_time_minutes) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS collection_articles_article_id_idx ON collection_articles(article_id) WHERE deleted_at IS NULL;
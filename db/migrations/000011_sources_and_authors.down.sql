@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS articles_author_id_idx;
+DROP INDEX IF EXISTS articles_source_id_idx;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS author_id;
+ALTER TABLE articles DROP COLUMN IF EXISTS source_id;
+
+DROP TABLE IF EXISTS authors;
+DROP TABLE IF EXISTS sources;
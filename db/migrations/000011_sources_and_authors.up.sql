@@ -0,0 +1,62 @@
+CREATE TABLE IF NOT EXISTS sources (
+  id BYTEA NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  domain VARCHAR(255),
+  logo_url TEXT,
+  aliases TEXT[] DEFAULT '{}' NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+  updated_at TIMESTAMPTZ,
+  deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT sources_name_unique UNIQUE NULLS NOT DISTINCT (name, deleted_at),
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE IF NOT EXISTS authors (
+  id BYTEA NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  aliases TEXT[] DEFAULT '{}' NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+  updated_at TIMESTAMPTZ,
+  deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT authors_name_unique UNIQUE NULLS NOT DISTINCT (name, deleted_at),
+  PRIMARY KEY(id)
+);
+
+-- Ids are built as ULIDs: a millisecond timestamp followed by 10 random bytes
+INSERT INTO sources(id, name, domain)
+SELECT
+  OVERLAY(UUID_SEND(GEN_RANDOM_UUID()) PLACING DECODE(LPAD(TO_HEX((EXTRACT(EPOCH FROM CLOCK_TIMESTAMP()) * 1000)::BIGINT), 12, '0'), 'hex') FROM 1 FOR 6),
+  MIN(BTRIM(source)),
+  MODE() WITHIN GROUP (ORDER BY LOWER(SUBSTRING(original_url FROM '^[a-zA-Z]+://(?:www\.)?([^/:?#]+)')))
+FROM articles
+GROUP BY LOWER(BTRIM(source));
+
+INSERT INTO authors(id, name)
+SELECT
+  OVERLAY(UUID_SEND(GEN_RANDOM_UUID()) PLACING DECODE(LPAD(TO_HEX((EXTRACT(EPOCH FROM CLOCK_TIMESTAMP()) * 1000)::BIGINT), 12, '0'), 'hex') FROM 1 FOR 6),
+  MIN(BTRIM(author))
+FROM articles
+WHERE author IS NOT NULL AND BTRIM(author) != ''
+GROUP BY LOWER(BTRIM(author));
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS source_id BYTEA;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS author_id BYTEA;
+
+UPDATE articles a
+SET source_id = s.id, source = s.name
+FROM sources s
+WHERE LOWER(BTRIM(a.source)) = LOWER(s.name);
+
+UPDATE articles a
+SET author_id = au.id, author = au.name
+FROM authors au
+WHERE LOWER(BTRIM(a.author)) = LOWER(au.name);
+
+UPDATE articles SET author = NULL WHERE author_id IS NULL;
+
+ALTER TABLE articles ALTER COLUMN source_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS articles_source_id_idx ON articles(source_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS articles_author_id_idx ON articles(author_id) WHERE deleted_at IS NULL;
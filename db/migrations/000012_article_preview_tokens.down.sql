@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS article_preview_tokens_article_id_idx;
+
+DROP TABLE IF EXISTS article_preview_tokens;
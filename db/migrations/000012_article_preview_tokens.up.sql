@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS article_preview_tokens (
+  id BYTEA NOT NULL,
+  article_id BYTEA NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+  revoked_at TIMESTAMPTZ,
+
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX IF NOT EXISTS article_preview_tokens_article_id_idx ON article_preview_tokens (article_id);
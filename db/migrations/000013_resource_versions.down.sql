@@ -0,0 +1,4 @@
+ALTER TABLE collections DROP COLUMN IF EXISTS version;
+ALTER TABLE article_categories DROP COLUMN IF EXISTS version;
+ALTER TABLE article_texts DROP COLUMN IF EXISTS version;
+ALTER TABLE articles DROP COLUMN IF EXISTS version;
@@ -0,0 +1,4 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS version INTEGER DEFAULT 1 NOT NULL;
+ALTER TABLE article_texts ADD COLUMN IF NOT EXISTS version INTEGER DEFAULT 1 NOT NULL;
+ALTER TABLE article_categories ADD COLUMN IF NOT EXISTS version INTEGER DEFAULT 1 NOT NULL;
+ALTER TABLE collections ADD COLUMN IF NOT EXISTS version INTEGER DEFAULT 1 NOT NULL;
@@ -0,0 +1,22 @@
+-- Articles still in "Uncategorized" move back into the deleted categories they
+-- were taken out of, the ones moved elsewhere since are left alone
+UPDATE articles a
+SET
+  category_id = r.category_id,
+  updated_at = NOW(),
+  version = a.version + 1
+FROM article_category_reassignments r
+WHERE
+  a.id = r.article_id AND
+  a.category_id = (SELECT id FROM article_categories WHERE name = 'Uncategorized' AND deleted_at IS NULL);
+
+-- "Uncategorized" is only removed when the up migration created it and
+-- nothing else was put into it since
+DELETE FROM article_categories ac
+WHERE
+  ac.name = 'Uncategorized' AND
+  ac.deleted_at IS NULL AND
+  EXISTS (SELECT 1 FROM article_category_reassignments WHERE is_category_created) AND
+  NOT EXISTS (SELECT 1 FROM articles WHERE category_id = ac.id);
+
+DROP TABLE IF EXISTS article_category_reassignments;
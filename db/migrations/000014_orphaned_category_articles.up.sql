@@ -0,0 +1,32 @@
+-- The articles moved out of deleted categories are recorded with the category
+-- they were in, so migrating down can move them back
+CREATE TABLE IF NOT EXISTS article_category_reassignments (
+  article_id BYTEA PRIMARY KEY,
+  category_id BYTEA NOT NULL,
+  is_category_created BOOLEAN NOT NULL
+);
+
+INSERT INTO article_category_reassignments(article_id, category_id, is_category_created)
+SELECT
+  a.id,
+  a.category_id,
+  NOT EXISTS (SELECT 1 FROM article_categories WHERE name = 'Uncategorized' AND deleted_at IS NULL)
+FROM articles a
+WHERE NOT EXISTS (SELECT 1 FROM article_categories ac WHERE ac.id = a.category_id AND ac.deleted_at IS NULL);
+
+-- Articles left in deleted categories move into "Uncategorized", which is only
+-- created when there is something to move into it
+INSERT INTO article_categories(id, name)
+SELECT
+  OVERLAY(UUID_SEND(GEN_RANDOM_UUID()) PLACING DECODE(LPAD(TO_HEX((EXTRACT(EPOCH FROM CLOCK_TIMESTAMP()) * 1000)::BIGINT), 12, '0'), 'hex') FROM 1 FOR 6),
+  'Uncategorized'
+WHERE
+  EXISTS (SELECT 1 FROM article_category_reassignments) AND
+  NOT EXISTS (SELECT 1 FROM article_categories WHERE name = 'Uncategorized' AND deleted_at IS NULL);
+
+UPDATE articles a
+SET
+  category_id = (SELECT id FROM article_categories WHERE name = 'Uncategorized' AND deleted_at IS NULL),
+  updated_at = NOW(),
+  version = version + 1
+WHERE a.id IN (SELECT article_id FROM article_category_reassignments);
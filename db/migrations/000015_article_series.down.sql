@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS series_progress;
+DROP TABLE IF EXISTS series_articles;
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS series (
+  id BYTEA NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT,
+  is_published BOOLEAN DEFAULT FALSE NOT NULL,
+  published_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+  updated_at TIMESTAMPTZ,
+  deleted_at TIMESTAMPTZ,
+
+  PRIMARY KEY(id)
+);
+
+-- An article is part of at most one series so it has a single next and previous part
+CREATE TABLE IF NOT EXISTS series_articles (
+  series_id BYTEA NOT NULL,
+  article_id BYTEA NOT NULL,
+  position INTEGER NOT NULL,
+
+  CONSTRAINT series_articles_article_unique UNIQUE (article_id),
+  CONSTRAINT series_articles_position_unique UNIQUE (series_id, position),
+  PRIMARY KEY(series_id, article_id)
+);
+
+CREATE TABLE IF NOT EXISTS series_progress (
+  user_id BYTEA NOT NULL,
+  series_id BYTEA NOT NULL,
+  article_id BYTEA NOT NULL,
+  read_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  PRIMARY KEY(user_id, series_id, article_id)
+);
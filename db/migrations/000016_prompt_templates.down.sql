@@ -0,0 +1,3 @@
+ALTER TABLE article_texts DROP COLUMN IF EXISTS prompt_version;
+
+DROP TABLE IF EXISTS prompt_templates;
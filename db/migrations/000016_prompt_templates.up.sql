@@ -0,0 +1,18 @@
+-- Version 0 of every prompt is built into the API, stored rows are versions 1 and up
+CREATE TABLE IF NOT EXISTS prompt_templates (
+  id BYTEA NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  version INTEGER NOT NULL,
+  system_template TEXT NOT NULL,
+  user_template TEXT NOT NULL,
+  note VARCHAR(255),
+  is_active BOOLEAN DEFAULT FALSE NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  CONSTRAINT prompt_templates_version_unique UNIQUE (name, version),
+  PRIMARY KEY(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS prompt_templates_active_idx ON prompt_templates (name) WHERE is_active;
+
+ALTER TABLE article_texts ADD COLUMN IF NOT EXISTS prompt_version VARCHAR(60);
@@ -0,0 +1,16 @@
+-- key is the sha256 of task, prompt version, model and normalized input text
+CREATE TABLE IF NOT EXISTS assistant_response_cache (
+  key BYTEA NOT NULL,
+  task VARCHAR(50) NOT NULL,
+  prompt_version VARCHAR(60) NOT NULL,
+  model VARCHAR(100) NOT NULL,
+  input_text TEXT NOT NULL,
+  output_text TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+
+  PRIMARY KEY(key)
+);
+
+CREATE INDEX IF NOT EXISTS assistant_response_cache_expires_at_idx ON assistant_response_cache (expires_at);
+CREATE INDEX IF NOT EXISTS assistant_response_cache_task_idx ON assistant_response_cache (task, prompt_version);
@@ -0,0 +1,11 @@
+-- One row per user and quota window, period_start is the local date the window starts on
+CREATE TABLE IF NOT EXISTS assistant_quota_usage (
+  user_id BYTEA NOT NULL,
+  period VARCHAR(10) NOT NULL,
+  period_start DATE NOT NULL,
+  requests INTEGER DEFAULT 0 NOT NULL,
+  tokens BIGINT DEFAULT 0 NOT NULL,
+  updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  PRIMARY KEY(user_id, period, period_start)
+);
@@ -0,0 +1,23 @@
+-- One row per LLM call, user_id is set for user requests and admin for superadmin requests
+CREATE TABLE IF NOT EXISTS llm_calls (
+  id BYTEA NOT NULL,
+  task VARCHAR(50) NOT NULL,
+  provider VARCHAR(30) NOT NULL,
+  model VARCHAR(100) NOT NULL,
+  prompt_tokens INTEGER DEFAULT 0 NOT NULL,
+  completion_tokens INTEGER DEFAULT 0 NOT NULL,
+  total_tokens INTEGER DEFAULT 0 NOT NULL,
+  cost_usd DOUBLE PRECISION DEFAULT 0 NOT NULL,
+  latency_ms INTEGER NOT NULL,
+  outcome VARCHAR(20) NOT NULL,
+  finish_reason VARCHAR(30),
+  error TEXT,
+  user_id BYTEA,
+  admin VARCHAR(255),
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX IF NOT EXISTS llm_calls_created_at_idx ON llm_calls (created_at);
+CREATE INDEX IF NOT EXISTS llm_calls_user_id_idx ON llm_calls (user_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS assistant_chat_messages;
+DROP TABLE IF EXISTS assistant_chats;
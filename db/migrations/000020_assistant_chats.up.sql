@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS assistant_chats (
+  id BYTEA NOT NULL,
+  user_id BYTEA NOT NULL,
+  article_id BYTEA NOT NULL,
+  difficulty VARCHAR(25) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+  updated_at TIMESTAMPTZ,
+
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE IF NOT EXISTS assistant_chat_messages (
+  id BYTEA NOT NULL,
+  chat_id BYTEA NOT NULL,
+  role VARCHAR(20) NOT NULL,
+  content TEXT NOT NULL,
+  prompt_version VARCHAR(60),
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX IF NOT EXISTS assistant_chats_user_id_idx ON assistant_chats (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS assistant_chat_messages_chat_id_idx ON assistant_chat_messages (chat_id, id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS assistant_annotations (
+  id BYTEA NOT NULL,
+  user_id BYTEA NOT NULL,
+  article_id BYTEA NOT NULL,
+  difficulty VARCHAR(25) NOT NULL,
+  kind VARCHAR(20) NOT NULL,
+  start_offset INTEGER NOT NULL,
+  end_offset INTEGER NOT NULL,
+  text TEXT NOT NULL,
+  result TEXT NOT NULL,
+  prompt_version VARCHAR(60) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX IF NOT EXISTS assistant_annotations_user_article_idx ON assistant_annotations (user_id, article_id, difficulty, start_offset);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS dictionary_entries;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS dictionary_entries (
+  id BYTEA NOT NULL,
+  lemma VARCHAR(100) NOT NULL,
+  part_of_speech VARCHAR(30) NOT NULL DEFAULT '',
+  definitions TEXT[] NOT NULL,
+  examples TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+  updated_at TIMESTAMPTZ,
+
+  PRIMARY KEY(id),
+  CONSTRAINT dictionary_entries_lemma_part_of_speech_unique UNIQUE (lemma, part_of_speech)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS notebook_notes;
+DROP TABLE IF EXISTS notebook_cards;
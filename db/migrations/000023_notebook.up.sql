@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS notebook_cards (
+  id BYTEA NOT NULL,
+  user_id BYTEA NOT NULL,
+  word VARCHAR(255) NOT NULL,
+  meaning TEXT NOT NULL,
+  article_id BYTEA,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE IF NOT EXISTS notebook_notes (
+  id BYTEA NOT NULL,
+  user_id BYTEA NOT NULL,
+  article_id BYTEA,
+  title VARCHAR(255) NOT NULL,
+  content TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX IF NOT EXISTS notebook_cards_user_id_idx ON notebook_cards (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS notebook_notes_user_id_idx ON notebook_notes (user_id, id DESC);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS assistant_history (
+  id BYTEA NOT NULL,
+  user_id BYTEA NOT NULL,
+  kind VARCHAR(20) NOT NULL,
+  input_text TEXT NOT NULL,
+  output_text TEXT NOT NULL,
+  prompt_version VARCHAR(60) NOT NULL,
+  article_id BYTEA,
+  article_title VARCHAR(255),
+  difficulty VARCHAR(25),
+  paragraph TEXT,
+  is_favourite BOOLEAN DEFAULT FALSE NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX IF NOT EXISTS assistant_history_user_id_idx ON assistant_history (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS assistant_history_favourite_idx ON assistant_history (user_id, id DESC) WHERE is_favourite = TRUE;
@@ -0,0 +1,2 @@
+ALTER TABLE article_texts DROP COLUMN IF EXISTS moderation_status;
+DROP TABLE IF EXISTS moderation_flags;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS moderation_flags (
+  id BYTEA NOT NULL,
+  subject_kind VARCHAR(20) NOT NULL,
+  subject_id BYTEA,
+  user_id BYTEA,
+  admin VARCHAR(255),
+  provider VARCHAR(20),
+  text TEXT NOT NULL,
+  subject_hash VARCHAR(64) NOT NULL,
+  decision VARCHAR(10) NOT NULL,
+  reasons JSONB NOT NULL,
+  status VARCHAR(10) NOT NULL,
+  reviewed_by VARCHAR(255),
+  review_note TEXT,
+  reviewed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+  PRIMARY KEY(id)
+);
+
+CREATE INDEX IF NOT EXISTS moderation_flags_status_idx ON moderation_flags (status, id DESC);
+CREATE INDEX IF NOT EXISTS moderation_flags_subject_idx ON moderation_flags (subject_kind, subject_id);
+
+-- Texts held for review stay hidden from readers until a flag on them is approved
+ALTER TABLE article_texts ADD COLUMN IF NOT EXISTS moderation_status VARCHAR(10) DEFAULT 'APPROVED' NOT NULL;
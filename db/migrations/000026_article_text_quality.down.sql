@@ -0,0 +1 @@
+ALTER TABLE article_texts DROP COLUMN IF EXISTS quality;
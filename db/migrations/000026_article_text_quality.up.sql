@@ -0,0 +1 @@
+ALTER TABLE article_texts ADD COLUMN IF NOT EXISTS quality JSONB;
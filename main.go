@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	stdlog "log"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -11,7 +13,12 @@ import (
 	"github.com/lexica-app/lexicapi/app/article"
 	"github.com/lexica-app/lexicapi/app/assistant"
 	"github.com/lexica-app/lexicapi/app/auth"
+	"github.com/lexica-app/lexicapi/app/dictionary"
 	"github.com/lexica-app/lexicapi/app/friend"
+	"github.com/lexica-app/lexicapi/app/moderation"
+	"github.com/lexica-app/lexicapi/app/notebook"
+	"github.com/lexica-app/lexicapi/app/prompt"
+	"github.com/lexica-app/lexicapi/app/usage"
 	"github.com/lexica-app/lexicapi/db"
 	"github.com/rs/zerolog/log"
 )
@@ -25,15 +32,57 @@ func main() {
 	// App Configurations
 	app.ConfigureLogger(config)
 	app.ConfigureCors(config)
+	app.ConfigureResponseCache(config)
 
 	// Configure Adapters and Dependency Injection
 	pool := db.CreateConnPool(config.DbDsn)
-	openaiAdapter := adapters.ConfigureOpenAIAdapter(config.OpenAIOrganizationId, config.OpenAIAPIKey)
+	llmAdapter := adapters.ConfigureLLMAdapter(adapters.LLMConfig{
+		Provider:              config.LLMProvider,
+		OpenAIOrganizationId:  config.OpenAIOrganizationId,
+		OpenAIAPIKey:          config.OpenAIAPIKey,
+		AzureOpenAIEndpoint:   config.AzureOpenAIEndpoint,
+		AzureOpenAIAPIKey:     config.AzureOpenAIAPIKey,
+		AzureOpenAIAPIVersion: config.AzureOpenAIAPIVersion,
+		AzureOpenAIDeployment: config.AzureOpenAIDeployment,
+		BaseUrl:               config.LLMBaseUrl,
+		APIKey:                config.LLMAPIKey,
+		Resilience: adapters.ResilienceConfig{
+			MaxAttempts:      config.LLMMaxAttempts,
+			BaseDelay:        time.Duration(config.LLMRetryBaseDelayMs) * time.Millisecond,
+			MaxDelay:         time.Duration(config.LLMRetryMaxDelayMs) * time.Millisecond,
+			BreakerThreshold: config.LLMBreakerThreshold,
+			BreakerCooldown:  time.Duration(config.LLMBreakerCooldownSeconds) * time.Second,
+		},
+	})
+	moderator := adapters.ConfigureModerator(adapters.ModeratorConfig{
+		Provider:             config.ModerationProvider,
+		Model:                config.ModerationModel,
+		OpenAIOrganizationId: config.OpenAIOrganizationId,
+		OpenAIAPIKey:         config.OpenAIAPIKey,
+	})
+	adapters.ConfigureLLMTaskSettings(config.LLMModel, map[adapters.LLMTask]string{
+		adapters.LLMTaskArticleText:    config.LLMArticleTextSettings,
+		adapters.LLMTaskArticleSummary: config.LLMArticleSummarySettings,
+		adapters.LLMTaskSimplify:       config.LLMSimplifySettings,
+		adapters.LLMTaskExplain:        config.LLMExplainSettings,
+		adapters.LLMTaskChat:           config.LLMChatSettings,
+	})
 
 	article.SetPool(pool)
-	article.SetOpenAIAdapter(openaiAdapter)
+	article.SetLLMAdapter(llmAdapter)
+	article.ConfigureTrashRetention(config.TrashRetentionDays)
+	article.ConfigurePreviewTokens(config.LexicaJwtIssuer, config.ArticlePreviewSecret)
+	article.ConfigureChunking(config.ArticleChunkTokens, config.ArticleChunkConcurrency)
+	article.ConfigureQualityChecks(config.ArticleQualityMaxAttempts)
 
-	assistant.SetOpenAIAdapter(openaiAdapter)
+	assistant.SetPool(pool)
+	assistant.SetLLMAdapter(llmAdapter)
+	assistant.ConfigureResponseCache(config.AssistantCacheTtlHours, config.AssistantCacheMemorySize)
+	assistant.ConfigureQuotas(config.AssistantQuotaTimezone, map[string]string{
+		auth.STUDENT.String.String:  config.AssistantQuotaPelajar,
+		auth.EDUCATOR.String.String: config.AssistantQuotaPengajar,
+		auth.CIVILIAN.String.String: config.AssistantQuotaUmum,
+	})
 
 	auth.SetPool(pool)
 	auth.ConfigureGoogleOAuth(config.GoogleOAuthClientId)
@@ -46,8 +95,37 @@ func main() {
 	)
 	auth.ConfigureSuperadmin(config.LexicaSuperadminEmail, config.LexicaSuperadminPassword)
 
+	dictionary.SetPool(pool)
+
 	friend.SetPool(pool)
 
+	moderation.SetPool(pool)
+	if moderator != nil {
+		moderation.SetModerator(moderator)
+	}
+	moderation.ConfigureRules(
+		config.ModerationBlockTerms,
+		config.ModerationReviewTerms,
+		config.ModerationBlockPattern,
+		config.ModerationReviewPattern,
+		config.ModerationBlockCategories,
+		config.ModerationFailOpen,
+	)
+	moderation.OnApprove(moderation.ARTICLE_TEXT, article.ApproveModeratedText)
+	moderation.OnReject(moderation.ARTICLE_TEXT, article.TrashModeratedText)
+
+	notebook.SetPool(pool)
+
+	prompt.SetPool(pool)
+
+	usage.SetPool(pool)
+	usage.ConfigurePrices(config.LLMPrices)
+	usage.ConfigureBudget(config.LLMMonthlyBudgetUsd)
+
+	// Background jobs
+	go article.StartTrashPurgeJob(context.Background())
+	go assistant.StartResponseCachePurgeJob(context.Background())
+
 	r := chi.NewRouter()
 
 	// Global middlewares
@@ -65,6 +143,10 @@ func main() {
 	r.Group(func(r chi.Router) {
 		r.Mount("/admin/auth", auth.AdminRouter())
 		r.Mount("/admin/article", article.AdminRouter())
+		r.Mount("/admin/prompt", prompt.AdminRouter())
+		r.Mount("/admin/assistant", assistant.AdminRouter())
+		r.Mount("/admin/usage", usage.AdminRouter())
+		r.Mount("/admin/moderation", moderation.AdminRouter())
 	})
 
 	// Normal Routes
@@ -73,6 +155,7 @@ func main() {
 		r.Mount("/article", article.Router())
 		r.Mount("/assistant", assistant.Router())
 		r.Mount("/friend", friend.Router())
+		r.Mount("/notebook", notebook.Router())
 	})
 
 	log.Info().Msgf("Running server on port %s in %s mode...", config.Port, config.Env)
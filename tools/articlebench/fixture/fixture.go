@@ -0,0 +1,153 @@
+// Package fixture seeds the articles the listing benchmarks run against. It's
+// shared by the articlebench tool and the listing benchmarks in app/article so
+// both measure the same data.
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	Source       = "lexica-bench"
+	Author       = "Bench Author"
+	CategoryName = "Benchmark"
+)
+
+var words = strings.Fields("lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore magna aliqua")
+
+// Seed inserts articleCount published articles, one a minute going back from
+// now, under their own category, source and author. Every article has an
+// ADVANCED text and every third a BEGINNER one too.
+func Seed(ctx context.Context, pool *pgxpool.Pool, articleCount int) (categoryId ulid.ULID, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return categoryId, err
+	}
+	defer tx.Rollback(ctx)
+
+	categoryId = ulid.Make()
+	if _, err = tx.Exec(ctx, "INSERT INTO article_categories(id, name) VALUES ($1, $2)", categoryId, CategoryName); err != nil {
+		return categoryId, err
+	}
+
+	sourceId := ulid.Make()
+	if _, err = tx.Exec(ctx, "INSERT INTO sources(id, name) VALUES ($1, $2)", sourceId, Source); err != nil {
+		return categoryId, err
+	}
+
+	authorId := ulid.Make()
+	if _, err = tx.Exec(ctx, "INSERT INTO authors(id, name) VALUES ($1, $2)", authorId, Author); err != nil {
+		return categoryId, err
+	}
+
+	now := time.Now()
+	articleRows := make([][]any, 0, articleCount)
+	textRows := make([][]any, 0, articleCount*2)
+	for i := 0; i < articleCount; i++ {
+		createdAt := now.Add(-time.Duration(i) * time.Minute)
+		articleId := ulid.MustNew(ulid.Timestamp(createdAt), ulid.DefaultEntropy())
+		articleRows = append(articleRows, []any{
+			articleId,
+			categoryId,
+			fmt.Sprintf("%s %d", sentence(6), i),
+			"https://example.com/" + articleId.String(),
+			Source,
+			sourceId,
+			Author,
+			authorId,
+			true,
+			createdAt,
+			createdAt,
+		})
+
+		difficulties := []string{"ADVANCED"}
+		if i%3 == 0 {
+			difficulties = append(difficulties, "BEGINNER")
+		}
+		for _, difficulty := range difficulties {
+			wordCount := 100 + rand.Intn(900)
+			textRows = append(textRows, []any{
+				ulid.Make(),
+				articleId,
+				sentence(wordCount),
+				difficulty,
+				difficulty != "ADVANCED",
+				createdAt,
+				wordCount,
+				(wordCount + 199) / 200,
+			})
+		}
+	}
+
+	if _, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"articles"},
+		[]string{"id", "category_id", "title", "original_url", "source", "source_id", "author", "author_id", "is_published", "created_at", "published_at"},
+		pgx.CopyFromRows(articleRows),
+	); err != nil {
+		return categoryId, err
+	}
+
+	if _, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"article_texts"},
+		[]string{"id", "article_id", "content", "difficulty", "is_adapted", "created_at", "word_count", "reading_time_minutes"},
+		pgx.CopyFromRows(textRows),
+	); err != nil {
+		return categoryId, err
+	}
+
+	if _, err = tx.Exec(ctx, "ANALYZE articles"); err != nil {
+		return categoryId, err
+	}
+	if _, err = tx.Exec(ctx, "ANALYZE article_texts"); err != nil {
+		return categoryId, err
+	}
+
+	return categoryId, tx.Commit(ctx)
+}
+
+// Remove deletes everything Seed inserted.
+func Remove(ctx context.Context, pool *pgxpool.Pool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, "DELETE FROM article_texts WHERE article_id IN (SELECT id FROM articles WHERE source = $1)", Source); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, "DELETE FROM articles WHERE source = $1", Source); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, "DELETE FROM article_categories WHERE name = $1", CategoryName); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, "DELETE FROM sources WHERE name = $1", Source); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, "DELETE FROM authors WHERE name = $1", Author); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// sentence joins wordCount random filler words.
+func sentence(wordCount int) string {
+	s := make([]string, wordCount)
+	for i := range s {
+		s[i] = words[rand.Intn(len(words))]
+	}
+
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,160 @@
+// Command articlebench seeds a large number of articles and measures the
+// article listing endpoint against them. Run it from the repository root with
+// the API running so the .env file can be shared:
+//
+//	go run ./tools/articlebench -n 100000
+//	go run ./tools/articlebench -cleanup
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	stdlog "log"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/db"
+	"github.com/lexica-app/lexicapi/tools/articlebench/fixture"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type scenario struct {
+	name  string
+	query url.Values
+	pages int
+}
+
+func main() {
+	articleCount := flag.Int("n", 100000, "number of articles to seed")
+	runs := flag.Int("runs", 20, "requests per scenario")
+	pageSize := flag.Int("page-size", 20, "page size used by every scenario")
+	baseUrl := flag.String("url", "", "API base url, defaults to localhost on the configured port")
+	skipSeed := flag.Bool("skip-seed", false, "reuse previously seeded articles")
+	cleanup := flag.Bool("cleanup", false, "remove seeded articles and exit")
+	flag.Parse()
+
+	config, err := app.LoadConfig()
+	if err != nil {
+		stdlog.Fatal("Failed to load config:", err)
+	}
+
+	ctx := context.Background()
+	pool := db.CreateConnPool(config.DbDsn)
+	defer pool.Close()
+
+	if *cleanup {
+		if err = fixture.Remove(ctx, pool); err != nil {
+			log.Fatal().Err(err).Msg("Failed to remove seeded articles")
+		}
+		log.Info().Msg("Removed seeded articles")
+		return
+	}
+
+	if !*skipSeed {
+		start := time.Now()
+		if err = fixture.Remove(ctx, pool); err != nil {
+			log.Fatal().Err(err).Msg("Failed to remove seeded articles")
+		}
+		if _, err = fixture.Seed(ctx, pool, *articleCount); err != nil {
+			log.Fatal().Err(err).Msg("Failed to seed articles")
+		}
+		log.Info().Msgf("Seeded %d articles in %s", *articleCount, time.Since(start).Round(time.Millisecond))
+	}
+
+	var categoryId ulid.ULID
+	if err = pool.QueryRow(ctx, "SELECT id FROM article_categories WHERE name = $1 AND deleted_at IS NULL", fixture.CategoryName).Scan(&categoryId); err != nil {
+		log.Fatal().Err(err).Msg("Failed to find benchmark category")
+	}
+
+	if *baseUrl == "" {
+		*baseUrl = "http://localhost:" + config.Port
+	}
+
+	lastMonth := time.Now().AddDate(0, -1, 0).Format("2006-01-02")
+	scenarios := []scenario{
+		{name: "newest", query: url.Values{}},
+		{name: "oldest", query: url.Values{"sort": {"oldest"}}},
+		{name: "title", query: url.Values{"sort": {"title"}}},
+		{name: "popularity", query: url.Values{"sort": {"popularity"}}},
+		{name: "search", query: url.Values{"q": {"dolor"}}},
+		{name: "category", query: url.Values{"category_id": {categoryId.String()}}},
+		{name: "source", query: url.Values{"source": {fixture.Source}}},
+		{name: "difficulty", query: url.Values{"difficulty": {"BEGINNER"}}},
+		{name: "reading time", query: url.Values{"min_reading_time": {"2"}, "max_reading_time": {"3"}}},
+		{name: "published range", query: url.Values{"published_from": {lastMonth}}},
+		{name: "newest, 10 pages", query: url.Values{}, pages: 10},
+		{name: "title, 10 pages", query: url.Values{"sort": {"title"}}, pages: 10},
+	}
+
+	fmt.Printf("%-20s %10s %10s %10s %10s\n", "scenario", "min", "p50", "p95", "max")
+	for _, s := range scenarios {
+		s.query.Set("page_size", fmt.Sprint(*pageSize))
+
+		durations := make([]time.Duration, 0, *runs)
+		for i := 0; i < *runs; i++ {
+			d, err := runScenario(*baseUrl, config.LexicaApiKey, s)
+			if err != nil {
+				log.Fatal().Err(err).Msgf("Failed to run scenario %s", s.name)
+			}
+			durations = append(durations, d)
+		}
+
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		fmt.Printf(
+			"%-20s %10s %10s %10s %10s\n",
+			s.name,
+			durations[0].Round(time.Microsecond),
+			durations[len(durations)/2].Round(time.Microsecond),
+			durations[len(durations)*95/100].Round(time.Microsecond),
+			durations[len(durations)-1].Round(time.Microsecond),
+		)
+	}
+}
+
+// runScenario requests the first page and then follows the cursor, returning
+// how long the whole walk took.
+func runScenario(baseUrl, apiKey string, s scenario) (time.Duration, error) {
+	query := url.Values{}
+	for key, values := range s.query {
+		query[key] = values
+	}
+
+	start := time.Now()
+	for page := 0; page < s.pages || page == 0; page++ {
+		req, err := http.NewRequest(http.MethodGet, baseUrl+"/article?"+query.Encode(), nil)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("X-Lexica-Api-Key", apiKey)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0, err
+		}
+
+		var body struct {
+			Cursor *string `json:"cursor"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		res.Body.Close()
+		if err != nil {
+			return 0, err
+		}
+		if res.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("unexpected status %d", res.StatusCode)
+		}
+
+		if body.Cursor == nil {
+			break
+		}
+		query.Set("cursor", *body.Cursor)
+	}
+
+	return time.Since(start), nil
+}
@@ -0,0 +1,81 @@
+// Command dictimport loads a dictionary dataset into the database, the
+// dataset has one JSON object per line, see dictionary.Import for the format.
+// Run it from the repository root so the .env file can be shared:
+//
+//	go run ./tools/dictimport -file kamus.jsonl
+//	go run ./tools/dictimport -file kamus.jsonl -dry-run
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	stdlog "log"
+	"os"
+	"time"
+
+	"github.com/lexica-app/lexicapi/app"
+	"github.com/lexica-app/lexicapi/app/dictionary"
+	"github.com/lexica-app/lexicapi/db"
+	"github.com/rs/zerolog/log"
+)
+
+// Invalid lines past this are only counted
+const maxReportedLines = 20
+
+func main() {
+	path := flag.String("file", "", "dictionary dataset in JSON Lines")
+	isDryRun := flag.Bool("dry-run", false, "only check the dataset")
+	flag.Parse()
+
+	if *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*path)
+	if err != nil {
+		stdlog.Fatal("Failed to open dataset:", err)
+	}
+	defer f.Close()
+
+	ctx := context.Background()
+	if !*isDryRun {
+		config, err := app.LoadConfig()
+		if err != nil {
+			stdlog.Fatal("Failed to load config:", err)
+		}
+
+		pool := db.CreateConnPool(config.DbDsn)
+		defer pool.Close()
+
+		dictionary.SetPool(pool)
+	}
+
+	start := time.Now()
+	result, err := dictionary.Import(ctx, f, *isDryRun)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to import dictionary")
+	}
+
+	for i, lineErr := range result.Invalid {
+		if i == maxReportedLines {
+			fmt.Printf("... and %d more invalid lines\n", len(result.Invalid)-maxReportedLines)
+			break
+		}
+		fmt.Println(lineErr.Error())
+	}
+
+	if *isDryRun {
+		log.Info().Msgf("Checked dataset in %s: %d valid, %d invalid", time.Since(start).Round(time.Millisecond), result.Valid, len(result.Invalid))
+		return
+	}
+
+	log.Info().Msgf(
+		"Imported dictionary in %s: %d inserted, %d updated, %d invalid",
+		time.Since(start).Round(time.Millisecond),
+		result.Inserted,
+		result.Updated,
+		len(result.Invalid),
+	)
+}